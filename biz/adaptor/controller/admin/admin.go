@@ -10,9 +10,13 @@ import (
 	hertz "github.com/cloudwego/hertz/pkg/protocol/consts"
 	"github.com/xh-polaris/alumni-core_api/biz/application/service"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/activity"
 	"github.com/xh-polaris/alumni-core_api/provider"
 )
 
+// sessionKey 请求上下文中保存当前管理员会话的键
+const sessionKey = "adminSession"
+
 type response struct {
 	Code    int64  `json:"code"`
 	Message string `json:"message"`
@@ -26,7 +30,8 @@ func RequireAuth() app.HandlerFunc {
 			c.Abort()
 			return
 		}
-		if _, err := provider.Get().AdminService.GetSession(ctx); err != nil {
+		session, err := provider.Get().AdminService.GetSession(ctx)
+		if err != nil {
 			status := hertz.StatusInternalServerError
 			switch {
 			case errors.Is(err, service.ErrAdminUnauthorized):
@@ -38,10 +43,31 @@ func RequireAuth() app.HandlerFunc {
 			c.Abort()
 			return
 		}
+		c.Set(sessionKey, session)
 		c.Next(ctx)
 	}
 }
 
+// Require 按路由校验当前管理员是否拥有指定权限，需在 RequireAuth 之后执行
+func Require(permission string) app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		session := currentSession(c)
+		if session == nil || !session.Can(permission) {
+			fail(c, hertz.StatusForbidden, "当前账号无此操作权限")
+			c.Abort()
+			return
+		}
+		c.Next(ctx)
+	}
+}
+
+// currentSession 读取 RequireAuth 保存的当前管理员会话
+func currentSession(c *app.RequestContext) *service.AdminSession {
+	value, _ := c.Get(sessionKey)
+	session, _ := value.(*service.AdminSession)
+	return session
+}
+
 func GetSession(ctx context.Context, c *app.RequestContext) {
 	resp, err := provider.Get().AdminService.GetSession(ctx)
 	write(c, resp, err)
@@ -55,6 +81,11 @@ func ListUsers(ctx context.Context, c *app.RequestContext) {
 		c.Query("keyword"),
 		c.Query("role"),
 		c.Query("status"),
+		service.CompletenessFilter{
+			Min:     queryInt(c, "minCompleteness", 0),
+			Max:     queryInt(c, "maxCompleteness", 0),
+			Missing: c.Query("missing"),
+		},
 	)
 	write(c, resp, err)
 }
@@ -104,6 +135,10 @@ func RestoreUser(ctx context.Context, c *app.RequestContext) {
 	write(c, nil, provider.Get().AdminService.RestoreUser(ctx, c.Param("id")))
 }
 
+func UnlockUser(ctx context.Context, c *app.RequestContext) {
+	write(c, nil, provider.Get().AdminService.UnlockUser(ctx, c.Param("id")))
+}
+
 func ListRegistrations(ctx context.Context, c *app.RequestContext) {
 	resp, err := provider.Get().AdminService.ListRegistrations(
 		ctx,
@@ -148,6 +183,321 @@ func CancelCheckInRegistration(ctx context.Context, c *app.RequestContext) {
 	write(c, nil, provider.Get().AdminService.SetRegistrationCheckIn(ctx, c.Param("id"), false))
 }
 
+func SetActivityTickets(ctx context.Context, c *app.RequestContext) {
+	var req struct {
+		Tickets []activity.Ticket `json:"tickets"`
+	}
+	if err := c.BindAndValidate(&req); err != nil {
+		fail(c, hertz.StatusBadRequest, err.Error())
+		return
+	}
+	resp, err := provider.Get().AdminService.SetActivityTickets(ctx, c.Param("id"), req.Tickets)
+	write(c, resp, err)
+}
+
+func SetActivityOrganizations(ctx context.Context, c *app.RequestContext) {
+	var req struct {
+		OrganizationIDs []string `json:"organizationIds"`
+	}
+	if err := c.BindAndValidate(&req); err != nil {
+		fail(c, hertz.StatusBadRequest, err.Error())
+		return
+	}
+	write(c, nil, provider.Get().OrganizationService.SetActivityOrganizations(ctx, c.Param("id"), req.OrganizationIDs))
+}
+
+func SetActivityGroup(ctx context.Context, c *app.RequestContext) {
+	var req struct {
+		GroupID string `json:"groupId"`
+	}
+	if err := c.BindAndValidate(&req); err != nil {
+		fail(c, hertz.StatusBadRequest, err.Error())
+		return
+	}
+	write(c, nil, provider.Get().GroupService.SetActivityGroup(ctx, c.Param("id"), req.GroupID))
+}
+
+func ListOrganizations(ctx context.Context, c *app.RequestContext) {
+	resp, err := provider.Get().OrganizationService.ListAdminOrganizations(
+		ctx,
+		queryInt(c, "page", 1),
+		queryInt(c, "pageSize", 20),
+		c.Query("keyword"),
+		c.Query("status"),
+	)
+	write(c, resp, err)
+}
+
+func GetOrganization(ctx context.Context, c *app.RequestContext) {
+	resp, err := provider.Get().OrganizationService.GetAdminOrganization(ctx, c.Param("id"))
+	write(c, resp, err)
+}
+
+func CreateOrganization(ctx context.Context, c *app.RequestContext) {
+	var req service.AdminOrganizationInput
+	if err := c.BindAndValidate(&req); err != nil {
+		fail(c, hertz.StatusBadRequest, err.Error())
+		return
+	}
+	resp, err := provider.Get().OrganizationService.CreateOrganization(ctx, req)
+	write(c, resp, err)
+}
+
+func UpdateOrganization(ctx context.Context, c *app.RequestContext) {
+	var req service.AdminOrganizationInput
+	if err := c.BindAndValidate(&req); err != nil {
+		fail(c, hertz.StatusBadRequest, err.Error())
+		return
+	}
+	resp, err := provider.Get().OrganizationService.UpdateOrganization(ctx, c.Param("id"), req)
+	write(c, resp, err)
+}
+
+func DeleteOrganization(ctx context.Context, c *app.RequestContext) {
+	write(c, nil, provider.Get().OrganizationService.DeleteOrganization(ctx, c.Param("id")))
+}
+
+func RestoreOrganization(ctx context.Context, c *app.RequestContext) {
+	write(c, nil, provider.Get().OrganizationService.RestoreOrganization(ctx, c.Param("id")))
+}
+
+func SetOrganizationMembers(ctx context.Context, c *app.RequestContext) {
+	var req struct {
+		Members []service.AdminOrganizationMemberInput `json:"members"`
+	}
+	if err := c.BindAndValidate(&req); err != nil {
+		fail(c, hertz.StatusBadRequest, err.Error())
+		return
+	}
+	resp, err := provider.Get().OrganizationService.SetOrganizationMembers(ctx, c.Param("id"), req.Members)
+	write(c, resp, err)
+}
+
+func ListOrders(ctx context.Context, c *app.RequestContext) {
+	resp, err := provider.Get().OrderService.ListAdminOrders(
+		ctx,
+		queryInt(c, "page", 1),
+		queryInt(c, "pageSize", 20),
+		c.Query("activityId"),
+		c.Query("status"),
+	)
+	write(c, resp, err)
+}
+
+func RefundOrder(ctx context.Context, c *app.RequestContext) {
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.BindAndValidate(&req); err != nil {
+		fail(c, hertz.StatusBadRequest, err.Error())
+		return
+	}
+	write(c, nil, provider.Get().OrderService.RefundOrder(ctx, c.Param("id"), req.Reason))
+}
+
+func ListVerifications(ctx context.Context, c *app.RequestContext) {
+	resp, err := provider.Get().VerificationService.ListAdminVerifications(
+		ctx,
+		queryInt(c, "page", 1),
+		queryInt(c, "pageSize", 20),
+		c.Query("status"),
+	)
+	write(c, resp, err)
+}
+
+func ApproveVerification(ctx context.Context, c *app.RequestContext) {
+	write(c, nil, provider.Get().VerificationService.ApproveVerification(ctx, c.Param("id")))
+}
+
+func RejectVerification(ctx context.Context, c *app.RequestContext) {
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.BindAndValidate(&req); err != nil {
+		fail(c, hertz.StatusBadRequest, err.Error())
+		return
+	}
+	write(c, nil, provider.Get().VerificationService.RejectVerification(ctx, c.Param("id"), req.Reason))
+}
+
+func ListPermissions(ctx context.Context, c *app.RequestContext) {
+	write(c, provider.Get().RoleService.ListPermissions(ctx), nil)
+}
+
+func ListRoles(ctx context.Context, c *app.RequestContext) {
+	resp, err := provider.Get().RoleService.ListRoles(ctx)
+	write(c, resp, err)
+}
+
+func CreateRole(ctx context.Context, c *app.RequestContext) {
+	var req service.AdminRoleInput
+	if err := c.BindAndValidate(&req); err != nil {
+		fail(c, hertz.StatusBadRequest, err.Error())
+		return
+	}
+	resp, err := provider.Get().RoleService.CreateRole(ctx, currentSession(c), req)
+	write(c, resp, err)
+}
+
+func UpdateRole(ctx context.Context, c *app.RequestContext) {
+	var req service.AdminRoleInput
+	if err := c.BindAndValidate(&req); err != nil {
+		fail(c, hertz.StatusBadRequest, err.Error())
+		return
+	}
+	resp, err := provider.Get().RoleService.UpdateRole(ctx, currentSession(c), c.Param("key"), req)
+	write(c, resp, err)
+}
+
+func DeleteRole(ctx context.Context, c *app.RequestContext) {
+	write(c, nil, provider.Get().RoleService.DeleteRole(ctx, c.Param("key")))
+}
+
+func SetUserAdminRoles(ctx context.Context, c *app.RequestContext) {
+	var req struct {
+		Roles []string `json:"roles"`
+	}
+	if err := c.BindAndValidate(&req); err != nil {
+		fail(c, hertz.StatusBadRequest, err.Error())
+		return
+	}
+	write(c, nil, provider.Get().RoleService.SetUserAdminRoles(ctx, currentSession(c), c.Param("id"), req.Roles))
+}
+
+func PreviewMergeUsers(ctx context.Context, c *app.RequestContext) {
+	resp, err := provider.Get().MergeService.PreviewMerge(ctx, c.Query("sourceId"), c.Query("targetId"))
+	write(c, resp, err)
+}
+
+func NotifyIncompleteProfiles(ctx context.Context, c *app.RequestContext) {
+	var req service.AdminProfileNotice
+	if err := c.BindAndValidate(&req); err != nil {
+		fail(c, hertz.StatusBadRequest, err.Error())
+		return
+	}
+	resp, err := provider.Get().NotificationService.NotifyIncompleteProfiles(ctx, req)
+	write(c, resp, err)
+}
+
+func MergeUsers(ctx context.Context, c *app.RequestContext) {
+	var req service.AdminMergeInput
+	if err := c.BindAndValidate(&req); err != nil {
+		fail(c, hertz.StatusBadRequest, err.Error())
+		return
+	}
+	resp, err := provider.Get().MergeService.MergeUsers(ctx, req)
+	write(c, resp, err)
+}
+
+func ListAudits(ctx context.Context, c *app.RequestContext) {
+	resp, err := provider.Get().AuditService.ListAudits(ctx, service.AdminAuditQuery{
+		Page:       queryInt(c, "page", 1),
+		PageSize:   queryInt(c, "pageSize", 20),
+		ActorID:    c.Query("actorId"),
+		TargetType: c.Query("targetType"),
+		TargetID:   c.Query("targetId"),
+		From:       queryInt(c, "from", 0),
+		To:         queryInt(c, "to", 0),
+	})
+	write(c, resp, err)
+}
+
+func ListGroups(ctx context.Context, c *app.RequestContext) {
+	resp, err := provider.Get().GroupService.ListAdminGroups(
+		ctx,
+		queryInt(c, "page", 1),
+		queryInt(c, "pageSize", 20),
+		c.Query("keyword"),
+		c.Query("category"),
+		c.Query("status"),
+	)
+	write(c, resp, err)
+}
+
+func DeleteGroup(ctx context.Context, c *app.RequestContext) {
+	write(c, nil, provider.Get().GroupService.DeleteGroup(ctx, c.Param("id")))
+}
+
+func RestoreGroup(ctx context.Context, c *app.RequestContext) {
+	write(c, nil, provider.Get().GroupService.RestoreGroup(ctx, c.Param("id")))
+}
+
+func ListJobs(ctx context.Context, c *app.RequestContext) {
+	resp, err := provider.Get().JobService.ListAdminJobs(
+		ctx,
+		queryInt(c, "page", 1),
+		queryInt(c, "pageSize", 20),
+		c.Query("status"),
+	)
+	write(c, resp, err)
+}
+
+func ApproveJob(ctx context.Context, c *app.RequestContext) {
+	write(c, nil, provider.Get().JobService.ApproveJob(ctx, c.Param("id")))
+}
+
+func RejectJob(ctx context.Context, c *app.RequestContext) {
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.BindAndValidate(&req); err != nil {
+		fail(c, hertz.StatusBadRequest, err.Error())
+		return
+	}
+	write(c, nil, provider.Get().JobService.RejectJob(ctx, c.Param("id"), req.Reason))
+}
+
+func ListDictionary(ctx context.Context, c *app.RequestContext) {
+	resp, err := provider.Get().DictionaryService.ListAdminDictionary(
+		ctx,
+		c.Param("kind"),
+		queryInt(c, "page", 1),
+		queryInt(c, "pageSize", 20),
+		c.Query("keyword"),
+		c.Query("parentId"),
+	)
+	write(c, resp, err)
+}
+
+func CreateDictionaryEntry(ctx context.Context, c *app.RequestContext) {
+	var req service.AdminDictionaryInput
+	if err := c.BindAndValidate(&req); err != nil {
+		fail(c, hertz.StatusBadRequest, err.Error())
+		return
+	}
+	resp, err := provider.Get().DictionaryService.CreateDictionaryEntry(ctx, c.Param("kind"), req)
+	write(c, resp, err)
+}
+
+func UpdateDictionaryEntry(ctx context.Context, c *app.RequestContext) {
+	var req service.AdminDictionaryInput
+	if err := c.BindAndValidate(&req); err != nil {
+		fail(c, hertz.StatusBadRequest, err.Error())
+		return
+	}
+	resp, err := provider.Get().DictionaryService.UpdateDictionaryEntry(ctx, c.Param("kind"), c.Param("id"), req)
+	write(c, resp, err)
+}
+
+func DeleteDictionaryEntry(ctx context.Context, c *app.RequestContext) {
+	write(c, nil, provider.Get().DictionaryService.DeleteDictionaryEntry(ctx, c.Param("kind"), c.Param("id")))
+}
+
+func MigrateDictionary(ctx context.Context, c *app.RequestContext) {
+	resp, err := provider.Get().DictionaryService.MigrateDictionary(ctx, c.Param("kind"))
+	write(c, resp, err)
+}
+
+func RegionStats(ctx context.Context, c *app.RequestContext) {
+	resp, err := provider.Get().RegionService.AdminRegionStats(ctx, c.Query("code"))
+	write(c, resp, err)
+}
+
+func MigrateHometowns(ctx context.Context, c *app.RequestContext) {
+	resp, err := provider.Get().RegionService.MigrateHometowns(ctx)
+	write(c, resp, err)
+}
+
 func ListArticles(ctx context.Context, c *app.RequestContext) {
 	resp, err := provider.Get().AdminService.ListArticles(
 		ctx,
@@ -212,7 +562,7 @@ func write(c *app.RequestContext, data any, err error) {
 		fail(c, hertz.StatusForbidden, err.Error())
 	case errors.Is(err, service.ErrAdminBadRequest):
 		fail(c, hertz.StatusBadRequest, err.Error())
-	case err == consts.ErrNotFound || err == consts.ErrInvalidObjectId:
+	case errors.Is(err, service.ErrAdminNotFound), err == consts.ErrNotFound || err == consts.ErrInvalidObjectId:
 		fail(c, hertz.StatusNotFound, "资源不存在")
 	default:
 		fail(c, hertz.StatusInternalServerError, "服务异常")
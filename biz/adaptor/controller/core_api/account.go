@@ -0,0 +1,62 @@
+package core_api
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	core_api "github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/provider"
+)
+
+// ExportUserData .
+// @router /user/export [POST]
+func ExportUserData(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.ExportUserDataReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.AccountService.ExportUserData(ctx, &req)
+	if err == nil {
+		c.Header("Content-Disposition", `attachment; filename="alumni-data.json"`)
+	}
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// DeleteAccount .
+// @router /user/delete [POST]
+func DeleteAccount(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.DeleteAccountReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.AccountService.DeleteAccount(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// CancelAccountDeletion .
+// @router /user/cancel_delete [POST]
+func CancelAccountDeletion(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.CancelAccountDeletionReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.AccountService.CancelAccountDeletion(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
@@ -144,7 +144,7 @@ func UpdateActivity(ctx context.Context, c *app.RequestContext) {
 // @router /activity/get_many [POST]
 func GetActivities(ctx context.Context, c *app.RequestContext) {
 	var err error
-	var req core_api.GetActivitiesReq
+	var req core_api.ListActivitiesReq
 	err = c.BindAndValidate(&req)
 	if err != nil {
 		c.String(consts.StatusBadRequest, err.Error())
@@ -0,0 +1,27 @@
+package core_api
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	core_api "github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/provider"
+)
+
+// SearchDictionary .
+// @router /dictionary/search [POST]
+func SearchDictionary(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.SearchDictionaryReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.DictionaryService.SearchDictionary(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
@@ -0,0 +1,59 @@
+package core_api
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	core_api "github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/provider"
+)
+
+// SearchDirectory .
+// @router /directory/search [POST]
+func SearchDirectory(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.SearchDirectoryReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.DirectoryService.SearchDirectory(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// GetDirectorySetting .
+// @router /directory/get_setting [POST]
+func GetDirectorySetting(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetDirectorySettingReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.DirectoryService.GetDirectorySetting(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// UpdateDirectorySetting .
+// @router /directory/update_setting [POST]
+func UpdateDirectorySetting(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.DirectorySetting
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.DirectoryService.UpdateDirectorySetting(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
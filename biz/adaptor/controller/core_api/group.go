@@ -0,0 +1,235 @@
+package core_api
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	core_api "github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/provider"
+)
+
+// CreateGroup .
+// @router /group/create [POST]
+func CreateGroup(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.CreateGroupReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.GroupService.CreateGroup(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// UpdateGroup .
+// @router /group/update [POST]
+func UpdateGroup(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.UpdateGroupReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.GroupService.UpdateGroup(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// SearchGroups .
+// @router /group/search [POST]
+func SearchGroups(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.SearchGroupsReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.GroupService.SearchGroups(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// GetGroup .
+// @router /group/get [POST]
+func GetGroup(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetGroupReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.GroupService.GetGroup(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// JoinGroup .
+// @router /group/join [POST]
+func JoinGroup(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.JoinGroupReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.GroupService.JoinGroup(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// LeaveGroup .
+// @router /group/leave [POST]
+func LeaveGroup(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.LeaveGroupReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.GroupService.LeaveGroup(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// InviteGroupMember .
+// @router /group/invite [POST]
+func InviteGroupMember(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.InviteGroupMemberReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.GroupService.InviteGroupMember(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// ReviewGroupMember .
+// @router /group/review_member [POST]
+func ReviewGroupMember(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.ReviewGroupMemberReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.GroupService.ReviewGroupMember(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// SetGroupMemberRole .
+// @router /group/set_member_role [POST]
+func SetGroupMemberRole(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.SetGroupMemberRoleReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.GroupService.SetGroupMemberRole(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// RemoveGroupMember .
+// @router /group/remove_member [POST]
+func RemoveGroupMember(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.RemoveGroupMemberReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.GroupService.RemoveGroupMember(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// GetGroupMembers .
+// @router /group/get_members [POST]
+func GetGroupMembers(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetGroupMembersReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.GroupService.GetGroupMembers(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// CreateGroupAnnouncement .
+// @router /group/create_announcement [POST]
+func CreateGroupAnnouncement(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.CreateGroupAnnouncementReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.GroupService.CreateGroupAnnouncement(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// GetGroupAnnouncements .
+// @router /group/get_announcements [POST]
+func GetGroupAnnouncements(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetGroupAnnouncementsReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.GroupService.GetGroupAnnouncements(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// DeleteGroupAnnouncement .
+// @router /group/delete_announcement [POST]
+func DeleteGroupAnnouncement(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.DeleteGroupAnnouncementReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.GroupService.DeleteGroupAnnouncement(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
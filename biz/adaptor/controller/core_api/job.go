@@ -0,0 +1,139 @@
+package core_api
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	core_api "github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/provider"
+)
+
+// CreateJob .
+// @router /job/create [POST]
+func CreateJob(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.CreateJobReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.JobService.CreateJob(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// SearchJobs .
+// @router /job/search [POST]
+func SearchJobs(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.SearchJobsReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.JobService.SearchJobs(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// GetJob .
+// @router /job/get [POST]
+func GetJob(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetJobReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.JobService.GetJob(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// GetMyJobs .
+// @router /job/get_mine [POST]
+func GetMyJobs(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetMyJobsReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.JobService.GetMyJobs(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// CloseJob .
+// @router /job/close [POST]
+func CloseJob(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.CloseJobReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.JobService.CloseJob(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// ApplyJob .
+// @router /job/apply [POST]
+func ApplyJob(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.ApplyJobReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.JobService.ApplyJob(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// GetJobApplications .
+// @router /job/get_applications [POST]
+func GetJobApplications(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetJobApplicationsReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.JobService.GetJobApplications(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// GetJobVocabulary .
+// @router /job/get_vocabulary [POST]
+func GetJobVocabulary(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetJobVocabularyReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.JobService.GetJobVocabulary(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
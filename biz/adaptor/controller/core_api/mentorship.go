@@ -0,0 +1,123 @@
+package core_api
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	core_api "github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/provider"
+)
+
+// GetMentorSetting .
+// @router /mentorship/get_setting [POST]
+func GetMentorSetting(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetMentorSettingReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.MentorshipService.GetMentorSetting(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// UpdateMentorSetting .
+// @router /mentorship/update_setting [POST]
+func UpdateMentorSetting(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.MentorSetting
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.MentorshipService.UpdateMentorSetting(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// SuggestMentors .
+// @router /mentorship/suggest [POST]
+func SuggestMentors(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.SuggestMentorsReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.MentorshipService.SuggestMentors(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// CreateMentorship .
+// @router /mentorship/create [POST]
+func CreateMentorship(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.CreateMentorshipReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.MentorshipService.CreateMentorship(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// GetMentorships .
+// @router /mentorship/get_many [POST]
+func GetMentorships(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetMentorshipsReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.MentorshipService.GetMentorships(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// RespondMentorship .
+// @router /mentorship/respond [POST]
+func RespondMentorship(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.RespondMentorshipReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.MentorshipService.RespondMentorship(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// CompleteMentorship .
+// @router /mentorship/complete [POST]
+func CompleteMentorship(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.CompleteMentorshipReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.MentorshipService.CompleteMentorship(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
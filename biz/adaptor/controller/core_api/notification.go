@@ -0,0 +1,43 @@
+package core_api
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	core_api "github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/provider"
+)
+
+// GetNotifications .
+// @router /notification/get_many [POST]
+func GetNotifications(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetNotificationsReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.NotificationService.GetNotifications(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// ReadNotifications .
+// @router /notification/read [POST]
+func ReadNotifications(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.ReadNotificationsReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.NotificationService.ReadNotifications(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
@@ -0,0 +1,94 @@
+package core_api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	core_api "github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util/log"
+	"github.com/xh-polaris/alumni-core_api/provider"
+)
+
+// ListTickets .
+// @router /activity/get_tickets [POST]
+func ListTickets(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.ListTicketsReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.OrderService.ListTickets(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// CreateOrder .
+// @router /order/create [POST]
+func CreateOrder(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.CreateOrderReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.OrderService.CreateOrder(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// GetOrder .
+// @router /order/get [POST]
+func GetOrder(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetOrderReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.OrderService.GetOrder(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// GetOrders .
+// @router /order/get_many [POST]
+func GetOrders(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetOrdersReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.OrderService.GetOrders(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// PayNotify 支付渠道回调，按渠道约定的格式应答，不走统一响应
+// @router /order/pay_notify [POST]
+func PayNotify(ctx context.Context, c *app.RequestContext) {
+	header := http.Header{}
+	c.Request.Header.VisitAll(func(key, value []byte) {
+		header.Add(string(key), string(value))
+	})
+
+	p := provider.Get()
+	if err := p.OrderService.HandlePayNotify(ctx, header, c.Request.Body()); err != nil {
+		log.CtxError(ctx, "handle pay notify fail, err=%v", err)
+		c.JSON(consts.StatusInternalServerError, map[string]string{"code": "FAIL", "message": err.Error()})
+		return
+	}
+	c.JSON(consts.StatusOK, map[string]string{"code": "SUCCESS", "message": "成功"})
+}
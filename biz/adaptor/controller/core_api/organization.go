@@ -0,0 +1,91 @@
+package core_api
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	core_api "github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/provider"
+)
+
+// GetOrganizations .
+// @router /organization/get_many [POST]
+func GetOrganizations(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetOrganizationsReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.OrganizationService.GetOrganizations(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// GetOrganization .
+// @router /organization/get [POST]
+func GetOrganization(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetOrganizationReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.OrganizationService.GetOrganization(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// GetOrganizationActivities .
+// @router /organization/get_activities [POST]
+func GetOrganizationActivities(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetOrganizationActivitiesReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.OrganizationService.GetOrganizationActivities(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// CreateOrganizationActivity .
+// @router /organization/create_activity [POST]
+func CreateOrganizationActivity(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.CreateOrganizationActivityReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.ActivityService.CreateOrganizationActivity(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// GetActivityOrganizations .
+// @router /activity/get_organizations [POST]
+func GetActivityOrganizations(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetActivityOrganizationsReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.OrganizationService.GetActivityOrganizations(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
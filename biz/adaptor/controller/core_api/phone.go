@@ -0,0 +1,43 @@
+package core_api
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	core_api "github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/provider"
+)
+
+// SendPhoneChangeCode .
+// @router /user/phone/send_code [POST]
+func SendPhoneChangeCode(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.SendPhoneChangeCodeReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.UserService.SendPhoneChangeCode(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// ChangePhone .
+// @router /user/phone/change [POST]
+func ChangePhone(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.ChangePhoneReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.UserService.ChangePhone(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
@@ -0,0 +1,43 @@
+package core_api
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	core_api "github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/provider"
+)
+
+// GetPrivacySetting .
+// @router /user/get_privacy [POST]
+func GetPrivacySetting(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetPrivacySettingReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.PrivacyService.GetPrivacySetting(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// UpdatePrivacySetting .
+// @router /user/update_privacy [POST]
+func UpdatePrivacySetting(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.PrivacySetting
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.PrivacyService.UpdatePrivacySetting(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
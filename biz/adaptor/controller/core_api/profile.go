@@ -0,0 +1,27 @@
+package core_api
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	core_api "github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/provider"
+)
+
+// GetUserProfile .
+// @router /user/:id/profile [GET]
+func GetUserProfile(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetUserProfileReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.UserService.GetUserProfile(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
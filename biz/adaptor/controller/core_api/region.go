@@ -0,0 +1,75 @@
+package core_api
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	core_api "github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/provider"
+)
+
+// GetRegions .
+// @router /region/get_children [POST]
+func GetRegions(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetRegionsReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.RegionService.GetRegions(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// SearchRegions .
+// @router /region/search [POST]
+func SearchRegions(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.SearchRegionsReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.RegionService.SearchRegions(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// GetRegionStats .
+// @router /region/get_stats [POST]
+func GetRegionStats(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetRegionStatsReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.RegionService.GetRegionStats(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// UpdateHometown .
+// @router /user/update_hometown [POST]
+func UpdateHometown(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.UpdateHometownReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.RegionService.UpdateHometown(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
@@ -0,0 +1,43 @@
+package core_api
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	core_api "github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/provider"
+)
+
+// RefreshSession .
+// @router /session/refresh [POST]
+func RefreshSession(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.RefreshSessionReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.SessionService.RefreshSession(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// Logout .
+// @router /session/logout [POST]
+func Logout(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.LogoutReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.SessionService.Logout(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
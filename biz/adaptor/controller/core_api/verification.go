@@ -0,0 +1,43 @@
+package core_api
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	core_api "github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/provider"
+)
+
+// SubmitVerification .
+// @router /verification/submit [POST]
+func SubmitVerification(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.SubmitVerificationReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.VerificationService.SubmitVerification(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// GetVerifications .
+// @router /verification/get_many [POST]
+func GetVerifications(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.GetVerificationsReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.VerificationService.GetVerifications(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
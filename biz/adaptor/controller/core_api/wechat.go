@@ -0,0 +1,43 @@
+package core_api
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	core_api "github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/provider"
+)
+
+// WxLogin .
+// @router /user/wx_login [POST]
+func WxLogin(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.WxLoginReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.UserService.WxLogin(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
+
+// WxBindPhone .
+// @router /user/wx_bind_phone [POST]
+func WxBindPhone(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req core_api.WxBindPhoneReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := provider.Get()
+	resp, err := p.UserService.WxBindPhone(ctx, &req)
+	adaptor.PostProcess(ctx, c, &req, resp, err)
+}
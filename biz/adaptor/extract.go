@@ -3,9 +3,11 @@ package adaptor
 import (
 	"context"
 	"errors"
+	"net"
 
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/jwks"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util/log"
 
@@ -15,7 +17,26 @@ import (
 	"github.com/xh-polaris/service-idl-gen-go/kitex_gen/basic"
 )
 
-const hertzContext = "hertz_context"
+const (
+	hertzContext = "hertz_context"
+	userMetaKey  = "userMeta"
+)
+
+// keyfunc 按令牌头部选择验签公钥，由 jwks.KeySet 提供
+var keyfunc jwt.Keyfunc
+
+// SetKeyfunc 注入验签公钥，未注入时所有令牌都视为无效
+func SetKeyfunc(f jwt.Keyfunc) {
+	keyfunc = f
+}
+
+// sessionValidator 校验令牌对应的会话是否仍然有效，issuedAt 为令牌签发时间，未设置时不做校验
+var sessionValidator func(ctx context.Context, user *basic.UserMeta, issuedAt int64) bool
+
+// SetSessionValidator 注入会话校验，会话被吊销或用户被停用后，未过期的令牌也视为未登录
+func SetSessionValidator(validator func(ctx context.Context, user *basic.UserMeta, issuedAt int64) bool) {
+	sessionValidator = validator
+}
 
 func InjectContext(ctx context.Context, c *app.RequestContext) context.Context {
 	return context.WithValue(ctx, hertzContext, c)
@@ -34,6 +55,9 @@ func ExtractMeta(ctx context.Context) (*basic.UserMeta, *basic.Extra) {
 }
 
 func IsDevModeRequest(ctx context.Context) bool {
+	if cfg := config.GetConfig(); cfg == nil || !cfg.MockAuthEnabled() {
+		return false
+	}
 	c, err := ExtractContext(ctx)
 	if err != nil {
 		return false
@@ -53,6 +77,13 @@ func ExtractUserMeta(ctx context.Context) (user *basic.UserMeta) {
 	if err != nil {
 		return
 	}
+	// 同一请求内只解析和校验一次
+	if cached, ok := c.Get(userMetaKey); ok {
+		return cached.(*basic.UserMeta)
+	}
+	defer func() {
+		c.Set(userMetaKey, user)
+	}()
 	tokenString := c.GetHeader("Authorization")
 	if IsDevModeRequest(ctx) && string(tokenString) == consts.DevMockAccessToken {
 		user.UserId = consts.DevMockUserID
@@ -62,9 +93,11 @@ func ExtractUserMeta(ctx context.Context) (user *basic.UserMeta) {
 		user.IsLogin = true
 		return
 	}
-	token, err := jwt.Parse(string(tokenString), func(_ *jwt.Token) (interface{}, error) {
-		return jwt.ParseECPublicKeyFromPEM([]byte(config.GetConfig().Auth.PublicKey))
-	})
+	if keyfunc == nil {
+		err = errors.New("keyfunc is not set")
+		return
+	}
+	token, err := jwt.Parse(string(tokenString), keyfunc, jwt.WithValidMethods([]string{jwks.Algorithm}))
 	if err != nil {
 		return
 	}
@@ -89,6 +122,19 @@ func ExtractUserMeta(ctx context.Context) (user *basic.UserMeta) {
 	if user.SessionDeviceId == "" {
 		user.SessionDeviceId = user.DeviceId
 	}
+	if sessionValidator != nil {
+		var issuedAt int64
+		if claims, ok := token.Claims.(jwt.MapClaims); ok {
+			if iat, ok := claims["iat"].(float64); ok {
+				issuedAt = int64(iat)
+			}
+		}
+		if !sessionValidator(ctx, user, issuedAt) {
+			err = errors.New("session is revoked")
+			user = new(basic.UserMeta)
+			return
+		}
+	}
 	log.CtxInfo(ctx, "userMeta=%s", util.JSONF(user))
 	return
 }
@@ -105,11 +151,39 @@ func ExtractExtra(ctx context.Context) (extra *basic.Extra) {
 	if err != nil {
 		return
 	}
-	extra.ClientIP = c.ClientIP()
 	err = c.Bind(extra)
+	// 客户端 IP 用于限流与审计，不能由请求参数覆盖
+	extra.ClientIP = c.ClientIP()
 	if err != nil {
 		return
 	}
 	log.CtxInfo(ctx, "extra=%s", util.JSONF(extra))
 	return
 }
+
+// ClientIPFunc 只有远端地址属于 trustedProxies 时才采信 X-Forwarded-For、X-Real-IP，否则使用远端地址，
+// 避免客户端伪造请求头绕过按 IP 的限流；无法解析的 CIDR 会被忽略
+func ClientIPFunc(trustedProxies []string) app.ClientIP {
+	cidrs := make([]*net.IPNet, 0, len(trustedProxies))
+	for _, proxy := range trustedProxies {
+		_, cidr, err := net.ParseCIDR(proxy)
+		if err != nil {
+			log.Error("invalid trusted proxy %s, err=%v", proxy, err)
+			continue
+		}
+		cidrs = append(cidrs, cidr)
+	}
+	return app.ClientIPWithOption(app.ClientIPOptions{
+		RemoteIPHeaders: []string{"X-Forwarded-For", "X-Real-IP"},
+		TrustedCIDRs:    cidrs,
+	})
+}
+
+// ExtractCaptchaTicket 读取前端完成人机验证后携带的 ticket
+func ExtractCaptchaTicket(ctx context.Context) string {
+	c, err := ExtractContext(ctx)
+	if err != nil {
+		return ""
+	}
+	return string(c.GetHeader(consts.CaptchaTicketHeader))
+}
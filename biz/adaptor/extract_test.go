@@ -0,0 +1,59 @@
+package adaptor
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/test/mock"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/cloudwego/hertz/pkg/network"
+)
+
+// remoteConn 固定远端地址的连接，模拟直连或经反向代理转发的请求
+type remoteConn struct {
+	network.Conn
+	addr net.Addr
+}
+
+func (c remoteConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+func newRequest(remoteIP string, trustedProxies []string, body string, headers ...ut.Header) context.Context {
+	headers = append(headers, ut.Header{Key: "Content-Type", Value: "application/json"})
+	c := ut.CreateUtRequestContext("POST", "/sts/send_verify_code", &ut.Body{Body: strings.NewReader(body), Len: len(body)}, headers...)
+	c.SetConn(remoteConn{Conn: mock.NewConn(""), addr: &net.TCPAddr{IP: net.ParseIP(remoteIP), Port: 40000}})
+	c.SetClientIPFunc(ClientIPFunc(trustedProxies))
+	return InjectContext(context.Background(), c)
+}
+
+func TestExtractExtraIgnoresSpoofedClientIP(t *testing.T) {
+	ctx := newRequest("203.0.113.7", nil, `{"clientIP":"198.51.100.1"}`,
+		ut.Header{Key: "X-Forwarded-For", Value: "198.51.100.2"},
+		ut.Header{Key: "X-Real-IP", Value: "198.51.100.3"},
+	)
+	if got := ExtractExtra(ctx).GetClientIP(); got != "203.0.113.7" {
+		t.Fatalf("client ip = %s, want remote address 203.0.113.7", got)
+	}
+}
+
+func TestExtractExtraTrustsConfiguredProxy(t *testing.T) {
+	ctx := newRequest("10.0.0.2", []string{"10.0.0.0/8"}, `{"clientIP":"198.51.100.1"}`,
+		ut.Header{Key: "X-Forwarded-For", Value: "198.51.100.2, 10.0.0.3"},
+	)
+	if got := ExtractExtra(ctx).GetClientIP(); got != "198.51.100.2" {
+		t.Fatalf("client ip = %s, want forwarded address 198.51.100.2", got)
+	}
+}
+
+func TestClientIPFuncIgnoresHeadersFromUntrustedPeer(t *testing.T) {
+	c := app.NewContext(0)
+	c.SetConn(remoteConn{Conn: mock.NewConn(""), addr: &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 40000}})
+	c.Request.Header.Set("X-Forwarded-For", "198.51.100.2")
+	if got := ClientIPFunc([]string{"10.0.0.0/8", "not-a-cidr"})(c); got != "203.0.113.7" {
+		t.Fatalf("client ip = %s, want remote address 203.0.113.7", got)
+	}
+}
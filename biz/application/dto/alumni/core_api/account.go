@@ -0,0 +1,122 @@
+// plain (non-generated) types for personal data export and account deletion
+
+package core_api
+
+type ExportUserDataReq struct{}
+
+// UserDataExport 平台保存的当前用户全部个人信息副本
+type UserDataExport struct {
+	ExportTime    int64                 `json:"exportTime"`
+	Profile       *ExportProfile        `json:"profile"`
+	Registrations []*ExportRegistration `json:"registrations"`
+	Orders        []*ExportOrder        `json:"orders"`
+	Verifications []*Verification       `json:"verifications"`
+	Organizations []*ExportMembership   `json:"organizations"`
+	Mentor        *MentorSetting        `json:"mentor"`
+	Mentorships   []*ExportMentorship   `json:"mentorships"` // 作为导师或学员的指导申请
+	Jobs          []*ExportJob          `json:"jobs"`        // 发布的招聘信息
+	Applications  []*ExportApplication  `json:"applications"`
+	Groups        []*ExportGroupMember  `json:"groups"`
+	Announcements []*ExportAnnouncement `json:"announcements"` // 发布的群组公告
+	Files         []string              `json:"files"`         // 上传过的文件地址，包括头像与认证材料
+}
+
+type ExportProfile struct {
+	Id string `json:"id"`
+	*GetUserInfoResp
+	Role       string            `json:"role"`
+	Privacy    map[string]string `json:"privacy"` // 各字段分组的可见范围
+	Listed     bool              `json:"listed"`  // 是否出现在校友通讯录中
+	CreateTime int64             `json:"createTime"`
+	UpdateTime int64             `json:"updateTime"`
+}
+
+type ExportRegistration struct {
+	Id          string `json:"id"`
+	ActivityId  string `json:"activityId"`
+	Name        string `json:"name"`
+	Phone       string `json:"phone"`
+	CheckIn     bool   `json:"checkIn"`
+	CheckInTime int64  `json:"checkInTime"`
+	OrderId     string `json:"orderId"`
+	PayStatus   string `json:"payStatus"`
+	CreateTime  int64  `json:"createTime"`
+}
+
+type ExportOrder struct {
+	Id         string `json:"id"`
+	ActivityId string `json:"activityId"`
+	TicketName string `json:"ticketName"`
+	Quantity   int64  `json:"quantity"`
+	Amount     int64  `json:"amount"` // 单位：分
+	Status     string `json:"status"`
+	PaidTime   int64  `json:"paidTime"`
+	RefundTime int64  `json:"refundTime"`
+	CreateTime int64  `json:"createTime"`
+}
+
+type ExportMembership struct {
+	OrganizationId string `json:"organizationId"`
+	Name           string `json:"name"`
+	Role           string `json:"role"`
+	JoinTime       int64  `json:"joinTime"`
+}
+
+type ExportMentorship struct {
+	Id         string `json:"id"`
+	MentorId   string `json:"mentorId"`
+	MenteeId   string `json:"menteeId"`
+	Topic      string `json:"topic"`
+	Message    string `json:"message"`
+	Reply      string `json:"reply"`
+	Status     string `json:"status"`
+	CreateTime int64  `json:"createTime"`
+}
+
+type ExportJob struct {
+	Id           string `json:"id"`
+	Organization string `json:"organization"`
+	Position     string `json:"position"`
+	Industry     string `json:"industry"`
+	City         string `json:"city"`
+	SalaryMin    int64  `json:"salaryMin"`
+	SalaryMax    int64  `json:"salaryMax"`
+	Description  string `json:"description"`
+	ExpireTime   int64  `json:"expireTime"`
+	Status       string `json:"status"`
+	Reason       string `json:"reason"`
+	CreateTime   int64  `json:"createTime"`
+}
+
+type ExportApplication struct {
+	Id         string `json:"id"`
+	JobId      string `json:"jobId"`
+	Message    string `json:"message"`
+	CreateTime int64  `json:"createTime"`
+}
+
+type ExportGroupMember struct {
+	GroupId  string `json:"groupId"`
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	Status   string `json:"status"`
+	JoinTime int64  `json:"joinTime"`
+}
+
+type ExportAnnouncement struct {
+	Id         string `json:"id"`
+	GroupId    string `json:"groupId"`
+	Title      string `json:"title"`
+	Content    string `json:"content"`
+	CreateTime int64  `json:"createTime"`
+}
+
+type DeleteAccountReq struct{}
+
+type CancelAccountDeletionReq struct{}
+
+// AccountDeletion 注销申请状态，DueTime 之前可撤销，之后个人信息将被匿名化
+type AccountDeletion struct {
+	RequestTime int64 `json:"requestTime"`
+	DueTime     int64 `json:"dueTime"`
+}
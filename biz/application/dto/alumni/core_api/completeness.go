@@ -0,0 +1,17 @@
+// plain (non-generated) types for profile completeness
+
+package core_api
+
+// ProfileCompleteness 资料完整度，Score 取值 0-100，Missing 为未填写的部分，
+// 取值 avatar/name/gender/birthday/hometown/educations/employments
+type ProfileCompleteness struct {
+	Score   int64    `json:"score"`
+	Missing []string `json:"missing"`
+}
+
+// UserInfo 当前用户的资料
+type UserInfo struct {
+	*GetUserInfoResp
+	HometownCode string               `json:"hometownCode,omitempty"`
+	Completeness *ProfileCompleteness `json:"completeness"`
+}
@@ -0,0 +1,24 @@
+// plain (non-generated) types for school and industry dictionaries
+
+package core_api
+
+type SearchDictionaryReq struct {
+	Kind     string `json:"kind"`     // school 或 industry
+	Keyword  string `json:"keyword"`  // 匹配名称和别名
+	ParentId string `json:"parentId"` // 仅对行业生效：关键词为空时返回该行业的下级，为空时返回一级行业
+	Limit    int64  `json:"limit"`
+}
+
+// DictionaryEntry Path 为行业从一级行业到自身的名称
+type DictionaryEntry struct {
+	Id       string   `json:"id"`
+	Name     string   `json:"name"`
+	Aliases  []string `json:"aliases"`
+	Region   string   `json:"region,omitempty"`
+	ParentId string   `json:"parentId,omitempty"`
+	Path     []string `json:"path,omitempty"`
+}
+
+type SearchDictionaryResp struct {
+	Entries []*DictionaryEntry `json:"entries"`
+}
@@ -0,0 +1,46 @@
+// plain (non-generated) types for the alumni directory
+
+package core_api
+
+type SearchDirectoryReq struct {
+	Keyword      string `json:"keyword"` // 同时匹配姓名、家乡、学校、单位、行业
+	Name         string `json:"name"`
+	Hometown     string `json:"hometown"`
+	School       string `json:"school"`
+	Year         int64  `json:"year"` // 毕业年份
+	Organization string `json:"organization"`
+	Industry     string `json:"industry"`
+	Page         int64  `json:"page"`
+	PageSize     int64  `json:"pageSize"`
+}
+
+// DirectoryUser 仅包含对方隐私设置允许当前用户查看的字段，其余字段为空
+type DirectoryUser struct {
+	Id                 string        `json:"id"`
+	Avatar             string        `json:"avatar"`
+	Name               string        `json:"name"`
+	Hometown           string        `json:"hometown,omitempty"`
+	HometownCode       string        `json:"hometownCode,omitempty"`
+	Birthday           int64         `json:"birthday,omitempty"`
+	Phone              string        `json:"phone,omitempty"`
+	WxId               string        `json:"wxId,omitempty"`
+	HometownEducations []*Education  `json:"hometownEducations,omitempty"`
+	ShanghaiEducations []*Education  `json:"shanghaiEducations,omitempty"`
+	Employments        []*Employment `json:"employments,omitempty"`
+	Matched            []string      `json:"matched"` // 命中的检索条件，便于前端高亮
+}
+
+// SearchDirectoryResp 只对最近注册的一批候选人做相关度排序，Total 为参与排序的人数；
+// Capped 为 true 表示符合条件的人数超出上限，其余校友不在结果中，应提示用户缩小检索范围
+type SearchDirectoryResp struct {
+	Total  int64            `json:"total"`
+	Capped bool             `json:"capped"`
+	Users  []*DirectoryUser `json:"users"`
+}
+
+// DirectorySetting 是否出现在通讯录中，各字段的可见范围由隐私设置决定
+type DirectorySetting struct {
+	Listed bool `json:"listed"`
+}
+
+type GetDirectorySettingReq struct{}
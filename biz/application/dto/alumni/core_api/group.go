@@ -0,0 +1,144 @@
+// plain (non-generated) types for alumni interest groups
+
+package core_api
+
+// Group 校友群组，Category 取值 school/hometown/industry/hobby，Policy 取值 open/approval/invite
+type Group struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Category    string `json:"category"`
+	RefId       string `json:"refId"` // 学校、行业群组关联的字典条目 id，同乡群组关联的行政区划代码
+	Avatar      string `json:"avatar"`
+	Description string `json:"description"`
+	Policy      string `json:"policy"`
+	MemberCount int64  `json:"memberCount"`
+	MyRole      string `json:"myRole"`   // 当前用户在群组中的角色，未加入为空
+	MyStatus    string `json:"myStatus"` // 当前用户的成员状态 active/pending/invited，未加入为空
+	CreateTime  int64  `json:"createTime"`
+}
+
+type CreateGroupReq struct {
+	Name        string `json:"name"`
+	Category    string `json:"category"`
+	RefId       string `json:"refId"`
+	Avatar      string `json:"avatar"`
+	Description string `json:"description"`
+	Policy      string `json:"policy"` // 为空时为 open
+}
+
+// UpdateGroupReq 类别和关联对象创建后不可修改
+type UpdateGroupReq struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Avatar      string `json:"avatar"`
+	Description string `json:"description"`
+	Policy      string `json:"policy"`
+}
+
+type SearchGroupsReq struct {
+	Keyword  string `json:"keyword"`
+	Category string `json:"category"`
+	RefId    string `json:"refId"`
+	Mine     bool   `json:"mine"` // 仅返回当前用户已加入的群组
+	Page     int64  `json:"page"`
+	PageSize int64  `json:"pageSize"`
+}
+
+type GetGroupsResp struct {
+	Total  int64    `json:"total"`
+	Groups []*Group `json:"groups"`
+}
+
+type GetGroupReq struct {
+	Id string `json:"id"`
+}
+
+// JoinGroupReq 开放群组直接加入，需审批的群组提交申请，受邀用户加入即接受邀请
+type JoinGroupReq struct {
+	Id string `json:"id"`
+}
+
+// LeaveGroupReq 退出群组，同时可用于撤回申请或拒绝邀请
+type LeaveGroupReq struct {
+	Id string `json:"id"`
+}
+
+type InviteGroupMemberReq struct {
+	Id     string `json:"id"`
+	UserId string `json:"userId"`
+}
+
+// ReviewGroupMemberReq 管理员审批加入申请
+type ReviewGroupMemberReq struct {
+	Id      string `json:"id"`
+	UserId  string `json:"userId"`
+	Approve bool   `json:"approve"`
+}
+
+type SetGroupMemberRoleReq struct {
+	Id     string `json:"id"`
+	UserId string `json:"userId"`
+	Role   string `json:"role"` // admin 或 member
+}
+
+type RemoveGroupMemberReq struct {
+	Id     string `json:"id"`
+	UserId string `json:"userId"`
+}
+
+type GetGroupMembersReq struct {
+	Id       string `json:"id"`
+	Status   string `json:"status"` // 为空时查询正式成员，pending、invited 仅管理员可查询
+	Page     int64  `json:"page"`
+	PageSize int64  `json:"pageSize"`
+}
+
+// GroupMember 群组成员，资料按成员的隐私设置裁剪
+type GroupMember struct {
+	*DirectoryUser
+	Role     string `json:"role"`
+	Status   string `json:"status"`
+	JoinTime int64  `json:"joinTime"`
+}
+
+type GetGroupMembersResp struct {
+	Total   int64          `json:"total"`
+	Members []*GroupMember `json:"members"`
+}
+
+// CreateGroupAnnouncementReq 管理员发布群组公告，发布后通知全部成员
+type CreateGroupAnnouncementReq struct {
+	Id      string `json:"id"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+type GroupAnnouncement struct {
+	Id         string         `json:"id"`
+	Title      string         `json:"title"`
+	Content    string         `json:"content"`
+	Author     *DirectoryUser `json:"author"`
+	CreateTime int64          `json:"createTime"`
+}
+
+type GetGroupAnnouncementsReq struct {
+	Id       string `json:"id"`
+	Page     int64  `json:"page"`
+	PageSize int64  `json:"pageSize"`
+}
+
+type GetGroupAnnouncementsResp struct {
+	Total         int64                `json:"total"`
+	Announcements []*GroupAnnouncement `json:"announcements"`
+}
+
+type DeleteGroupAnnouncementReq struct {
+	Id string `json:"id"` // 公告 id
+}
+
+// ListActivitiesReq 活动列表，GroupId 只返回该群组的活动，MyGroups 只返回当前用户所在群组的活动
+type ListActivitiesReq struct {
+	*GetActivitiesReq
+	GroupId  string `json:"groupId"`
+	MyGroups bool   `json:"myGroups"`
+}
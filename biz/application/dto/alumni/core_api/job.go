@@ -0,0 +1,95 @@
+// plain (non-generated) types for the alumni job board
+
+package core_api
+
+// CreateJobReq 薪资单位为元/月，均为 0 时表示面议；ExpireTime 为空时默认 30 天后过期
+type CreateJobReq struct {
+	Organization string `json:"organization"`
+	Position     string `json:"position"`
+	Industry     string `json:"industry"`
+	City         string `json:"city"`
+	SalaryMin    int64  `json:"salaryMin"`
+	SalaryMax    int64  `json:"salaryMax"`
+	Description  string `json:"description"`
+	ExpireTime   int64  `json:"expireTime"`
+}
+
+type SearchJobsReq struct {
+	Keyword      string `json:"keyword"` // 同时匹配职位、单位和描述
+	Organization string `json:"organization"`
+	Industry     string `json:"industry"`
+	City         string `json:"city"`
+	SalaryMin    int64  `json:"salaryMin"` // 只返回薪资上限不低于该值或面议的职位
+	Page         int64  `json:"page"`
+	PageSize     int64  `json:"pageSize"`
+}
+
+type GetJobReq struct {
+	Id string `json:"id"`
+}
+
+type GetMyJobsReq struct {
+	Status   string `json:"status"` // pending、approved、rejected、closed，为空时查询全部
+	Page     int64  `json:"page"`
+	PageSize int64  `json:"pageSize"`
+}
+
+// Job 发布人查看自己的职位时返回审核状态、驳回理由和投递数量，其他人只能看到已通过审核的职位
+type Job struct {
+	Id           string         `json:"id"`
+	Poster       *DirectoryUser `json:"poster"`
+	Organization string         `json:"organization"`
+	Position     string         `json:"position"`
+	Industry     string         `json:"industry"`
+	City         string         `json:"city"`
+	SalaryMin    int64          `json:"salaryMin"`
+	SalaryMax    int64          `json:"salaryMax"`
+	Description  string         `json:"description"`
+	ExpireTime   int64          `json:"expireTime"`
+	Status       string         `json:"status"`
+	Reason       string         `json:"reason,omitempty"`
+	Applications int64          `json:"applications,omitempty"`
+	Applied      bool           `json:"applied"`
+	CreateTime   int64          `json:"createTime"`
+}
+
+type GetJobsResp struct {
+	Total int64  `json:"total"`
+	Jobs  []*Job `json:"jobs"`
+}
+
+type CloseJobReq struct {
+	Id string `json:"id"`
+}
+
+type ApplyJobReq struct {
+	JobId   string `json:"jobId"`
+	Message string `json:"message"`
+}
+
+type GetJobApplicationsReq struct {
+	JobId    string `json:"jobId"`
+	Page     int64  `json:"page"`
+	PageSize int64  `json:"pageSize"`
+}
+
+// JobApplication 投递即同意向发布人展示资料，Applicant 不受申请人隐私设置限制
+type JobApplication struct {
+	Id         string         `json:"id"`
+	Applicant  *DirectoryUser `json:"applicant"`
+	Message    string         `json:"message"`
+	CreateTime int64          `json:"createTime"`
+}
+
+type GetJobApplicationsResp struct {
+	Total        int64             `json:"total"`
+	Applications []*JobApplication `json:"applications"`
+}
+
+type GetJobVocabularyReq struct{}
+
+// JobVocabulary 校友工作经历中已有的单位和行业，发布招聘时用于联想输入
+type JobVocabulary struct {
+	Organizations []string `json:"organizations"`
+	Industries    []string `json:"industries"`
+}
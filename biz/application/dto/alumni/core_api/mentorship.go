@@ -0,0 +1,79 @@
+// plain (non-generated) types for alumni mentorship
+
+package core_api
+
+// MentorSetting 导师设置，Active 为进行中的指导数量，更新时忽略
+type MentorSetting struct {
+	Enabled  bool     `json:"enabled"`
+	Topics   []string `json:"topics"`
+	Capacity int64    `json:"capacity"`
+	Intro    string   `json:"intro"`
+	Active   int64    `json:"active"`
+}
+
+type GetMentorSettingReq struct{}
+
+type SuggestMentorsReq struct {
+	Topic    string `json:"topic"` // 希望获得指导的方向，可为空
+	Page     int64  `json:"page"`
+	PageSize int64  `json:"pageSize"`
+}
+
+// Mentor 推荐的导师，Matched 为与当前用户的共同点，Available 为剩余名额
+type Mentor struct {
+	*DirectoryUser
+	Topics    []string `json:"topics"`
+	Intro     string   `json:"intro"`
+	Available int64    `json:"available"`
+	Score     int64    `json:"score"`
+}
+
+// SuggestMentorsResp 只对最近注册的一批导师做推荐排序，Total 为其中仍有名额的人数；
+// Capped 为 true 表示开放指导的导师数超出上限，其余导师不在结果中
+type SuggestMentorsResp struct {
+	Total   int64     `json:"total"`
+	Capped  bool      `json:"capped"`
+	Mentors []*Mentor `json:"mentors"`
+}
+
+type CreateMentorshipReq struct {
+	MentorId string `json:"mentorId"`
+	Topic    string `json:"topic"`
+	Message  string `json:"message"`
+}
+
+type GetMentorshipsReq struct {
+	Role     string `json:"role"`   // mentor 查询收到的申请，其余查询发出的申请
+	Status   string `json:"status"` // pending、accepted、declined、completed，为空时查询全部
+	Page     int64  `json:"page"`
+	PageSize int64  `json:"pageSize"`
+}
+
+type Mentorship struct {
+	Id         string         `json:"id"`
+	Mentor     *DirectoryUser `json:"mentor"`
+	Mentee     *DirectoryUser `json:"mentee"`
+	Topic      string         `json:"topic"`
+	Message    string         `json:"message"`
+	Reply      string         `json:"reply"`
+	Status     string         `json:"status"`
+	CreateTime int64          `json:"createTime"`
+	UpdateTime int64          `json:"updateTime"`
+}
+
+type GetMentorshipsResp struct {
+	Total       int64         `json:"total"`
+	Mentorships []*Mentorship `json:"mentorships"`
+}
+
+// RespondMentorshipReq 导师接受或拒绝待处理的申请
+type RespondMentorshipReq struct {
+	Id     string `json:"id"`
+	Accept bool   `json:"accept"`
+	Reply  string `json:"reply"`
+}
+
+// CompleteMentorshipReq 导师或学员结束进行中的指导
+type CompleteMentorshipReq struct {
+	Id string `json:"id"`
+}
@@ -0,0 +1,28 @@
+// plain (non-generated) types for in-app notifications
+
+package core_api
+
+type GetNotificationsReq struct {
+	Page     int64 `json:"page"`
+	PageSize int64 `json:"pageSize"`
+}
+
+type Notification struct {
+	Id         string `json:"id"`
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	Content    string `json:"content"`
+	Read       bool   `json:"read"`
+	CreateTime int64  `json:"createTime"`
+}
+
+type GetNotificationsResp struct {
+	Notifications []*Notification `json:"notifications"`
+	Total         int64           `json:"total"`
+	Unread        int64           `json:"unread"`
+}
+
+// ReadNotificationsReq Ids 为空时将全部通知标记为已读
+type ReadNotificationsReq struct {
+	Ids []string `json:"ids"`
+}
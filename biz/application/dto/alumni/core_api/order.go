@@ -0,0 +1,59 @@
+// plain (non-generated) types for paid activity tickets and orders
+
+package core_api
+
+// Ticket 活动票种，Price 单位为分
+type Ticket struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Price       int64  `json:"price"`
+	Description string `json:"description"`
+}
+
+type ListTicketsReq struct {
+	ActivityId string `json:"activityId"`
+}
+
+type ListTicketsResp struct {
+	ActivityId string    `json:"activityId"`
+	Tickets    []*Ticket `json:"tickets"`
+}
+
+type CreateOrderReq struct {
+	ActivityId string                              `json:"activityId"`
+	TicketId   string                              `json:"ticketId"`
+	Items      []*RegisterActivityReq_RegisterItem `json:"items"`
+	OpenId     string                              `json:"openId"` // 微信支付付款人 openid
+}
+
+type Order struct {
+	Id          string            `json:"id"`
+	ActivityId  string            `json:"activityId"`
+	TicketId    string            `json:"ticketId"`
+	TicketName  string            `json:"ticketName"`
+	UnitPrice   int64             `json:"unitPrice"`
+	Quantity    int64             `json:"quantity"`
+	Amount      int64             `json:"amount"`
+	Status      string            `json:"status"` // pending/paid/refunded/expired
+	RegisterIds []string          `json:"registerIds"`
+	ExpireTime  int64             `json:"expireTime"`
+	PaidTime    int64             `json:"paidTime"`
+	RefundTime  int64             `json:"refundTime"`
+	CreateTime  int64             `json:"createTime"`
+	PayParams   map[string]string `json:"payParams,omitempty"` // 仅下单时返回，用于客户端拉起支付
+}
+
+type GetOrderReq struct {
+	Id string `json:"id"`
+}
+
+type GetOrdersReq struct {
+	ActivityId string `json:"activityId"`
+	Page       int64  `json:"page"`
+	PageSize   int64  `json:"pageSize"`
+}
+
+type GetOrdersResp struct {
+	Total  int64    `json:"total"`
+	Orders []*Order `json:"orders"`
+}
@@ -0,0 +1,49 @@
+// plain (non-generated) types for organizations and their activities
+
+package core_api
+
+type Organization struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Logo        string `json:"logo"`
+	Description string `json:"description"`
+	Contact     string `json:"contact"`
+	MemberCount int64  `json:"memberCount"`
+	MyRole      string `json:"myRole"` // 当前用户在组织中的角色，未加入为空
+}
+
+type GetOrganizationsReq struct {
+	Keyword  string `json:"keyword"`
+	Mine     bool   `json:"mine"` // 仅返回当前用户加入的组织
+	Page     int64  `json:"page"`
+	PageSize int64  `json:"pageSize"`
+}
+
+type GetOrganizationsResp struct {
+	Total         int64           `json:"total"`
+	Organizations []*Organization `json:"organizations"`
+}
+
+type GetOrganizationReq struct {
+	Id string `json:"id"`
+}
+
+type GetOrganizationActivitiesReq struct {
+	Id       string `json:"id"`
+	Page     int64  `json:"page"`
+	PageSize int64  `json:"pageSize"`
+}
+
+type GetActivityOrganizationsReq struct {
+	ActivityId string `json:"activityId"`
+}
+
+type GetActivityOrganizationsResp struct {
+	Organizations []*Organization `json:"organizations"`
+}
+
+// CreateOrganizationActivityReq 组织管理者以所管理的组织名义创建活动
+type CreateOrganizationActivityReq struct {
+	OrganizationIds []string `json:"organizationIds"`
+	*CreateActivityReq
+}
@@ -0,0 +1,12 @@
+// plain (non-generated) types for changing the bound phone number
+
+package core_api
+
+type SendPhoneChangeCodeReq struct {
+	Phone string `json:"phone"` // 新手机号
+}
+
+type ChangePhoneReq struct {
+	Phone      string `json:"phone"`
+	VerifyCode string `json:"verifyCode"` // 发送到新手机号的验证码
+}
@@ -0,0 +1,10 @@
+// plain (non-generated) types for profile privacy settings
+
+package core_api
+
+type GetPrivacySettingReq struct{}
+
+// PrivacySetting 字段分组到可见范围的映射，范围取值 private/alumni/public
+type PrivacySetting struct {
+	Privacy map[string]string `json:"privacy"`
+}
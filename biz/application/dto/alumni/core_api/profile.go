@@ -0,0 +1,22 @@
+// plain (non-generated) types for viewing another alumnus' profile
+
+package core_api
+
+type GetUserProfileReq struct {
+	Id string `path:"id" json:"id"`
+}
+
+// UserProfile 他人资料，对方未向当前用户公开的字段为空
+type UserProfile struct {
+	Id string `json:"id"`
+	*GetUserInfoResp
+	HometownCode string           `json:"hometownCode,omitempty"`
+	Shared       []*SharedContext `json:"shared"` // 与当前用户的共同点
+}
+
+// SharedContext 共同点，Type 取值 hometown/school/organization
+type SharedContext struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	Year  int64  `json:"year,omitempty"` // 同校且同届时为毕业年份
+}
@@ -0,0 +1,47 @@
+// plain (non-generated) types for administrative regions
+
+package core_api
+
+// Region GB/T 2260 行政区划，Level 为 1 省级、2 地级、3 县级，FullName 为从省级开始的完整名称
+type Region struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	Level      int64  `json:"level"`
+	ParentCode string `json:"parentCode,omitempty"`
+	FullName   string `json:"fullName"`
+}
+
+type GetRegionsReq struct {
+	ParentCode string `json:"parentCode"` // 为空时返回省级区划
+}
+
+type SearchRegionsReq struct {
+	Keyword string `json:"keyword"`
+	Limit   int64  `json:"limit"`
+}
+
+type GetRegionsResp struct {
+	Regions []*Region `json:"regions"`
+}
+
+// UpdateHometownReq Code 为空时清空家乡
+type UpdateHometownReq struct {
+	Code string `json:"code"`
+}
+
+type GetRegionStatsReq struct {
+	Code string `json:"code"` // 为空时按省级区划统计，否则按该区划的下级统计
+}
+
+type RegionStat struct {
+	Region *Region `json:"region"`
+	Count  int64   `json:"count"`
+}
+
+// RegionStatsResp Direct 为家乡只填写到该区划、未细化到下级的人数
+type RegionStatsResp struct {
+	Region *Region       `json:"region,omitempty"`
+	Total  int64         `json:"total"`
+	Direct int64         `json:"direct"`
+	Stats  []*RegionStat `json:"stats"`
+}
@@ -0,0 +1,20 @@
+// plain (non-generated) types for login sessions and refresh tokens
+
+package core_api
+
+// AuthTokens 登录结果，AccessToken 过期后使用 RefreshToken 调用 /session/refresh 换取新的令牌
+type AuthTokens struct {
+	Id            string `json:"id"`
+	AccessToken   string `json:"accessToken"`
+	AccessExpire  int64  `json:"accessExpire"`
+	RefreshToken  string `json:"refreshToken,omitempty"`
+	RefreshExpire int64  `json:"refreshExpire,omitempty"`
+}
+
+type RefreshSessionReq struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+type LogoutReq struct {
+	All bool `json:"all"` // 为 true 时退出全部设备
+}
@@ -0,0 +1,27 @@
+// plain (non-generated) types for alumni verification applications
+
+package core_api
+
+type SubmitVerificationReq struct {
+	School string   `json:"school"`
+	Year   int64    `json:"year"`   // 毕业年份
+	Proofs []string `json:"proofs"` // 证明材料图片地址，先通过 STS 上传
+}
+
+type GetVerificationsReq struct{}
+
+type Verification struct {
+	Id         string   `json:"id"`
+	School     string   `json:"school"`
+	Year       int64    `json:"year"`
+	Proofs     []string `json:"proofs"`
+	Status     string   `json:"status"` // pending/approved/rejected
+	Reason     string   `json:"reason"`
+	ReviewTime int64    `json:"reviewTime"`
+	CreateTime int64    `json:"createTime"`
+}
+
+type GetVerificationsResp struct {
+	Role          string          `json:"role"` // 当前角色，认证通过后为 alumni
+	Verifications []*Verification `json:"verifications"`
+}
@@ -0,0 +1,17 @@
+// plain (non-generated) types for WeChat mini-program login
+
+package core_api
+
+type WxLoginReq struct {
+	Code string `json:"code"` // wx.login 返回的 code
+}
+
+type WxBindPhoneReq struct {
+	Code string `json:"code"` // getPhoneNumber 返回的 code
+}
+
+// WxLoginResp NeedBindPhone 为 true 时需引导用户授权手机号并调用 /user/wx_bind_phone
+type WxLoginResp struct {
+	*AuthTokens
+	NeedBindPhone bool `json:"needBindPhone"`
+}
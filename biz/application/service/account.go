@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/group"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/group_announcement"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/group_member"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/job"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/job_application"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/mentorship"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/order"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/organization"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/register"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/verification"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util/log"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	defaultDeletionGraceDays = 15
+	// deletedUserName 匿名化后展示的用户名与报名人姓名
+	deletedUserName = "已注销用户"
+	// exportRecordLimit 导出时每类记录的数量上限
+	exportRecordLimit = int64(1000)
+	// purgeBatchSize 每轮匿名化处理的账号数量上限
+	purgeBatchSize = int64(100)
+)
+
+type IAccountService interface {
+	ExportUserData(ctx context.Context, _ *core_api.ExportUserDataReq) (*core_api.UserDataExport, error)
+	DeleteAccount(ctx context.Context, _ *core_api.DeleteAccountReq) (*core_api.AccountDeletion, error)
+	CancelAccountDeletion(ctx context.Context, _ *core_api.CancelAccountDeletionReq) (*core_api.Response, error)
+	PurgeDueDeletions(ctx context.Context, now time.Time) (int, error)
+}
+
+type AccountService struct {
+	Config             *config.Config
+	UserMapper         *user.MongoMapper
+	RegisterMapper     *register.MongoMapper
+	OrderMapper        *order.MongoMapper
+	VerificationMapper *verification.MongoMapper
+	OrganizationMapper *organization.MongoMapper
+	GroupMapper        *group.MongoMapper
+	GroupMemberMapper  *group_member.MongoMapper
+	AnnouncementMapper *group_announcement.MongoMapper
+	MentorshipMapper   *mentorship.MongoMapper
+	JobMapper          *job.MongoMapper
+	ApplicationMapper  *job_application.MongoMapper
+}
+
+var AccountServiceSet = wire.NewSet(
+	wire.Struct(new(AccountService), "*"),
+	wire.Bind(new(IAccountService), new(*AccountService)),
+)
+
+func (s *AccountService) currentUser(ctx context.Context) (*user.User, error) {
+	userMeta := adaptor.ExtractUserMeta(ctx)
+	if userMeta.GetUserId() == "" {
+		return nil, consts.ErrNotAuthentication
+	}
+	aUser, err := s.UserMapper.FindOne(ctx, userMeta.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if aUser.Deletion.Purged {
+		return nil, consts.ErrAccountDeleted
+	}
+	return aUser, nil
+}
+
+// ExportUserData 导出平台保存的当前用户全部个人信息，供用户行使个人信息查阅、复制权
+func (s *AccountService) ExportUserData(ctx context.Context, _ *core_api.ExportUserDataReq) (*core_api.UserDataExport, error) {
+	aUser, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userId := aUser.ID.Hex()
+	info, err := mapUserInfo(aUser)
+	if err != nil {
+		return nil, err
+	}
+	info.Birthday = timeToUnix(aUser.Birthday)
+	privacy := make(map[string]string, len(user.PrivacyFields))
+	for _, field := range user.PrivacyFields {
+		privacy[field] = aUser.PrivacyLevel(field)
+	}
+	export := &core_api.UserDataExport{
+		ExportTime: time.Now().Unix(),
+		Profile: &core_api.ExportProfile{
+			Id:              userId,
+			GetUserInfoResp: info,
+			Role:            aUser.Role,
+			Privacy:         privacy,
+			Listed:          aUser.Directory.Listed,
+			CreateTime:      timeToUnix(aUser.CreateTime),
+			UpdateTime:      timeToUnix(aUser.UpdateTime),
+		},
+		Registrations: []*core_api.ExportRegistration{},
+		Orders:        []*core_api.ExportOrder{},
+		Verifications: []*core_api.Verification{},
+		Organizations: []*core_api.ExportMembership{},
+		Mentor: &core_api.MentorSetting{
+			Enabled:  aUser.Mentor.Enabled,
+			Topics:   aUser.Mentor.Topics,
+			Capacity: aUser.Mentor.Capacity,
+			Intro:    aUser.Mentor.Intro,
+		},
+		Mentorships:   []*core_api.ExportMentorship{},
+		Jobs:          []*core_api.ExportJob{},
+		Applications:  []*core_api.ExportApplication{},
+		Groups:        []*core_api.ExportGroupMember{},
+		Announcements: []*core_api.ExportAnnouncement{},
+		Files:         []string{},
+	}
+	if aUser.Avatar != "" {
+		export.Files = append(export.Files, aUser.Avatar)
+	}
+
+	registers, _, err := s.RegisterMapper.FindManyByFilter(ctx, bson.M{consts.UserID: userId}, 0, exportRecordLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range registers {
+		export.Registrations = append(export.Registrations, &core_api.ExportRegistration{
+			Id:          r.Id.Hex(),
+			ActivityId:  r.ActivityId,
+			Name:        r.Name,
+			Phone:       r.Phone,
+			CheckIn:     r.CheckIn,
+			CheckInTime: timeToUnix(r.CheckInTime),
+			OrderId:     r.OrderId,
+			PayStatus:   r.PayStatus,
+			CreateTime:  timeToUnix(r.CreateTime),
+		})
+	}
+
+	orders, _, err := s.OrderMapper.FindManyByFilter(ctx, bson.M{consts.UserID: userId}, 0, exportRecordLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range orders {
+		export.Orders = append(export.Orders, &core_api.ExportOrder{
+			Id:         o.ID.Hex(),
+			ActivityId: o.ActivityId,
+			TicketName: o.TicketName,
+			Quantity:   o.Quantity,
+			Amount:     o.Amount,
+			Status:     o.Status,
+			PaidTime:   timeToUnix(o.PaidTime),
+			RefundTime: timeToUnix(o.RefundTime),
+			CreateTime: timeToUnix(o.CreateTime),
+		})
+	}
+
+	verifications, _, err := s.VerificationMapper.FindManyByFilter(ctx, bson.M{consts.UserID: userId}, 0, exportRecordLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range verifications {
+		export.Verifications = append(export.Verifications, mapVerification(item))
+		export.Files = append(export.Files, item.Proofs...)
+	}
+
+	orgs, err := s.OrganizationMapper.FindByMember(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, org := range orgs {
+		for _, member := range org.Members {
+			if member.UserId != userId {
+				continue
+			}
+			export.Organizations = append(export.Organizations, &core_api.ExportMembership{
+				OrganizationId: org.ID.Hex(),
+				Name:           org.Name,
+				Role:           member.Role,
+				JoinTime:       timeToUnix(member.JoinTime),
+			})
+		}
+	}
+
+	mentorships, _, err := s.MentorshipMapper.FindMany(ctx, bson.M{"$or": []bson.M{{"mentor_id": userId}, {"mentee_id": userId}}}, 0, exportRecordLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range mentorships {
+		export.Mentorships = append(export.Mentorships, &core_api.ExportMentorship{
+			Id:         item.ID.Hex(),
+			MentorId:   item.MentorId,
+			MenteeId:   item.MenteeId,
+			Topic:      item.Topic,
+			Message:    item.Message,
+			Reply:      item.Reply,
+			Status:     item.Status,
+			CreateTime: timeToUnix(item.CreateTime),
+		})
+	}
+
+	jobs, _, err := s.JobMapper.FindMany(ctx, bson.M{"poster_id": userId}, 0, exportRecordLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range jobs {
+		export.Jobs = append(export.Jobs, &core_api.ExportJob{
+			Id:           item.ID.Hex(),
+			Organization: item.Organization,
+			Position:     item.Position,
+			Industry:     item.Industry,
+			City:         item.City,
+			SalaryMin:    item.SalaryMin,
+			SalaryMax:    item.SalaryMax,
+			Description:  item.Description,
+			ExpireTime:   timeToUnix(item.ExpireTime),
+			Status:       item.Status,
+			Reason:       item.Reason,
+			CreateTime:   timeToUnix(item.CreateTime),
+		})
+	}
+
+	applications, _, err := s.ApplicationMapper.FindByUser(ctx, userId, 0, exportRecordLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range applications {
+		export.Applications = append(export.Applications, &core_api.ExportApplication{
+			Id:         item.ID.Hex(),
+			JobId:      item.JobId,
+			Message:    item.Message,
+			CreateTime: timeToUnix(item.CreateTime),
+		})
+	}
+
+	members, _, err := s.GroupMemberMapper.FindMany(ctx, bson.M{consts.UserID: userId}, 0, exportRecordLimit)
+	if err != nil {
+		return nil, err
+	}
+	groupIds := make([]string, 0, len(members))
+	for _, member := range members {
+		groupIds = append(groupIds, member.GroupId)
+	}
+	groups, err := s.GroupMapper.FindByIDs(ctx, groupIds)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(groups))
+	for _, g := range groups {
+		names[g.ID.Hex()] = g.Name
+	}
+	for _, member := range members {
+		export.Groups = append(export.Groups, &core_api.ExportGroupMember{
+			GroupId:  member.GroupId,
+			Name:     names[member.GroupId],
+			Role:     member.Role,
+			Status:   member.Status,
+			JoinTime: timeToUnix(member.JoinTime),
+		})
+	}
+
+	announcements, _, err := s.AnnouncementMapper.FindByAuthor(ctx, userId, 0, exportRecordLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range announcements {
+		export.Announcements = append(export.Announcements, &core_api.ExportAnnouncement{
+			Id:         item.ID.Hex(),
+			GroupId:    item.GroupId,
+			Title:      item.Title,
+			Content:    item.Content,
+			CreateTime: timeToUnix(item.CreateTime),
+		})
+	}
+	return export, nil
+}
+
+// DeleteAccount 申请注销账号，冷静期内资料保持不变且可撤销，重复申请返回原有的注销时间
+func (s *AccountService) DeleteAccount(ctx context.Context, _ *core_api.DeleteAccountReq) (*core_api.AccountDeletion, error) {
+	aUser, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if aUser.Deletion.DueTime.IsZero() {
+		days := s.Config.Account.DeletionGraceDays
+		if days <= 0 {
+			days = defaultDeletionGraceDays
+		}
+		now := time.Now()
+		aUser.Deletion = user.Deletion{RequestTime: now, DueTime: now.AddDate(0, 0, int(days))}
+		if err = s.UserMapper.UpdateDeletion(ctx, aUser.ID, aUser.Deletion); err != nil {
+			return nil, consts.ErrUpdate
+		}
+	}
+	return &core_api.AccountDeletion{
+		RequestTime: aUser.Deletion.RequestTime.Unix(),
+		DueTime:     aUser.Deletion.DueTime.Unix(),
+	}, nil
+}
+
+func (s *AccountService) CancelAccountDeletion(ctx context.Context, _ *core_api.CancelAccountDeletionReq) (*core_api.Response, error) {
+	aUser, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !aUser.Deletion.DueTime.IsZero() {
+		if err = s.UserMapper.UpdateDeletion(ctx, aUser.ID, user.Deletion{}); err != nil {
+			return nil, consts.ErrUpdate
+		}
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "已撤销注销申请",
+	}, nil
+}
+
+// PurgeDueDeletions 匿名化冷静期已结束的账号，返回本轮处理成功的数量，单个账号失败时留待下一轮重试
+func (s *AccountService) PurgeDueDeletions(ctx context.Context, now time.Time) (int, error) {
+	users, err := s.UserMapper.FindDueDeletions(ctx, now, purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, aUser := range users {
+		if err = s.purgeUser(ctx, aUser, now); err != nil {
+			log.CtxError(ctx, "[PurgeDueDeletions] purge user failed, id=%s, err=%v", aUser.ID.Hex(), err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// RunDeletionSweeper 按固定间隔执行 PurgeDueDeletions，直到 ctx 结束
+func (s *AccountService) RunDeletionSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if purged, err := s.PurgeDueDeletions(ctx, time.Now()); err != nil {
+			log.CtxError(ctx, "[RunDeletionSweeper] purge failed, err=%v", err)
+		} else if purged > 0 {
+			log.CtxInfo(ctx, "[RunDeletionSweeper] purged %d accounts", purged)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeUser 抹去个人信息与指导、投递留言，关闭发布的职位，解除报名记录、组织与群组成员关系，订单作为交易凭证保留，最后才标记账号，保证失败后可重试
+func (s *AccountService) purgeUser(ctx context.Context, aUser *user.User, now time.Time) error {
+	userId := aUser.ID.Hex()
+	if _, err := s.RegisterMapper.DetachUser(ctx, userId, deletedUserName); err != nil {
+		return err
+	}
+	if _, err := s.VerificationMapper.ClearProofs(ctx, userId); err != nil {
+		return err
+	}
+	orgs, err := s.OrganizationMapper.FindByMember(ctx, userId)
+	if err != nil {
+		return err
+	}
+	for _, org := range orgs {
+		members := make([]organization.Member, 0, len(org.Members))
+		for _, member := range org.Members {
+			if member.UserId != userId {
+				members = append(members, member)
+			}
+		}
+		org.Members = members
+		if err = s.OrganizationMapper.Update(ctx, org); err != nil {
+			return err
+		}
+	}
+	if _, err = s.GroupMemberMapper.DeleteByUser(ctx, userId); err != nil {
+		return err
+	}
+	if err = s.MentorshipMapper.ClearUser(ctx, userId); err != nil {
+		return err
+	}
+	if err = s.JobMapper.CloseByPoster(ctx, userId); err != nil {
+		return err
+	}
+	if err = s.ApplicationMapper.ClearUser(ctx, userId); err != nil {
+		return err
+	}
+
+	aUser.Avatar, aUser.Name, aUser.Gender, aUser.Birthday = "", deletedUserName, 0, time.Time{}
+	aUser.Phone, aUser.WxId, aUser.Hometown, aUser.HometownCode = "", "", "", ""
+	aUser.WxOpenId, aUser.WxUnionId = "", ""
+	aUser.HomeEducations, aUser.ShanghaiEducations = []user.Education{}, []user.Education{}
+	aUser.Employments = []user.Employment{}
+	aUser.Role, aUser.AdminRoles = "user", []string{}
+	aUser.Directory, aUser.Privacy, aUser.Mentor = user.Directory{}, map[string]string{}, user.Mentor{}
+	aUser.Status, aUser.DeleteTime = 1, now
+	aUser.Deletion.Purged = true
+	// 匿名化需要覆盖全部个人信息字段，此处整体写入
+	return s.UserMapper.Update(ctx, aUser)
+}
@@ -9,23 +9,37 @@ import (
 	"github.com/jinzhu/copier"
 	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
 	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/application/dto/basic"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/activity"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/group_member"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/order"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/organization"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/register"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/role"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	util "github.com/xh-polaris/alumni-core_api/biz/infrastructure/util/page"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 type IActivityService interface {
 	CreateActivity(ctx context.Context, req *core_api.CreateActivityReq) (resp *core_api.Response, err error)
+	CreateOrganizationActivity(ctx context.Context, req *core_api.CreateOrganizationActivityReq) (resp *core_api.Response, err error)
 	UpdateActivity(ctx context.Context, req *core_api.UpdateActivityReq) (resp *core_api.Response, err error)
-	GetActivities(ctx context.Context, req *core_api.GetActivitiesReq) (resp *core_api.GetActivitiesResp, err error)
+	GetActivities(ctx context.Context, req *core_api.ListActivitiesReq) (resp *core_api.GetActivitiesResp, err error)
 	GetActivity(ctx context.Context, req *core_api.GetActivityReq) (resp *core_api.GetActivityResp, err error)
 	RegisterActivity(ctx context.Context, req *core_api.RegisterActivityReq) (resp *core_api.Response, err error)
 	CheckInActivity(ctx context.Context, req *core_api.CheckInReq) (resp *core_api.Response, err error)
 	GetRegisters(ctx context.Context, req *core_api.GetRegistersReq) (resp *core_api.GetRegisterResp, err error)
 }
 type ActivityService struct {
-	ActivityMapper *activity.MongoMapper
-	RegisterMapper *register.MongoMapper
+	ActivityMapper     *activity.MongoMapper
+	RegisterMapper     *register.MongoMapper
+	OrderMapper        *order.MongoMapper
+	UserMapper         *user.MongoMapper
+	OrganizationMapper *organization.MongoMapper
+	RoleMapper         *role.MongoMapper
+	GroupMemberMapper  *group_member.MongoMapper
 }
 
 var ActivityServiceSet = wire.NewSet(
@@ -34,26 +48,49 @@ var ActivityServiceSet = wire.NewSet(
 )
 
 func (s *ActivityService) CreateActivity(ctx context.Context, req *core_api.CreateActivityReq) (resp *core_api.Response, err error) {
-	limit := int64(-1)
-	if req.Limit != nil {
-		limit = *req.Limit
+	authority, err := loadActivityAuthority(ctx, s.UserMapper, s.OrganizationMapper, s.RoleMapper)
+	if err != nil {
+		return nil, err
 	}
-	now := time.Now()
-	a := activity.Activity{
-		Cover:         req.Cover,
-		Name:          req.Name,
-		Location:      req.Location,
-		ExactLocation: req.ExactLocation,
-		Sponsor:       req.Sponsor,
-		Start:         req.Start,
-		Description:   req.Description,
-		RegisterStart: time.Unix(req.RegisterStart, 0),
-		RegisterEnd:   time.Unix(req.RegisterEnd, 0),
-		Contact:       req.Contact,
-		Limit:         limit,
-		Status:        0,
-		CreateTime:    now,
-		UpdateTime:    now,
+	if !authority.admin {
+		return nil, consts.ErrForbidden
+	}
+	a := newActivity(req)
+	err = s.ActivityMapper.Insert(ctx, &a)
+	if err != nil {
+		return nil, consts.ErrCreate
+	}
+	resp = &core_api.Response{
+		Code: 0,
+		Msg:  "创建成功",
+	}
+	return resp, nil
+}
+
+func (s *ActivityService) CreateOrganizationActivity(ctx context.Context, req *core_api.CreateOrganizationActivityReq) (resp *core_api.Response, err error) {
+	if req.CreateActivityReq == nil {
+		return nil, consts.ErrCreate
+	}
+	authority, err := loadActivityAuthority(ctx, s.UserMapper, s.OrganizationMapper, s.RoleMapper)
+	if err != nil {
+		return nil, err
+	}
+	organizationIds := uniqueStrings(req.OrganizationIds)
+	if !authority.canCreateFor(organizationIds) {
+		return nil, consts.ErrForbidden
+	}
+	orgs, err := s.OrganizationMapper.FindByIDs(ctx, organizationIds)
+	if err != nil || len(orgs) != len(organizationIds) {
+		return nil, consts.ErrNotFound
+	}
+	a := newActivity(req.CreateActivityReq)
+	a.OrganizationIds = organizationIds
+	if strings.TrimSpace(a.Sponsor) == "" {
+		names := make([]string, 0, len(orgs))
+		for _, org := range orgs {
+			names = append(names, org.Name)
+		}
+		a.Sponsor = strings.Join(names, "、")
 	}
 	err = s.ActivityMapper.Insert(ctx, &a)
 	if err != nil {
@@ -67,10 +104,17 @@ func (s *ActivityService) CreateActivity(ctx context.Context, req *core_api.Crea
 }
 
 func (s *ActivityService) UpdateActivity(ctx context.Context, req *core_api.UpdateActivityReq) (resp *core_api.Response, err error) {
+	authority, err := loadActivityAuthority(ctx, s.UserMapper, s.OrganizationMapper, s.RoleMapper)
+	if err != nil {
+		return nil, err
+	}
 	a, err := s.ActivityMapper.FindById(ctx, req.GetId())
 	if err != nil {
 		return nil, err
 	}
+	if !authority.canManage(a.OrganizationIds) {
+		return nil, consts.ErrForbidden
+	}
 	if req.Cover != nil {
 		a.Cover = *req.Cover
 	}
@@ -118,29 +162,44 @@ func (s *ActivityService) UpdateActivity(ctx context.Context, req *core_api.Upda
 	return resp, nil
 }
 
-func (s *ActivityService) GetActivities(ctx context.Context, req *core_api.GetActivitiesReq) (resp *core_api.GetActivitiesResp, err error) {
-	data, total, err := s.ActivityMapper.FindMany(ctx, req.PaginationOptions)
+// GetActivities 活动列表，可按群组或当前用户所在的群组筛选
+func (s *ActivityService) GetActivities(ctx context.Context, req *core_api.ListActivitiesReq) (resp *core_api.GetActivitiesResp, err error) {
+	var pagination *basic.PaginationOptions
+	if req.GetActivitiesReq != nil {
+		pagination = req.PaginationOptions
+	}
+	var data []*activity.Activity
+	var total int64
+	switch {
+	case req.MyGroups:
+		userId := adaptor.ExtractUserMeta(ctx).GetUserId()
+		if userId == "" {
+			return nil, consts.ErrNotAuthentication
+		}
+		var groupIds []string
+		if groupIds, err = s.GroupMemberMapper.FindGroupIds(ctx, userId); err != nil {
+			return nil, err
+		}
+		skip, limit := util.ParsePageOpt(pagination)
+		data, total, err = s.ActivityMapper.FindManyByFilter(ctx, bson.M{
+			consts.Status:  consts.EffectStatus,
+			consts.GroupId: bson.M{"$in": groupIds},
+		}, skip, limit)
+	case req.GroupId != "":
+		skip, limit := util.ParsePageOpt(pagination)
+		data, total, err = s.ActivityMapper.FindManyByFilter(ctx, bson.M{
+			consts.Status:  consts.EffectStatus,
+			consts.GroupId: req.GroupId,
+		}, skip, limit)
+	default:
+		data, total, err = s.ActivityMapper.FindMany(ctx, pagination)
+	}
 	if err != nil {
 		return nil, consts.ErrNotFound
 	}
 	var activities []*core_api.Activity
 	for _, act := range data {
-		a := &core_api.Activity{
-			Id:            act.ID.Hex(),
-			Cover:         act.Cover,
-			Name:          act.Name,
-			Location:      act.Location,
-			ExactLocation: act.ExactLocation,
-			Sponsor:       act.Sponsor,
-			Start:         act.Start,
-			RegisterStart: act.RegisterStart.Unix(),
-			RegisterEnd:   act.RegisterEnd.Unix(),
-			Description:   act.Description,
-			Contact:       act.Contact,
-			Limit:         act.Limit,
-			Status:        act.Status,
-		}
-		activities = append(activities, a)
+		activities = append(activities, mapActivity(act))
 	}
 
 	resp = &core_api.GetActivitiesResp{
@@ -155,23 +214,9 @@ func (s *ActivityService) GetActivity(ctx context.Context, req *core_api.GetActi
 	if err != nil {
 		return nil, consts.ErrNotFound
 	}
-	a := &core_api.Activity{
-		Id:            act.ID.Hex(),
-		Cover:         act.Cover,
-		Name:          act.Name,
-		Location:      act.Location,
-		ExactLocation: act.ExactLocation,
-		Sponsor:       act.Sponsor,
-		Start:         act.Start,
-		RegisterStart: act.RegisterStart.Unix(),
-		RegisterEnd:   act.RegisterEnd.Unix(),
-		Description:   act.Description,
-		Contact:       act.Contact,
-		Limit:         act.Limit,
-		Status:        act.Status,
-	}
+	a := mapActivity(act)
 
-	count, err := s.RegisterMapper.Count(ctx, act.ID.Hex())
+	count, err := countOccupied(ctx, s.RegisterMapper, act.ID.Hex())
 	if err != nil {
 		return nil, consts.ErrCount
 	}
@@ -191,6 +236,17 @@ func (s *ActivityService) RegisterActivity(ctx context.Context, req *core_api.Re
 	userId := userMeta.GetUserId()
 	activityId := req.ActivityId
 
+	act, err := s.ActivityMapper.FindById(ctx, activityId)
+	if err != nil {
+		return nil, err
+	}
+	if isPaidActivity(act) {
+		return nil, consts.ErrPaidActivity
+	}
+	if err = reserveSeats(ctx, s.ActivityMapper, s.OrderMapper, s.RegisterMapper, act, int64(len(req.Items))); err != nil {
+		return nil, err
+	}
+
 	failed := make([]string, 0)
 
 	for _, item := range req.Items {
@@ -211,6 +267,7 @@ func (s *ActivityService) RegisterActivity(ctx context.Context, req *core_api.Re
 			failed = append(failed, name)
 		}
 	}
+	releaseSeats(ctx, s.ActivityMapper, activityId, int64(len(failed)))
 	resp = &core_api.Response{
 		Code: 0,
 		Msg:  "报名成功",
@@ -289,3 +346,45 @@ func (s *ActivityService) GetRegisters(ctx context.Context, req *core_api.GetReg
 	}
 	return resp, nil
 }
+
+func newActivity(req *core_api.CreateActivityReq) activity.Activity {
+	limit := int64(-1)
+	if req.Limit != nil {
+		limit = *req.Limit
+	}
+	now := time.Now()
+	return activity.Activity{
+		Cover:         req.Cover,
+		Name:          req.Name,
+		Location:      req.Location,
+		ExactLocation: req.ExactLocation,
+		Sponsor:       req.Sponsor,
+		Start:         req.Start,
+		Description:   req.Description,
+		RegisterStart: time.Unix(req.RegisterStart, 0),
+		RegisterEnd:   time.Unix(req.RegisterEnd, 0),
+		Contact:       req.Contact,
+		Limit:         limit,
+		Status:        0,
+		CreateTime:    now,
+		UpdateTime:    now,
+	}
+}
+
+func mapActivity(act *activity.Activity) *core_api.Activity {
+	return &core_api.Activity{
+		Id:            act.ID.Hex(),
+		Cover:         act.Cover,
+		Name:          act.Name,
+		Location:      act.Location,
+		ExactLocation: act.ExactLocation,
+		Sponsor:       act.Sponsor,
+		Start:         act.Start,
+		RegisterStart: act.RegisterStart.Unix(),
+		RegisterEnd:   act.RegisterEnd.Unix(),
+		Description:   act.Description,
+		Contact:       act.Contact,
+		Limit:         act.Limit,
+		Status:        act.Status,
+	}
+}
@@ -3,16 +3,24 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/google/wire"
 	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
 	appconsts "github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/activity"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/article"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/audit"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/order"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/register"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/role"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/session"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/region"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -33,6 +41,12 @@ type AdminService struct {
 	UserMapper     *user.MongoMapper
 	RegisterMapper *register.MongoMapper
 	ArticleMapper  *article.MongoMapper
+	ActivityMapper *activity.MongoMapper
+	OrderMapper    *order.MongoMapper
+	RoleMapper     *role.MongoMapper
+	AuditMapper    *audit.MongoMapper
+	SessionMapper  *session.MongoMapper
+	Regions        *region.Dataset
 }
 
 var AdminServiceSet = wire.NewSet(
@@ -47,11 +61,17 @@ type PageResult[T any] struct {
 }
 
 type AdminSession struct {
-	ID     string `json:"id"`
-	Name   string `json:"name"`
-	Avatar string `json:"avatar"`
-	Phone  string `json:"phone"`
-	Role   string `json:"role"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Avatar      string   `json:"avatar"`
+	Phone       string   `json:"phone"`
+	Role        string   `json:"role"`
+	Roles       []string `json:"roles"`       // 后台角色
+	Permissions []string `json:"permissions"` // 有效权限，前端据此隐藏无权限的操作
+}
+
+func (s *AdminSession) Can(permission string) bool {
+	return hasPermission(s.Permissions, permission)
 }
 
 type AdminUser struct {
@@ -63,12 +83,17 @@ type AdminUser struct {
 	Phone              string            `json:"phone"`
 	WxID               string            `json:"wxId"`
 	Hometown           string            `json:"hometown"`
+	HometownCode       string            `json:"hometownCode"`
+	Completeness       int64             `json:"completeness"`
+	MissingSections    []string          `json:"missingSections"`
 	HomeEducations     []user.Education  `json:"homeEducations"`
 	ShanghaiEducations []user.Education  `json:"shanghaiEducations"`
 	Employments        []user.Employment `json:"employments"`
 	Role               string            `json:"role"`
+	AdminRoles         []string          `json:"adminRoles"`
 	Status             int64             `json:"status"`
 	Deleted            bool              `json:"deleted"`
+	LockedUntil        *int64            `json:"lockedUntil"` // 登录失败被临时锁定时的解锁时间
 	CreateTime         int64             `json:"createTime"`
 }
 
@@ -80,6 +105,7 @@ type AdminUserUpdate struct {
 	Phone              *string           `json:"phone"`
 	WxID               *string           `json:"wxId"`
 	Hometown           *string           `json:"hometown"`
+	HometownCode       *string           `json:"hometownCode"` // 行政区划代码，设置后家乡文字同步为区划名称
 	HomeEducations     []user.Education  `json:"homeEducations"`
 	ShanghaiEducations []user.Education  `json:"shanghaiEducations"`
 	Employments        []user.Employment `json:"employments"`
@@ -146,11 +172,13 @@ func (s *AdminService) GetSession(ctx context.Context) (*AdminSession, error) {
 
 	if adaptor.IsDevModeRequest(ctx) && userMeta.GetUserId() == appconsts.DevMockUserID {
 		return &AdminSession{
-			ID:     appconsts.DevMockUserID,
-			Name:   "演示管理员",
-			Avatar: "",
-			Phone:  "13800000000",
-			Role:   "admin",
+			ID:          appconsts.DevMockUserID,
+			Name:        "演示管理员",
+			Avatar:      "",
+			Phone:       "13800000000",
+			Role:        "admin",
+			Roles:       []string{RoleSuperAdmin},
+			Permissions: allPermissions(),
 		}, nil
 	}
 
@@ -158,20 +186,29 @@ func (s *AdminService) GetSession(ctx context.Context) (*AdminSession, error) {
 	if err != nil {
 		return nil, ErrAdminUnauthorized
 	}
-	if item.Role != "admin" || item.Status != 0 || !item.DeleteTime.IsZero() {
+	if item.Status != 0 || !item.DeleteTime.IsZero() {
+		return nil, ErrAdminForbidden
+	}
+	roles, perms, err := resolvePermissions(ctx, s.RoleMapper, item)
+	if err != nil {
+		return nil, err
+	}
+	if len(perms) == 0 {
 		return nil, ErrAdminForbidden
 	}
 
 	return &AdminSession{
-		ID:     item.ID.Hex(),
-		Name:   item.Name,
-		Avatar: item.Avatar,
-		Phone:  item.Phone,
-		Role:   "admin",
+		ID:          item.ID.Hex(),
+		Name:        item.Name,
+		Avatar:      item.Avatar,
+		Phone:       item.Phone,
+		Role:        "admin",
+		Roles:       roles,
+		Permissions: perms,
 	}, nil
 }
 
-func (s *AdminService) ListUsers(ctx context.Context, page, pageSize int64, keyword, role, status string) (*PageResult[AdminUser], error) {
+func (s *AdminService) ListUsers(ctx context.Context, page, pageSize int64, keyword, role, status string, completeness CompletenessFilter) (*PageResult[AdminUser], error) {
 	page, pageSize = normalizePage(page, pageSize)
 	filter := bson.M{}
 	andFilters := make([]bson.M, 0)
@@ -188,6 +225,8 @@ func (s *AdminService) ListUsers(ctx context.Context, page, pageSize int64, keyw
 	switch status {
 	case "deleted":
 		filter["delete_time"] = bson.M{"$exists": true, "$ne": time.Time{}}
+	case "locked":
+		filter["sign_in_guard.locked_until"] = bson.M{"$gt": time.Now()}
 	case "0", "1":
 		filter["status"] = parseStatus(status)
 		if status != "1" {
@@ -199,6 +238,13 @@ func (s *AdminService) ListUsers(ctx context.Context, page, pageSize int64, keyw
 			bson.M{"$or": []bson.M{{"delete_time": bson.M{"$exists": false}}, {"delete_time": time.Time{}}}},
 		)
 	}
+	cond, err := completeness.condition()
+	if err != nil {
+		return nil, err
+	}
+	if cond != nil {
+		andFilters = append(andFilters, cond)
+	}
 	if len(andFilters) > 0 {
 		filter["$and"] = andFilters
 	}
@@ -227,6 +273,10 @@ func (s *AdminService) UpdateUser(ctx context.Context, id string, input AdminUse
 	if err != nil {
 		return nil, err
 	}
+	if input.HometownCode != nil && *input.HometownCode != "" && s.Regions.Get(*input.HometownCode) == nil {
+		return nil, ErrAdminBadRequest
+	}
+	before := snapshot(item)
 	if input.Avatar != nil {
 		item.Avatar = *input.Avatar
 	}
@@ -240,13 +290,27 @@ func (s *AdminService) UpdateUser(ctx context.Context, id string, input AdminUse
 		item.Birthday = unixToTime(*input.Birthday)
 	}
 	if input.Phone != nil {
-		item.Phone = *input.Phone
+		item.Phone = strings.TrimSpace(*input.Phone)
+		if item.Phone != "" {
+			// 同一手机号只能属于一个正常状态的账号
+			switch _, err := s.UserMapper.FindLiveByPhone(ctx, item.Phone, item.ID); {
+			case err == nil:
+				return nil, fmt.Errorf("%w: 手机号已被其他账号绑定", ErrAdminBadRequest)
+			case !errors.Is(err, appconsts.ErrNotFound):
+				return nil, err
+			}
+		}
 	}
 	if input.WxID != nil {
 		item.WxId = *input.WxID
 	}
 	if input.Hometown != nil {
 		item.Hometown = *input.Hometown
+		item.HometownCode = s.Regions.Resolve(*input.Hometown)
+	}
+	if input.HometownCode != nil {
+		item.HometownCode = *input.HometownCode
+		item.Hometown = s.Regions.FullName(*input.HometownCode)
 	}
 	if input.HomeEducations != nil {
 		item.HomeEducations = input.HomeEducations
@@ -257,9 +321,25 @@ func (s *AdminService) UpdateUser(ctx context.Context, id string, input AdminUse
 	if input.Employments != nil {
 		item.Employments = input.Employments
 	}
-	if err = s.UserMapper.Update(ctx, item); err != nil {
+	if err = s.UserMapper.UpdateProfile(ctx, item); err != nil {
 		return nil, err
 	}
+	if input.Phone != nil {
+		if err = s.UserMapper.UpdatePhone(ctx, item.ID, item.Phone, ""); err != nil {
+			return nil, err
+		}
+	}
+	if input.HomeEducations != nil || input.ShanghaiEducations != nil {
+		if err = s.UserMapper.UpdateEducations(ctx, item.ID, item.HomeEducations, item.ShanghaiEducations); err != nil {
+			return nil, err
+		}
+	}
+	if input.Employments != nil {
+		if err = s.UserMapper.UpdateEmployments(ctx, item.ID, item.Employments); err != nil {
+			return nil, err
+		}
+	}
+	recordAudit(ctx, s.AuditMapper, "user.update", AuditUser, item.ID.Hex(), before, snapshot(item))
 	result := mapAdminUser(item)
 	return &result, nil
 }
@@ -272,8 +352,13 @@ func (s *AdminService) SetUserRole(ctx context.Context, id, role string) error {
 	if err != nil {
 		return err
 	}
+	before := snapshot(item)
 	item.Role = role
-	return s.UserMapper.Update(ctx, item)
+	if err = s.UserMapper.UpdateRole(ctx, item.ID, role); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.AuditMapper, "user.set_role", AuditUser, item.ID.Hex(), before, snapshot(item))
+	return nil
 }
 
 func (s *AdminService) SetUserStatus(ctx context.Context, id string, status int64) error {
@@ -281,8 +366,18 @@ func (s *AdminService) SetUserStatus(ctx context.Context, id string, status int6
 	if err != nil {
 		return err
 	}
+	before := snapshot(item)
 	item.Status = status
-	return s.UserMapper.Update(ctx, item)
+	if err = s.UserMapper.UpdateStatus(ctx, item.ID, status); err != nil {
+		return err
+	}
+	if status != 0 {
+		if _, err = s.SessionMapper.RevokeByUser(ctx, item.ID.Hex()); err != nil {
+			return err
+		}
+	}
+	recordAudit(ctx, s.AuditMapper, "user.set_status", AuditUser, item.ID.Hex(), before, snapshot(item))
+	return nil
 }
 
 func (s *AdminService) DeleteUser(ctx context.Context, id string) error {
@@ -290,9 +385,17 @@ func (s *AdminService) DeleteUser(ctx context.Context, id string) error {
 	if err != nil {
 		return err
 	}
+	before := snapshot(item)
 	item.Status = 1
 	item.DeleteTime = time.Now()
-	return s.UserMapper.Update(ctx, item)
+	if err = s.UserMapper.SoftDeleteByID(ctx, item.ID); err != nil {
+		return err
+	}
+	if _, err = s.SessionMapper.RevokeByUser(ctx, item.ID.Hex()); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.AuditMapper, "user.delete", AuditUser, item.ID.Hex(), before, snapshot(item))
+	return nil
 }
 
 func (s *AdminService) RestoreUser(ctx context.Context, id string) error {
@@ -300,9 +403,29 @@ func (s *AdminService) RestoreUser(ctx context.Context, id string) error {
 	if err != nil {
 		return err
 	}
+	before := snapshot(item)
 	item.Status = 0
 	item.DeleteTime = time.Time{}
-	return s.UserMapper.Update(ctx, item)
+	if err = s.UserMapper.Restore(ctx, item.ID); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.AuditMapper, "user.restore", AuditUser, item.ID.Hex(), before, snapshot(item))
+	return nil
+}
+
+// UnlockUser 解除登录失败导致的临时锁定，并清空失败记录
+func (s *AdminService) UnlockUser(ctx context.Context, id string) error {
+	item, err := s.UserMapper.FindOne(ctx, id)
+	if err != nil {
+		return err
+	}
+	before := snapshot(item)
+	item.SignInGuard = user.SignInGuard{}
+	if err = s.UserMapper.UpdateSignInGuard(ctx, item.ID, item.SignInGuard); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.AuditMapper, "user.unlock", AuditUser, item.ID.Hex(), before, snapshot(item))
+	return nil
 }
 
 func (s *AdminService) ListRegistrations(ctx context.Context, page, pageSize int64, activityID, keyword, checkIn string) (*AdminRegistrationPage, error) {
@@ -363,6 +486,9 @@ func (s *AdminService) CreateRegistration(ctx context.Context, input AdminRegist
 	if err := s.RegisterMapper.Insert(ctx, item); err != nil {
 		return nil, err
 	}
+	// 管理员代报名不受名额上限限制，但同样计入名额
+	occupySeats(ctx, s.ActivityMapper, item.ActivityId, 1)
+	recordAudit(ctx, s.AuditMapper, "registration.create", AuditRegistration, item.Id.Hex(), nil, snapshot(item))
 	result := mapAdminRegistration(item)
 	return &result, nil
 }
@@ -372,6 +498,7 @@ func (s *AdminService) UpdateRegistration(ctx context.Context, id string, input
 	if err != nil {
 		return nil, err
 	}
+	before := snapshot(item)
 	if strings.TrimSpace(input.UserID) != "" {
 		item.UserId = input.UserID
 	}
@@ -382,6 +509,7 @@ func (s *AdminService) UpdateRegistration(ctx context.Context, id string, input
 	if err = s.RegisterMapper.Update(ctx, item); err != nil {
 		return nil, err
 	}
+	recordAudit(ctx, s.AuditMapper, "registration.update", AuditRegistration, item.Id.Hex(), before, snapshot(item))
 	result := mapAdminRegistration(item)
 	return &result, nil
 }
@@ -391,9 +519,18 @@ func (s *AdminService) DeleteRegistration(ctx context.Context, id string) error
 	if err != nil {
 		return err
 	}
+	before := snapshot(item)
+	occupied := item.Status != appconsts.DeleteStatus && occupiesSeat(item.PayStatus)
 	item.Status = 1
 	item.DeleteTime = time.Now()
-	return s.RegisterMapper.Update(ctx, item)
+	if err = s.RegisterMapper.Update(ctx, item); err != nil {
+		return err
+	}
+	if occupied {
+		releaseSeats(ctx, s.ActivityMapper, item.ActivityId, 1)
+	}
+	recordAudit(ctx, s.AuditMapper, "registration.delete", AuditRegistration, item.Id.Hex(), before, snapshot(item))
+	return nil
 }
 
 func (s *AdminService) SetRegistrationCheckIn(ctx context.Context, id string, checked bool) error {
@@ -401,13 +538,68 @@ func (s *AdminService) SetRegistrationCheckIn(ctx context.Context, id string, ch
 	if err != nil {
 		return err
 	}
+	before := snapshot(item)
 	item.CheckIn = checked
 	if checked {
 		item.CheckInTime = time.Now()
 	} else {
 		item.CheckInTime = time.Time{}
 	}
-	return s.RegisterMapper.Update(ctx, item)
+	if err = s.RegisterMapper.Update(ctx, item); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.AuditMapper, "registration.set_check_in", AuditRegistration, item.Id.Hex(), before, snapshot(item))
+	return nil
+}
+
+func (s *AdminService) SetActivityTickets(ctx context.Context, id string, tickets []activity.Ticket) ([]activity.Ticket, error) {
+	item, err := s.ActivityMapper.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := snapshot(item)
+	normalized := make([]activity.Ticket, 0, len(tickets))
+	for _, t := range tickets {
+		t.Name = strings.TrimSpace(t.Name)
+		t.Description = strings.TrimSpace(t.Description)
+		if t.Name == "" || t.Price < 0 {
+			return nil, ErrAdminBadRequest
+		}
+		if t.ID = strings.TrimSpace(t.ID); t.ID == "" {
+			t.ID = uuid.New().String()
+		}
+		normalized = append(normalized, t)
+	}
+	if err = s.checkTicketsInUse(ctx, item, normalized); err != nil {
+		return nil, err
+	}
+	item.Tickets = normalized
+	if err = s.ActivityMapper.Update(ctx, item); err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, s.AuditMapper, "activity.set_tickets", AuditActivity, item.ID.Hex(), before, snapshot(item))
+	return normalized, nil
+}
+
+// checkTicketsInUse 已有已支付或未超时待支付订单的票种不能删除或改价
+func (s *AdminService) checkTicketsInUse(ctx context.Context, item *activity.Activity, tickets []activity.Ticket) error {
+	inUse, err := s.OrderMapper.FindActiveTicketIds(ctx, item.ID.Hex(), time.Now())
+	if err != nil {
+		return err
+	}
+	prices := make(map[string]int64, len(tickets))
+	for _, t := range tickets {
+		prices[t.ID] = t.Price
+	}
+	for _, t := range item.Tickets {
+		if !inUse[t.ID] {
+			continue
+		}
+		if price, ok := prices[t.ID]; !ok || price != t.Price {
+			return fmt.Errorf("%w: 票种「%s」已有订单，不能删除或修改价格", ErrAdminBadRequest, t.Name)
+		}
+	}
+	return nil
 }
 
 func (s *AdminService) ListArticles(ctx context.Context, page, pageSize int64, keyword, status string) (*PageResult[AdminArticle], error) {
@@ -464,6 +656,7 @@ func (s *AdminService) CreateArticle(ctx context.Context, input AdminArticleInpu
 	if err := s.ArticleMapper.Insert(ctx, item); err != nil {
 		return nil, err
 	}
+	recordAudit(ctx, s.AuditMapper, "article.create", AuditArticle, item.ID.Hex(), nil, snapshot(item))
 	result := mapAdminArticle(item)
 	return &result, nil
 }
@@ -473,6 +666,7 @@ func (s *AdminService) UpdateArticle(ctx context.Context, id string, input Admin
 	if err != nil {
 		return nil, err
 	}
+	before := snapshot(item)
 	item.Title = strings.TrimSpace(input.Title)
 	item.Summary = strings.TrimSpace(input.Summary)
 	item.Cover = strings.TrimSpace(input.Cover)
@@ -484,6 +678,7 @@ func (s *AdminService) UpdateArticle(ctx context.Context, id string, input Admin
 	if err = s.ArticleMapper.Update(ctx, item); err != nil {
 		return nil, err
 	}
+	recordAudit(ctx, s.AuditMapper, "article.update", AuditArticle, item.ID.Hex(), before, snapshot(item))
 	result := mapAdminArticle(item)
 	return &result, nil
 }
@@ -493,9 +688,14 @@ func (s *AdminService) DeleteArticle(ctx context.Context, id string) error {
 	if err != nil {
 		return err
 	}
+	before := snapshot(item)
 	item.Deleted = true
 	item.DeleteTime = time.Now()
-	return s.ArticleMapper.Update(ctx, item)
+	if err = s.ArticleMapper.Update(ctx, item); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.AuditMapper, "article.delete", AuditArticle, item.ID.Hex(), before, snapshot(item))
+	return nil
 }
 
 func (s *AdminService) RestoreArticle(ctx context.Context, id string) error {
@@ -503,10 +703,15 @@ func (s *AdminService) RestoreArticle(ctx context.Context, id string) error {
 	if err != nil {
 		return err
 	}
+	before := snapshot(item)
 	item.Deleted = false
 	item.PublishStatus = article.StatusOffline
 	item.DeleteTime = time.Time{}
-	return s.ArticleMapper.Update(ctx, item)
+	if err = s.ArticleMapper.Update(ctx, item); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.AuditMapper, "article.restore", AuditArticle, item.ID.Hex(), before, snapshot(item))
+	return nil
 }
 
 func (s *AdminService) SetArticleStatus(ctx context.Context, id, status string) error {
@@ -514,6 +719,7 @@ func (s *AdminService) SetArticleStatus(ctx context.Context, id, status string)
 	if err != nil {
 		return err
 	}
+	before := snapshot(item)
 	if status != article.StatusPublished && status != article.StatusOffline {
 		return ErrAdminBadRequest
 	}
@@ -521,7 +727,11 @@ func (s *AdminService) SetArticleStatus(ctx context.Context, id, status string)
 	if status == article.StatusPublished && item.PublishTime.IsZero() {
 		item.PublishTime = time.Now()
 	}
-	return s.ArticleMapper.Update(ctx, item)
+	if err = s.ArticleMapper.Update(ctx, item); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.AuditMapper, "article.set_status", AuditArticle, item.ID.Hex(), before, snapshot(item))
+	return nil
 }
 
 func normalizePage(page, pageSize int64) (int64, int64) {
@@ -599,6 +809,12 @@ func mapAdminUser(item *user.User) AdminUser {
 	if role == "" {
 		role = "user"
 	}
+	var lockedUntil *int64
+	if item.SignInGuard.Locked(time.Now()) {
+		unix := item.SignInGuard.LockedUntil.Unix()
+		lockedUntil = &unix
+	}
+	completeness := profileCompleteness(item)
 	return AdminUser{
 		ID:                 item.ID.Hex(),
 		Avatar:             item.Avatar,
@@ -608,12 +824,17 @@ func mapAdminUser(item *user.User) AdminUser {
 		Phone:              item.Phone,
 		WxID:               item.WxId,
 		Hometown:           item.Hometown,
+		HometownCode:       item.HometownCode,
+		Completeness:       completeness.Score,
+		MissingSections:    completeness.Missing,
 		HomeEducations:     item.HomeEducations,
 		ShanghaiEducations: item.ShanghaiEducations,
 		Employments:        item.Employments,
 		Role:               role,
+		AdminRoles:         append([]string{}, item.AdminRoles...),
 		Status:             item.Status,
 		Deleted:            !item.DeleteTime.IsZero(),
+		LockedUntil:        lockedUntil,
 		CreateTime:         timeToUnix(item.CreateTime),
 	}
 }
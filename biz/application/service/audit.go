@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/audit"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 审计对象类型
+const (
+	AuditUser         = "user"
+	AuditRegistration = "registration"
+	AuditActivity     = "activity"
+	AuditOrder        = "order"
+	AuditOrganization = "organization"
+	AuditVerification = "verification"
+	AuditArticle      = "article"
+	AuditRole         = "role"
+	AuditJob          = "job"
+	AuditDictionary   = "dictionary"
+	AuditGroup        = "group"
+	AuditMentorship   = "mentorship"
+	AuditApplication  = "job_application"
+	AuditAnnouncement = "group_announcement"
+)
+
+type IAuditService interface {
+	ListAudits(ctx context.Context, query AdminAuditQuery) (*PageResult[AdminAudit], error)
+}
+
+type AuditService struct {
+	AuditMapper *audit.MongoMapper
+}
+
+var AuditServiceSet = wire.NewSet(
+	wire.Struct(new(AuditService), "*"),
+	wire.Bind(new(IAuditService), new(*AuditService)),
+)
+
+type AdminAudit struct {
+	ID         string         `json:"id"`
+	ActorID    string         `json:"actorId"`
+	Action     string         `json:"action"`
+	TargetType string         `json:"targetType"`
+	TargetID   string         `json:"targetId"`
+	Changes    []audit.Change `json:"changes"`
+	ClientIP   string         `json:"clientIp"`
+	TraceID    string         `json:"traceId"`
+	CreateTime int64          `json:"createTime"`
+}
+
+type AdminAuditQuery struct {
+	Page       int64
+	PageSize   int64
+	ActorID    string
+	TargetType string
+	TargetID   string
+	From       int64 // 起始时间（含），unix 秒
+	To         int64 // 截止时间（不含），unix 秒
+}
+
+func (s *AuditService) ListAudits(ctx context.Context, query AdminAuditQuery) (*PageResult[AdminAudit], error) {
+	page, pageSize := normalizePage(query.Page, query.PageSize)
+	filter := bson.M{}
+	if v := strings.TrimSpace(query.ActorID); v != "" {
+		filter["actor_id"] = v
+	}
+	if v := strings.TrimSpace(query.TargetType); v != "" {
+		filter["target_type"] = v
+	}
+	if v := strings.TrimSpace(query.TargetID); v != "" {
+		filter["target_id"] = v
+	}
+	timeRange := bson.M{}
+	if query.From > 0 {
+		timeRange["$gte"] = time.Unix(query.From, 0)
+	}
+	if query.To > 0 {
+		timeRange["$lt"] = time.Unix(query.To, 0)
+	}
+	if len(timeRange) > 0 {
+		filter[consts.CreateTime] = timeRange
+	}
+	data, total, err := s.AuditMapper.FindManyByFilter(ctx, filter, offset(page, pageSize), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]AdminAudit, 0, len(data))
+	for _, item := range data {
+		items = append(items, AdminAudit{
+			ID:         item.ID.Hex(),
+			ActorID:    item.ActorId,
+			Action:     item.Action,
+			TargetType: item.TargetType,
+			TargetID:   item.TargetId,
+			Changes:    item.Changes,
+			ClientIP:   item.ClientIP,
+			TraceID:    item.TraceId,
+			CreateTime: timeToUnix(item.CreateTime),
+		})
+	}
+	return &PageResult[AdminAudit]{Items: items, Total: total, Page: page, PageSize: pageSize}, nil
+}
+
+// recordAudit 记录一次后台变更，before/after 为 snapshot 得到的变更前后快照，新建时 before 为空、无法取得变更后状态时 after 为空
+// 审计写入失败只记录日志，不影响已经完成的变更
+func recordAudit(ctx context.Context, auditMapper *audit.MongoMapper, action, targetType, targetID string, before, after map[string]any) {
+	entry := &audit.Audit{
+		ActorId:    adaptor.ExtractUserMeta(ctx).GetUserId(),
+		Action:     action,
+		TargetType: targetType,
+		TargetId:   targetID,
+		Changes:    diffSnapshots(before, after),
+		ClientIP:   adaptor.ExtractExtra(ctx).GetClientIP(),
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		entry.TraceId = sc.TraceID().String()
+	}
+	if err := auditMapper.Insert(ctx, entry); err != nil {
+		log.CtxError(ctx, "[recordAudit] insert audit failed, action=%s, target=%s/%s, err=%v", action, targetType, targetID, err)
+	}
+}
+
+// reviewRecord 写入待审核记录的审核结果并记录审计，write 仅在记录仍待审核时写入并返回是否成功，apply 将审核结果同步到 item 以生成变更后快照
+func reviewRecord(ctx context.Context, auditMapper *audit.MongoMapper, targetType, targetID, to string, item any, write func(reviewerID string) (bool, error), apply func(reviewerID string)) error {
+	reviewerID := adaptor.ExtractUserMeta(ctx).GetUserId()
+	reviewed, err := write(reviewerID)
+	if err != nil {
+		return err
+	}
+	if !reviewed {
+		return ErrAdminBadRequest
+	}
+	before := snapshot(item)
+	apply(reviewerID)
+	recordAudit(ctx, auditMapper, targetType+"."+to, targetType, targetID, before, snapshot(item))
+	return nil
+}
+
+// snapshot 将对象按 json 字段名展开，用于比较变更前后的差异
+func snapshot(v any) map[string]any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	result := map[string]any{}
+	if err = json.Unmarshal(data, &result); err != nil {
+		return nil
+	}
+	return result
+}
+
+// diffSnapshots 只保留取值发生变化的字段，更新时间等每次都会变化的字段不计入
+func diffSnapshots(before, after map[string]any) []audit.Change {
+	fields := make([]string, 0, len(before)+len(after))
+	for field := range before {
+		fields = append(fields, field)
+	}
+	for field := range after {
+		if _, ok := before[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	changes := make([]audit.Change, 0)
+	for _, field := range fields {
+		if field == "updateTime" {
+			continue
+		}
+		if reflect.DeepEqual(before[field], after[field]) {
+			continue
+		}
+		changes = append(changes, audit.Change{Field: field, Before: before[field], After: after[field]})
+	}
+	return changes
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// profileSection 资料完整度的组成部分，expr 为与 filled 等价的聚合表达式，用于在数据库中按完整度筛选
+type profileSection struct {
+	key    string
+	label  string
+	weight int64
+	filled func(u *user.User) bool
+	expr   bson.M
+}
+
+// profileSections 各部分权重合计为 100，教育与工作经历决定通讯录能否检索到用户，权重最高
+var profileSections = []profileSection{
+	{
+		key: "avatar", label: "头像", weight: 10,
+		filled: func(u *user.User) bool { return strings.TrimSpace(u.Avatar) != "" },
+		expr:   nonEmptyStringExpr("avatar"),
+	},
+	{
+		key: "name", label: "姓名", weight: 10,
+		filled: func(u *user.User) bool { return strings.TrimSpace(u.Name) != "" },
+		expr:   nonEmptyStringExpr("name"),
+	},
+	{
+		key: "gender", label: "性别", weight: 5,
+		filled: func(u *user.User) bool { return u.Gender > 0 },
+		expr:   bson.M{"$gt": bson.A{bson.M{"$ifNull": bson.A{"$gender", 0}}, 0}},
+	},
+	{
+		key: "birthday", label: "生日", weight: 5,
+		filled: func(u *user.User) bool { return !u.Birthday.IsZero() },
+		expr:   bson.M{"$gt": bson.A{"$birthday", time.Time{}}},
+	},
+	{
+		key: user.FieldHometown, label: "家乡", weight: 10,
+		filled: func(u *user.User) bool { return strings.TrimSpace(u.Hometown) != "" },
+		expr:   nonEmptyStringExpr("hometown"),
+	},
+	{
+		key: user.FieldEducations, label: "教育经历", weight: 30,
+		filled: func(u *user.User) bool { return len(u.HomeEducations) > 0 || len(u.ShanghaiEducations) > 0 },
+		expr:   bson.M{"$or": bson.A{nonEmptyArrayExpr("home_educations"), nonEmptyArrayExpr("shanghai_educations")}},
+	},
+	{
+		key: user.FieldEmployments, label: "工作经历", weight: 30,
+		filled: func(u *user.User) bool { return len(u.Employments) > 0 },
+		expr:   nonEmptyArrayExpr("employments"),
+	},
+}
+
+func nonEmptyStringExpr(field string) bson.M {
+	return bson.M{"$gt": bson.A{bson.M{"$strLenCP": bson.M{"$trim": bson.M{"input": bson.M{"$ifNull": bson.A{"$" + field, ""}}}}}, 0}}
+}
+
+func nonEmptyArrayExpr(field string) bson.M {
+	return bson.M{"$gt": bson.A{bson.M{"$size": bson.M{"$ifNull": bson.A{"$" + field, bson.A{}}}}, 0}}
+}
+
+// profileCompleteness 计算资料完整度，Missing 按 profileSections 的顺序排列
+func profileCompleteness(u *user.User) *core_api.ProfileCompleteness {
+	result := &core_api.ProfileCompleteness{Missing: make([]string, 0)}
+	for _, section := range profileSections {
+		if section.filled(u) {
+			result.Score += section.weight
+		} else {
+			result.Missing = append(result.Missing, section.key)
+		}
+	}
+	return result
+}
+
+// CompletenessFilter 按资料完整度筛选用户，Min、Max 为闭区间，Missing 为必须缺少的部分
+type CompletenessFilter struct {
+	Min     int64  `json:"minCompleteness"`
+	Max     int64  `json:"maxCompleteness"`
+	Missing string `json:"missing"`
+}
+
+// condition 返回筛选条件，未设置任何条件时返回 nil
+func (f CompletenessFilter) condition() (bson.M, error) {
+	conditions := make([]bson.M, 0, 2)
+	if f.Min > 0 || (f.Max > 0 && f.Max < 100) {
+		upper := f.Max
+		if upper <= 0 {
+			upper = 100
+		}
+		if f.Min > upper {
+			return nil, ErrAdminBadRequest
+		}
+		score := make(bson.A, 0, len(profileSections))
+		for _, section := range profileSections {
+			score = append(score, bson.M{"$cond": bson.A{section.expr, section.weight, 0}})
+		}
+		conditions = append(conditions, bson.M{"$expr": bson.M{"$and": bson.A{
+			bson.M{"$gte": bson.A{bson.M{"$add": score}, f.Min}},
+			bson.M{"$lte": bson.A{bson.M{"$add": score}, upper}},
+		}}})
+	}
+	if f.Missing = strings.TrimSpace(f.Missing); f.Missing != "" {
+		section := findProfileSection(f.Missing)
+		if section == nil {
+			return nil, ErrAdminBadRequest
+		}
+		conditions = append(conditions, bson.M{"$expr": bson.M{"$not": bson.A{section.expr}}})
+	}
+	switch len(conditions) {
+	case 0:
+		return nil, nil
+	case 1:
+		return conditions[0], nil
+	default:
+		return bson.M{"$and": conditions}, nil
+	}
+}
+
+func findProfileSection(key string) *profileSection {
+	for i := range profileSections {
+		if profileSections[i].key == key {
+			return &profileSections[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,524 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/audit"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/dictionary"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultDictionaryLimit = int64(10)
+	maxDictionaryLimit     = int64(50)
+	// dictionaryCandidateLimit 参与联想排序的条目数量上限
+	dictionaryCandidateLimit = int64(200)
+	// maxIndustryDepth 行业层级的最大深度
+	maxIndustryDepth = 3
+	// migrationBatchSize 迁移时每批处理的用户数量
+	migrationBatchSize = int64(200)
+	// maxUnmatchedTexts 迁移结果中返回的未匹配文字数量上限
+	maxUnmatchedTexts = 50
+)
+
+type IDictionaryService interface {
+	SearchDictionary(ctx context.Context, req *core_api.SearchDictionaryReq) (*core_api.SearchDictionaryResp, error)
+	ListAdminDictionary(ctx context.Context, kind string, page, pageSize int64, keyword, parentID string) (*PageResult[AdminDictionaryEntry], error)
+	CreateDictionaryEntry(ctx context.Context, kind string, input AdminDictionaryInput) (*AdminDictionaryEntry, error)
+	UpdateDictionaryEntry(ctx context.Context, kind, id string, input AdminDictionaryInput) (*AdminDictionaryEntry, error)
+	DeleteDictionaryEntry(ctx context.Context, kind, id string) error
+	MigrateDictionary(ctx context.Context, kind string) (*MigrationResult, error)
+}
+
+type DictionaryService struct {
+	UserMapper       *user.MongoMapper
+	DictionaryMapper *dictionary.MongoMapper
+	AuditMapper      *audit.MongoMapper
+}
+
+var DictionaryServiceSet = wire.NewSet(
+	wire.Struct(new(DictionaryService), "*"),
+	wire.Bind(new(IDictionaryService), new(*DictionaryService)),
+)
+
+type AdminDictionaryEntry struct {
+	ID         string   `json:"id"`
+	Kind       string   `json:"kind"`
+	Name       string   `json:"name"`
+	Aliases    []string `json:"aliases"`
+	Region     string   `json:"region"`
+	ParentID   string   `json:"parentId"`
+	CreateTime int64    `json:"createTime"`
+	UpdateTime int64    `json:"updateTime"`
+}
+
+// AdminDictionaryInput Region 仅对学校生效，ParentID 仅对行业生效
+type AdminDictionaryInput struct {
+	Name     string   `json:"name"`
+	Aliases  []string `json:"aliases"`
+	Region   string   `json:"region"`
+	ParentID string   `json:"parentId"`
+}
+
+// MigrationResult 将自由文字迁移为结构化数据的结果，Unmatched 为出现次数最多的未匹配文字，便于补充数据后再次迁移
+type MigrationResult struct {
+	Scanned   int64           `json:"scanned"`
+	Updated   int64           `json:"updated"`
+	Matched   int64           `json:"matched"`
+	Unmatched []MigrationMiss `json:"unmatched"`
+}
+
+type MigrationMiss struct {
+	Text  string `json:"text"`
+	Count int64  `json:"count"`
+}
+
+// missCounter 统计未匹配的文字，忽略大小写和首尾空白
+type missCounter map[string]*MigrationMiss
+
+func (m missCounter) add(text string) {
+	key := strings.ToLower(strings.TrimSpace(text))
+	if m[key] == nil {
+		m[key] = &MigrationMiss{Text: strings.TrimSpace(text)}
+	}
+	m[key].Count++
+}
+
+// top 按出现次数降序返回前 n 个
+func (m missCounter) top(n int) []MigrationMiss {
+	result := make([]MigrationMiss, 0, len(m))
+	for _, item := range m {
+		result = append(result, *item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Text < result[j].Text
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+type dictionaryHit struct {
+	entry *dictionary.Entry
+	score int
+}
+
+// SearchDictionary 联想输入：名称或别名完全一致 > 名称前缀 > 别名前缀 > 包含
+func (s *DictionaryService) SearchDictionary(ctx context.Context, req *core_api.SearchDictionaryReq) (*core_api.SearchDictionaryResp, error) {
+	if adaptor.ExtractUserMeta(ctx).GetUserId() == "" {
+		return nil, consts.ErrNotAuthentication
+	}
+	if !validDictionaryKind(req.Kind) {
+		return nil, consts.ErrDictionaryKind
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultDictionaryLimit
+	}
+	if limit > maxDictionaryLimit {
+		limit = maxDictionaryLimit
+	}
+
+	keyword := strings.TrimSpace(req.Keyword)
+	filter := bson.M{"kind": req.Kind}
+	if keyword != "" {
+		regex := bson.M{"$regex": regexp.QuoteMeta(keyword), "$options": "i"}
+		filter["$or"] = []bson.M{{"name": regex}, {"aliases": regex}}
+	} else if req.Kind == dictionary.KindIndustry {
+		filter["parent_id"] = bson.M{"$in": []any{nil, ""}}
+		if parentId := strings.TrimSpace(req.ParentId); parentId != "" {
+			filter["parent_id"] = parentId
+		}
+	}
+	candidates, _, err := s.DictionaryMapper.FindMany(ctx, filter, 0, dictionaryCandidateLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]dictionaryHit, 0, len(candidates))
+	for _, candidate := range candidates {
+		hits = append(hits, dictionaryHit{entry: candidate, score: dictionaryScore(candidate, keyword)})
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].score > hits[j].score
+	})
+	if int64(len(hits)) > limit {
+		hits = hits[:limit]
+	}
+
+	parents := map[string]*dictionary.Entry{}
+	entries := make([]*core_api.DictionaryEntry, 0, len(hits))
+	for _, hit := range hits {
+		item := &core_api.DictionaryEntry{
+			Id:       hit.entry.ID.Hex(),
+			Name:     hit.entry.Name,
+			Aliases:  hit.entry.Aliases,
+			Region:   hit.entry.Region,
+			ParentId: hit.entry.ParentId,
+		}
+		if hit.entry.Kind == dictionary.KindIndustry {
+			item.Path = s.industryPath(ctx, hit.entry, parents)
+		}
+		entries = append(entries, item)
+	}
+	return &core_api.SearchDictionaryResp{Entries: entries}, nil
+}
+
+func (s *DictionaryService) ListAdminDictionary(ctx context.Context, kind string, page, pageSize int64, keyword, parentID string) (*PageResult[AdminDictionaryEntry], error) {
+	if !validDictionaryKind(kind) {
+		return nil, ErrAdminNotFound
+	}
+	page, pageSize = normalizePage(page, pageSize)
+	filter := bson.M{"kind": kind}
+	if keyword = strings.TrimSpace(keyword); keyword != "" {
+		regex := bson.M{"$regex": regexp.QuoteMeta(keyword), "$options": "i"}
+		filter["$or"] = []bson.M{{"name": regex}, {"aliases": regex}, {"region": regex}}
+	}
+	if parentID = strings.TrimSpace(parentID); parentID != "" {
+		filter["parent_id"] = parentID
+	}
+	data, total, err := s.DictionaryMapper.FindMany(ctx, filter, offset(page, pageSize), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]AdminDictionaryEntry, 0, len(data))
+	for _, item := range data {
+		items = append(items, mapAdminDictionaryEntry(item))
+	}
+	return &PageResult[AdminDictionaryEntry]{Items: items, Total: total, Page: page, PageSize: pageSize}, nil
+}
+
+func (s *DictionaryService) CreateDictionaryEntry(ctx context.Context, kind string, input AdminDictionaryInput) (*AdminDictionaryEntry, error) {
+	if !validDictionaryKind(kind) {
+		return nil, ErrAdminNotFound
+	}
+	item := &dictionary.Entry{Kind: kind}
+	if err := s.applyDictionaryInput(ctx, item, input); err != nil {
+		return nil, err
+	}
+	if err := s.DictionaryMapper.Insert(ctx, item); err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, s.AuditMapper, "dictionary.create", AuditDictionary, item.ID.Hex(), nil, snapshot(item))
+	result := mapAdminDictionaryEntry(item)
+	return &result, nil
+}
+
+func (s *DictionaryService) UpdateDictionaryEntry(ctx context.Context, kind, id string, input AdminDictionaryInput) (*AdminDictionaryEntry, error) {
+	item, err := s.findEntry(ctx, kind, id)
+	if err != nil {
+		return nil, err
+	}
+	before := snapshot(item)
+	if err = s.applyDictionaryInput(ctx, item, input); err != nil {
+		return nil, err
+	}
+	if err = s.DictionaryMapper.Update(ctx, item); err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, s.AuditMapper, "dictionary.update", AuditDictionary, item.ID.Hex(), before, snapshot(item))
+	result := mapAdminDictionaryEntry(item)
+	return &result, nil
+}
+
+// DeleteDictionaryEntry 仍有下级行业或仍被用户资料引用时拒绝删除
+func (s *DictionaryService) DeleteDictionaryEntry(ctx context.Context, kind, id string) error {
+	item, err := s.findEntry(ctx, kind, id)
+	if err != nil {
+		return err
+	}
+	if kind == dictionary.KindIndustry {
+		_, children, err := s.DictionaryMapper.FindMany(ctx, bson.M{"kind": kind, "parent_id": id}, 0, 1)
+		if err != nil {
+			return err
+		}
+		if children > 0 {
+			return ErrAdminBadRequest
+		}
+	}
+	_, total, err := s.UserMapper.FindMany(ctx, dictionaryRefFilter(kind, id), 0, 1)
+	if err != nil {
+		return err
+	}
+	if total > 0 {
+		return ErrAdminBadRequest
+	}
+	deleted, err := s.DictionaryMapper.Delete(ctx, item.ID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrAdminNotFound
+	}
+	recordAudit(ctx, s.AuditMapper, "dictionary.delete", AuditDictionary, item.ID.Hex(), snapshot(item), nil)
+	return nil
+}
+
+// MigrateDictionary 为尚未关联字典的教育或工作经历匹配字典条目，可在补充别名后重复执行
+func (s *DictionaryService) MigrateDictionary(ctx context.Context, kind string) (*MigrationResult, error) {
+	if !validDictionaryKind(kind) {
+		return nil, ErrAdminNotFound
+	}
+	result := &MigrationResult{}
+	resolved := map[string]string{}
+	misses := missCounter{}
+	resolve := func(text string) (string, error) {
+		key := strings.ToLower(strings.TrimSpace(text))
+		if id, ok := resolved[key]; ok {
+			return id, nil
+		}
+		id, err := resolveDictionaryId(ctx, s.DictionaryMapper, kind, text)
+		if err != nil {
+			return "", err
+		}
+		resolved[key] = id
+		return id, nil
+	}
+
+	var after primitive.ObjectID
+	for {
+		users, err := s.UserMapper.FindAfter(ctx, pendingDictionaryFilter(kind), after, migrationBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			result.Scanned++
+			changed := false
+			if kind == dictionary.KindSchool {
+				for _, educations := range [][]user.Education{u.HomeEducations, u.ShanghaiEducations} {
+					for i := range educations {
+						if educations[i].SchoolId != "" || strings.TrimSpace(educations[i].School) == "" {
+							continue
+						}
+						if educations[i].SchoolId, err = resolve(educations[i].School); err != nil {
+							return nil, err
+						}
+						if educations[i].SchoolId == "" {
+							misses.add(educations[i].School)
+							continue
+						}
+						result.Matched++
+						changed = true
+					}
+				}
+				if changed {
+					err = s.UserMapper.UpdateEducations(ctx, u.ID, u.HomeEducations, u.ShanghaiEducations)
+				}
+			} else {
+				for i := range u.Employments {
+					if u.Employments[i].IndustryId != "" || strings.TrimSpace(u.Employments[i].Industry) == "" {
+						continue
+					}
+					if u.Employments[i].IndustryId, err = resolve(u.Employments[i].Industry); err != nil {
+						return nil, err
+					}
+					if u.Employments[i].IndustryId == "" {
+						misses.add(u.Employments[i].Industry)
+						continue
+					}
+					result.Matched++
+					changed = true
+				}
+				if changed {
+					err = s.UserMapper.UpdateEmployments(ctx, u.ID, u.Employments)
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+			if changed {
+				result.Updated++
+			}
+		}
+		if int64(len(users)) < migrationBatchSize {
+			break
+		}
+		after = users[len(users)-1].ID
+	}
+
+	result.Unmatched = misses.top(maxUnmatchedTexts)
+	recordAudit(ctx, s.AuditMapper, "dictionary.migrate", AuditDictionary, kind, nil, snapshot(result))
+	return result, nil
+}
+
+func (s *DictionaryService) findEntry(ctx context.Context, kind, id string) (*dictionary.Entry, error) {
+	item, err := s.DictionaryMapper.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item.Kind != kind {
+		return nil, ErrAdminNotFound
+	}
+	return item, nil
+}
+
+// applyDictionaryInput 校验并写入名称、别名及层级，名称和别名不能与同类型的其他条目重复
+func (s *DictionaryService) applyDictionaryInput(ctx context.Context, item *dictionary.Entry, input AdminDictionaryInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return ErrAdminBadRequest
+	}
+	aliases := make([]string, 0, len(input.Aliases))
+	seen := map[string]bool{strings.ToLower(name): true}
+	for _, alias := range input.Aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" || seen[strings.ToLower(alias)] {
+			continue
+		}
+		seen[strings.ToLower(alias)] = true
+		aliases = append(aliases, alias)
+	}
+	for _, text := range append([]string{name}, aliases...) {
+		existing, err := s.DictionaryMapper.FindByText(ctx, item.Kind, text)
+		if err == nil && existing.ID != item.ID {
+			return ErrAdminBadRequest
+		}
+		if err != nil && err != consts.ErrNotFound {
+			return err
+		}
+	}
+
+	item.Name, item.Aliases = name, aliases
+	item.Region, item.ParentId = "", ""
+	switch item.Kind {
+	case dictionary.KindSchool:
+		item.Region = strings.TrimSpace(input.Region)
+	case dictionary.KindIndustry:
+		parentID := strings.TrimSpace(input.ParentID)
+		if parentID != "" {
+			if err := s.checkIndustryParent(ctx, item, parentID); err != nil {
+				return err
+			}
+		}
+		item.ParentId = parentID
+	}
+	return nil
+}
+
+// checkIndustryParent 上级须为已存在的行业，且不能形成环或超过最大层级
+func (s *DictionaryService) checkIndustryParent(ctx context.Context, item *dictionary.Entry, parentID string) error {
+	depth := 1
+	for id := parentID; id != ""; depth++ {
+		if depth >= maxIndustryDepth || (!item.ID.IsZero() && id == item.ID.Hex()) {
+			return ErrAdminBadRequest
+		}
+		parent, err := s.DictionaryMapper.FindByID(ctx, id)
+		if err == consts.ErrNotFound || err == consts.ErrInvalidObjectId {
+			return ErrAdminBadRequest
+		}
+		if err != nil {
+			return err
+		}
+		if parent.Kind != dictionary.KindIndustry {
+			return ErrAdminBadRequest
+		}
+		id = parent.ParentId
+	}
+	return nil
+}
+
+// industryPath 从一级行业到自身的名称，parents 缓存已查询的上级
+func (s *DictionaryService) industryPath(ctx context.Context, entry *dictionary.Entry, parents map[string]*dictionary.Entry) []string {
+	path := []string{entry.Name}
+	for id, depth := entry.ParentId, 1; id != "" && depth < maxIndustryDepth; depth++ {
+		parent, ok := parents[id]
+		if !ok {
+			found, err := s.DictionaryMapper.FindByID(ctx, id)
+			if err != nil {
+				break
+			}
+			parent, parents[id] = found, found
+		}
+		path = append([]string{parent.Name}, path...)
+		id = parent.ParentId
+	}
+	return path
+}
+
+// resolveDictionaryId 返回与文字匹配的字典条目 id，匹配不到时返回空
+func resolveDictionaryId(ctx context.Context, dictionaryMapper *dictionary.MongoMapper, kind, text string) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		return "", nil
+	}
+	entry, err := dictionaryMapper.FindByText(ctx, kind, text)
+	if err == consts.ErrNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return entry.ID.Hex(), nil
+}
+
+func dictionaryScore(entry *dictionary.Entry, keyword string) int {
+	if keyword == "" {
+		return 0
+	}
+	score := 0
+	for i, text := range append([]string{entry.Name}, entry.Aliases...) {
+		switch {
+		case sameText(text, keyword):
+			score = max(score, 4)
+		case strings.HasPrefix(strings.ToLower(text), strings.ToLower(keyword)) && i == 0:
+			score = max(score, 3)
+		case strings.HasPrefix(strings.ToLower(text), strings.ToLower(keyword)):
+			score = max(score, 2)
+		case containsFold(text, keyword):
+			score = max(score, 1)
+		}
+	}
+	return score
+}
+
+// dictionaryRefFilter 引用了字典条目的用户
+func dictionaryRefFilter(kind, id string) bson.M {
+	if kind == dictionary.KindSchool {
+		return bson.M{"$or": []bson.M{{"home_educations.school_id": id}, {"shanghai_educations.school_id": id}}}
+	}
+	return bson.M{"employments.industry_id": id}
+}
+
+// pendingDictionaryFilter 仍有经历填写了文字但未关联字典的用户
+func pendingDictionaryFilter(kind string) bson.M {
+	unset := bson.M{"$in": []any{nil, ""}}
+	filled := bson.M{"$nin": []any{nil, ""}}
+	if kind == dictionary.KindSchool {
+		pending := bson.M{"$elemMatch": bson.M{"school": filled, "school_id": unset}}
+		return bson.M{"$or": []bson.M{{"home_educations": pending}, {"shanghai_educations": pending}}}
+	}
+	return bson.M{"employments": bson.M{"$elemMatch": bson.M{"industry": filled, "industry_id": unset}}}
+}
+
+func validDictionaryKind(kind string) bool {
+	return kind == dictionary.KindSchool || kind == dictionary.KindIndustry
+}
+
+func mapAdminDictionaryEntry(item *dictionary.Entry) AdminDictionaryEntry {
+	aliases := item.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+	return AdminDictionaryEntry{
+		ID:         item.ID.Hex(),
+		Kind:       item.Kind,
+		Name:       item.Name,
+		Aliases:    aliases,
+		Region:     item.Region,
+		ParentID:   item.ParentId,
+		CreateTime: timeToUnix(item.CreateTime),
+		UpdateTime: timeToUnix(item.UpdateTime),
+	}
+}
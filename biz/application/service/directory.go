@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/dictionary"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/region"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// directoryCandidateLimit 参与相关度排序的候选人数上限，超出部分按注册时间截断，并在结果中标记 Capped
+const directoryCandidateLimit = int64(500)
+
+type IDirectoryService interface {
+	SearchDirectory(ctx context.Context, req *core_api.SearchDirectoryReq) (*core_api.SearchDirectoryResp, error)
+	GetDirectorySetting(ctx context.Context, _ *core_api.GetDirectorySettingReq) (*core_api.DirectorySetting, error)
+	UpdateDirectorySetting(ctx context.Context, req *core_api.DirectorySetting) (*core_api.Response, error)
+}
+
+type DirectoryService struct {
+	UserMapper       *user.MongoMapper
+	DictionaryMapper *dictionary.MongoMapper
+	Regions          *region.Dataset
+}
+
+var DirectoryServiceSet = wire.NewSet(
+	wire.Struct(new(DirectoryService), "*"),
+	wire.Bind(new(IDirectoryService), new(*DirectoryService)),
+)
+
+// directoryRefs 学校、行业检索条件匹配到的字典条目，用于找到同一学校或行业的其他写法；
+// hometownPrefix 为家乡条件匹配到的行政区划代码前缀，用于找到该地区及其下级区划的校友
+type directoryRefs struct {
+	schoolId       string
+	industryId     string
+	hometownPrefix string
+}
+
+type directoryHit struct {
+	user    *user.User
+	score   int
+	matched []string
+}
+
+func (s *DirectoryService) SearchDirectory(ctx context.Context, req *core_api.SearchDirectoryReq) (*core_api.SearchDirectoryResp, error) {
+	userMeta := adaptor.ExtractUserMeta(ctx)
+	if userMeta.GetUserId() == "" {
+		return nil, consts.ErrNotAuthentication
+	}
+	page, pageSize := normalizePage(req.Page, req.PageSize)
+	v := loadViewer(ctx, s.UserMapper)
+	var refs directoryRefs
+	var err error
+	if refs.schoolId, err = resolveDictionaryId(ctx, s.DictionaryMapper, dictionary.KindSchool, req.School); err != nil {
+		return nil, err
+	}
+	if refs.industryId, err = resolveDictionaryId(ctx, s.DictionaryMapper, dictionary.KindIndustry, req.Industry); err != nil {
+		return nil, err
+	}
+	if r := s.Regions.Get(s.Regions.Resolve(req.Hometown)); r != nil {
+		refs.hometownPrefix = r.Prefix()
+	}
+
+	filter := bson.M{"$and": directoryConditions(v, req, refs)}
+	candidates, matched, err := s.UserMapper.FindMany(ctx, filter, 0, directoryCandidateLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]directoryHit, 0, len(candidates))
+	for _, candidate := range candidates {
+		hits = append(hits, scoreDirectoryUser(v, candidate, req, refs))
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].score > hits[j].score
+	})
+
+	total := int64(len(hits))
+	start, end := offset(page, pageSize), offset(page, pageSize)+pageSize
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+	users := make([]*core_api.DirectoryUser, 0, end-start)
+	for _, hit := range hits[start:end] {
+		item := mapDirectoryUser(v, hit.user)
+		item.Matched = hit.matched
+		users = append(users, item)
+	}
+	return &core_api.SearchDirectoryResp{Total: total, Capped: matched > int64(len(candidates)), Users: users}, nil
+}
+
+func (s *DirectoryService) GetDirectorySetting(ctx context.Context, _ *core_api.GetDirectorySettingReq) (*core_api.DirectorySetting, error) {
+	userMeta := adaptor.ExtractUserMeta(ctx)
+	if userMeta.GetUserId() == "" {
+		return nil, consts.ErrNotAuthentication
+	}
+	aUser, err := s.UserMapper.FindOne(ctx, userMeta.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	return &core_api.DirectorySetting{Listed: aUser.Directory.Listed}, nil
+}
+
+func (s *DirectoryService) UpdateDirectorySetting(ctx context.Context, req *core_api.DirectorySetting) (*core_api.Response, error) {
+	userMeta := adaptor.ExtractUserMeta(ctx)
+	if userMeta.GetUserId() == "" {
+		return nil, consts.ErrNotAuthentication
+	}
+	aUser, err := s.UserMapper.FindOne(ctx, userMeta.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if err = s.UserMapper.UpdateDirectory(ctx, aUser.ID, user.Directory{Listed: req.Listed}); err != nil {
+		return nil, consts.ErrUpdate
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "更新成功",
+	}, nil
+}
+
+// directoryConditions 构造检索条件，针对某个字段分组的条件只匹配对查看者可见该分组的用户，避免通过检索反推未公开信息
+func directoryConditions(v *viewer, req *core_api.SearchDirectoryReq, refs directoryRefs) []bson.M {
+	conditions := []bson.M{
+		{"directory.listed": true},
+		{"$or": []bson.M{{consts.Status: int64(0)}, {consts.Status: bson.M{"$exists": false}}}},
+		{"$or": []bson.M{{consts.DeleteTime: bson.M{"$exists": false}}, {consts.DeleteTime: time.Time{}}}},
+	}
+	if oid, err := primitive.ObjectIDFromHex(v.userId); err == nil {
+		conditions = append(conditions, bson.M{consts.ID: bson.M{"$ne": oid}})
+	}
+	shared := func(field string, cond bson.M) bson.M {
+		return bson.M{"$and": []bson.M{v.fieldCondition(field), cond}}
+	}
+	regex := func(value string) bson.M {
+		return bson.M{"$regex": regexp.QuoteMeta(value), "$options": "i"}
+	}
+	schoolCond := func(value string) bson.M {
+		return shared(user.FieldEducations, bson.M{"$or": []bson.M{
+			{"home_educations.school": regex(value)},
+			{"shanghai_educations.school": regex(value)},
+		}})
+	}
+
+	if keyword := strings.TrimSpace(req.Keyword); keyword != "" {
+		conditions = append(conditions, bson.M{"$or": []bson.M{
+			{consts.Name: regex(keyword)},
+			shared(user.FieldHometown, bson.M{"hometown": regex(keyword)}),
+			schoolCond(keyword),
+			shared(user.FieldEmployments, bson.M{"$or": []bson.M{
+				{"employments.organization": regex(keyword)},
+				{"employments.industry": regex(keyword)},
+			}}),
+		}})
+	}
+	if name := strings.TrimSpace(req.Name); name != "" {
+		conditions = append(conditions, bson.M{consts.Name: regex(name)})
+	}
+	if hometown := strings.TrimSpace(req.Hometown); hometown != "" {
+		cond := bson.M{"hometown": regex(hometown)}
+		if refs.hometownPrefix != "" {
+			cond = bson.M{"$or": []bson.M{cond, {"hometown_code": bson.M{"$regex": "^" + regexp.QuoteMeta(refs.hometownPrefix)}}}}
+		}
+		conditions = append(conditions, shared(user.FieldHometown, cond))
+	}
+	if school := strings.TrimSpace(req.School); school != "" {
+		cond := schoolCond(school)
+		if refs.schoolId != "" {
+			cond = shared(user.FieldEducations, bson.M{"$or": []bson.M{
+				{"home_educations.school": regex(school)},
+				{"shanghai_educations.school": regex(school)},
+				{"home_educations.school_id": refs.schoolId},
+				{"shanghai_educations.school_id": refs.schoolId},
+			}})
+		}
+		conditions = append(conditions, cond)
+	}
+	if req.Year > 0 {
+		conditions = append(conditions, shared(user.FieldEducations, bson.M{"$or": []bson.M{
+			{"home_educations.year": req.Year},
+			{"shanghai_educations.year": req.Year},
+		}}))
+	}
+	if organization := strings.TrimSpace(req.Organization); organization != "" {
+		conditions = append(conditions, shared(user.FieldEmployments, bson.M{"employments.organization": regex(organization)}))
+	}
+	if industry := strings.TrimSpace(req.Industry); industry != "" {
+		cond := bson.M{"employments.industry": regex(industry)}
+		if refs.industryId != "" {
+			cond = bson.M{"$or": []bson.M{cond, {"employments.industry_id": refs.industryId}}}
+		}
+		conditions = append(conditions, shared(user.FieldEmployments, cond))
+	}
+	return conditions
+}
+
+// scoreDirectoryUser 计算相关度：姓名精确 > 前缀 > 包含，其次为家乡、学校、单位、行业命中
+func scoreDirectoryUser(v *viewer, u *user.User, req *core_api.SearchDirectoryReq, refs directoryRefs) directoryHit {
+	hit := directoryHit{user: u}
+	add := func(score int, matched string) {
+		hit.score += score
+		for _, m := range hit.matched {
+			if m == matched {
+				return
+			}
+		}
+		hit.matched = append(hit.matched, matched)
+	}
+
+	for _, name := range []string{req.Keyword, req.Name} {
+		if score := nameScore(u.Name, name); score > 0 {
+			add(score, "name")
+		}
+	}
+	if v.canSee(u, user.FieldHometown) {
+		for _, hometown := range []string{req.Keyword, req.Hometown} {
+			if containsFold(u.Hometown, hometown) {
+				add(3, user.FieldHometown)
+			}
+		}
+		if refs.hometownPrefix != "" && strings.HasPrefix(u.HometownCode, refs.hometownPrefix) {
+			add(3, user.FieldHometown)
+		}
+	}
+	if v.canSee(u, user.FieldEducations) {
+		for _, edu := range append(append([]user.Education{}, u.HomeEducations...), u.ShanghaiEducations...) {
+			if containsFold(edu.School, req.Keyword) || containsFold(edu.School, req.School) || (refs.schoolId != "" && edu.SchoolId == refs.schoolId) {
+				add(3, "school")
+			}
+			if req.Year > 0 && edu.Year == req.Year {
+				add(2, "year")
+			}
+		}
+	}
+	if v.canSee(u, user.FieldEmployments) {
+		for _, em := range u.Employments {
+			if containsFold(em.Organization, req.Keyword) || containsFold(em.Organization, req.Organization) {
+				add(3, "organization")
+			}
+			if containsFold(em.Industry, req.Keyword) || containsFold(em.Industry, req.Industry) || (refs.industryId != "" && em.IndustryId == refs.industryId) {
+				add(2, "industry")
+			}
+		}
+	}
+	if hit.matched == nil {
+		hit.matched = []string{}
+	}
+	return hit
+}
+
+func nameScore(name, query string) int {
+	name, query = strings.ToLower(strings.TrimSpace(name)), strings.ToLower(strings.TrimSpace(query))
+	switch {
+	case query == "" || name == "":
+		return 0
+	case name == query:
+		return 10
+	case strings.HasPrefix(name, query):
+		return 6
+	case strings.Contains(name, query):
+		return 4
+	default:
+		return 0
+	}
+}
+
+func containsFold(value, query string) bool {
+	query = strings.TrimSpace(query)
+	return query != "" && strings.Contains(strings.ToLower(value), strings.ToLower(query))
+}
+
+func mapDirectoryUser(v *viewer, u *user.User) *core_api.DirectoryUser {
+	masked := v.redact(u)
+	item := &core_api.DirectoryUser{
+		Id:           masked.ID.Hex(),
+		Avatar:       masked.Avatar,
+		Name:         masked.Name,
+		Hometown:     masked.Hometown,
+		HometownCode: masked.HometownCode,
+		Phone:        masked.Phone,
+		WxId:         masked.WxId,
+		Birthday:     timeToUnix(masked.Birthday),
+	}
+	if v.canSee(u, user.FieldEducations) {
+		item.HometownEducations = mapEducations(masked.HomeEducations)
+		item.ShanghaiEducations = mapEducations(masked.ShanghaiEducations)
+	}
+	if v.canSee(u, user.FieldEmployments) {
+		item.Employments = mapEmployments(masked.Employments)
+	}
+	return item
+}
+
+func mapEducations(educations []user.Education) []*core_api.Education {
+	result := make([]*core_api.Education, 0, len(educations))
+	for _, edu := range educations {
+		result = append(result, &core_api.Education{Phase: edu.Phase, School: edu.School, Year: edu.Year})
+	}
+	return result
+}
+
+func mapEmployments(employments []user.Employment) []*core_api.Employment {
+	result := make([]*core_api.Employment, 0, len(employments))
+	for _, em := range employments {
+		result = append(result, &core_api.Employment{
+			Organization: em.Organization,
+			Position:     em.Position,
+			Industry:     em.Industry,
+			Entry:        em.Entry,
+			Departure:    em.Departure,
+		})
+	}
+	return result
+}
@@ -0,0 +1,776 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/activity"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/audit"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/dictionary"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/group"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/group_announcement"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/group_member"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/notification"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/region"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// announcementBatchSize 发布公告时每批通知的成员数量
+const announcementBatchSize = int64(200)
+
+type IGroupService interface {
+	CreateGroup(ctx context.Context, req *core_api.CreateGroupReq) (*core_api.Group, error)
+	UpdateGroup(ctx context.Context, req *core_api.UpdateGroupReq) (*core_api.Group, error)
+	SearchGroups(ctx context.Context, req *core_api.SearchGroupsReq) (*core_api.GetGroupsResp, error)
+	GetGroup(ctx context.Context, req *core_api.GetGroupReq) (*core_api.Group, error)
+	JoinGroup(ctx context.Context, req *core_api.JoinGroupReq) (*core_api.Group, error)
+	LeaveGroup(ctx context.Context, req *core_api.LeaveGroupReq) (*core_api.Response, error)
+	InviteGroupMember(ctx context.Context, req *core_api.InviteGroupMemberReq) (*core_api.Response, error)
+	ReviewGroupMember(ctx context.Context, req *core_api.ReviewGroupMemberReq) (*core_api.Response, error)
+	SetGroupMemberRole(ctx context.Context, req *core_api.SetGroupMemberRoleReq) (*core_api.Response, error)
+	RemoveGroupMember(ctx context.Context, req *core_api.RemoveGroupMemberReq) (*core_api.Response, error)
+	GetGroupMembers(ctx context.Context, req *core_api.GetGroupMembersReq) (*core_api.GetGroupMembersResp, error)
+	CreateGroupAnnouncement(ctx context.Context, req *core_api.CreateGroupAnnouncementReq) (*core_api.GroupAnnouncement, error)
+	GetGroupAnnouncements(ctx context.Context, req *core_api.GetGroupAnnouncementsReq) (*core_api.GetGroupAnnouncementsResp, error)
+	DeleteGroupAnnouncement(ctx context.Context, req *core_api.DeleteGroupAnnouncementReq) (*core_api.Response, error)
+	ListAdminGroups(ctx context.Context, page, pageSize int64, keyword, category, status string) (*PageResult[AdminGroup], error)
+	DeleteGroup(ctx context.Context, id string) error
+	RestoreGroup(ctx context.Context, id string) error
+	SetActivityGroup(ctx context.Context, activityID, groupID string) error
+}
+
+type GroupService struct {
+	UserMapper              *user.MongoMapper
+	GroupMapper             *group.MongoMapper
+	GroupMemberMapper       *group_member.MongoMapper
+	GroupAnnouncementMapper *group_announcement.MongoMapper
+	ActivityMapper          *activity.MongoMapper
+	DictionaryMapper        *dictionary.MongoMapper
+	NotificationMapper      *notification.MongoMapper
+	AuditMapper             *audit.MongoMapper
+	Regions                 *region.Dataset
+}
+
+var GroupServiceSet = wire.NewSet(
+	wire.Struct(new(GroupService), "*"),
+	wire.Bind(new(IGroupService), new(*GroupService)),
+)
+
+type AdminGroup struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Category    string `json:"category"`
+	RefID       string `json:"refId"`
+	Description string `json:"description"`
+	Policy      string `json:"policy"`
+	CreatorID   string `json:"creatorId"`
+	CreatorName string `json:"creatorName"`
+	MemberCount int64  `json:"memberCount"`
+	Deleted     bool   `json:"deleted"`
+	CreateTime  int64  `json:"createTime"`
+}
+
+// CreateGroup 认证校友创建群组，创建人成为管理员
+func (s *GroupService) CreateGroup(ctx context.Context, req *core_api.CreateGroupReq) (*core_api.Group, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	if !v.verified {
+		return nil, consts.ErrGroupNotVerified
+	}
+	g := &group.Group{
+		Name:        strings.TrimSpace(req.Name),
+		Category:    strings.TrimSpace(req.Category),
+		RefId:       strings.TrimSpace(req.RefId),
+		Avatar:      strings.TrimSpace(req.Avatar),
+		Description: strings.TrimSpace(req.Description),
+		Policy:      strings.TrimSpace(req.Policy),
+		CreatorId:   v.userId,
+		Status:      consts.EffectStatus,
+	}
+	if g.Policy == "" {
+		g.Policy = group.PolicyOpen
+	}
+	if g.Name == "" || !validGroupPolicy(g.Policy) {
+		return nil, consts.ErrGroupInvalid
+	}
+	if err = s.checkGroupRef(ctx, g.Category, g.RefId); err != nil {
+		return nil, err
+	}
+	if err = s.GroupMapper.Insert(ctx, g); err != nil {
+		return nil, consts.ErrCreate
+	}
+	now := time.Now()
+	owner := &group_member.Member{
+		GroupId:  g.ID.Hex(),
+		UserId:   v.userId,
+		Role:     group_member.RoleAdmin,
+		Status:   group_member.StatusActive,
+		JoinTime: now,
+	}
+	if err = s.GroupMemberMapper.Insert(ctx, owner); err != nil {
+		return nil, consts.ErrCreate
+	}
+	return mapGroup(g, owner, 1), nil
+}
+
+func (s *GroupService) UpdateGroup(ctx context.Context, req *core_api.UpdateGroupReq) (*core_api.Group, error) {
+	_, g, self, err := s.requireGroupAdmin(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	g.Name = strings.TrimSpace(req.Name)
+	g.Avatar = strings.TrimSpace(req.Avatar)
+	g.Description = strings.TrimSpace(req.Description)
+	g.Policy = strings.TrimSpace(req.Policy)
+	if g.Name == "" || !validGroupPolicy(g.Policy) {
+		return nil, consts.ErrGroupInvalid
+	}
+	if err = s.GroupMapper.Update(ctx, g); err != nil {
+		return nil, consts.ErrUpdate
+	}
+	counts, err := s.GroupMemberMapper.CountActive(ctx, []string{g.ID.Hex()})
+	if err != nil {
+		return nil, err
+	}
+	return mapGroup(g, self, counts[g.ID.Hex()]), nil
+}
+
+func (s *GroupService) SearchGroups(ctx context.Context, req *core_api.SearchGroupsReq) (*core_api.GetGroupsResp, error) {
+	userId := adaptor.ExtractUserMeta(ctx).GetUserId()
+	page, pageSize := normalizePage(req.Page, req.PageSize)
+	filter := bson.M{consts.Status: bson.M{"$ne": int64(consts.DeleteStatus)}}
+	if keyword := strings.TrimSpace(req.Keyword); keyword != "" {
+		pattern := bson.M{"$regex": regexp.QuoteMeta(keyword), "$options": "i"}
+		filter["$or"] = []bson.M{{consts.Name: pattern}, {"description": pattern}}
+	}
+	if category := strings.TrimSpace(req.Category); category != "" {
+		filter[consts.Category] = category
+	}
+	if refId := strings.TrimSpace(req.RefId); refId != "" {
+		filter[consts.RefId] = refId
+	}
+	if req.Mine {
+		if userId == "" {
+			return nil, consts.ErrNotAuthentication
+		}
+		groupIds, err := s.GroupMemberMapper.FindGroupIds(ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+		oids := make([]primitive.ObjectID, 0, len(groupIds))
+		for _, id := range groupIds {
+			if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+				oids = append(oids, oid)
+			}
+		}
+		filter[consts.ID] = bson.M{"$in": oids}
+	}
+	data, total, err := s.GroupMapper.FindMany(ctx, filter, offset(page, pageSize), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.mapGroups(ctx, userId, data)
+	if err != nil {
+		return nil, err
+	}
+	return &core_api.GetGroupsResp{Total: total, Groups: groups}, nil
+}
+
+func (s *GroupService) GetGroup(ctx context.Context, req *core_api.GetGroupReq) (*core_api.Group, error) {
+	g, err := s.findGroup(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.mapGroups(ctx, adaptor.ExtractUserMeta(ctx).GetUserId(), []*group.Group{g})
+	if err != nil {
+		return nil, err
+	}
+	return groups[0], nil
+}
+
+func (s *GroupService) JoinGroup(ctx context.Context, req *core_api.JoinGroupReq) (*core_api.Group, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	g, err := s.findGroup(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.GroupMemberMapper.FindOne(ctx, req.Id, v.userId)
+	switch {
+	case err == nil && member.Status == group_member.StatusInvited:
+		if err = s.transitMember(ctx, member, group_member.StatusInvited, bson.M{
+			consts.Status: group_member.StatusActive,
+			"join_time":   time.Now(),
+		}); err != nil {
+			return nil, err
+		}
+	case err == nil:
+		return nil, consts.ErrGroupJoined
+	case !errors.Is(err, consts.ErrNotFound):
+		return nil, err
+	case g.Policy == group.PolicyInvite:
+		return nil, consts.ErrGroupInviteOnly
+	default:
+		member = &group_member.Member{GroupId: req.Id, UserId: v.userId, Role: group_member.RoleMember, Status: group_member.StatusPending}
+		if g.Policy == group.PolicyOpen {
+			member.Status, member.JoinTime = group_member.StatusActive, time.Now()
+		}
+		if err = s.GroupMemberMapper.Insert(ctx, member); err != nil {
+			return nil, consts.ErrCreate
+		}
+	}
+	return s.GetGroup(ctx, &core_api.GetGroupReq{Id: req.Id})
+}
+
+// LeaveGroup 最后一名管理员不能退出，需先指定其他管理员
+func (s *GroupService) LeaveGroup(ctx context.Context, req *core_api.LeaveGroupReq) (*core_api.Response, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.GroupMemberMapper.FindOne(ctx, req.Id, v.userId)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.checkLastAdmin(ctx, member); err != nil {
+		return nil, err
+	}
+	if _, err = s.GroupMemberMapper.Delete(ctx, member.ID); err != nil {
+		return nil, err
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "已退出",
+	}, nil
+}
+
+// InviteGroupMember 管理员邀请校友加入，对方已申请加入时直接通过
+func (s *GroupService) InviteGroupMember(ctx context.Context, req *core_api.InviteGroupMemberReq) (*core_api.Response, error) {
+	v, g, _, err := s.requireGroupAdmin(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	invitee, err := s.UserMapper.FindOne(ctx, req.UserId)
+	if err != nil || !isLiveUser(invitee) {
+		return nil, consts.ErrNotFound
+	}
+	member, err := s.GroupMemberMapper.FindOne(ctx, req.Id, req.UserId)
+	switch {
+	case err == nil && member.Status == group_member.StatusPending:
+		if err = s.transitMember(ctx, member, group_member.StatusPending, bson.M{
+			consts.Status: group_member.StatusActive,
+			"join_time":   time.Now(),
+		}); err != nil {
+			return nil, err
+		}
+	case err == nil:
+		return nil, consts.ErrGroupJoined
+	case !errors.Is(err, consts.ErrNotFound):
+		return nil, err
+	default:
+		member = &group_member.Member{
+			GroupId:   req.Id,
+			UserId:    req.UserId,
+			Role:      group_member.RoleMember,
+			Status:    group_member.StatusInvited,
+			InviterId: v.userId,
+		}
+		if err = s.GroupMemberMapper.Insert(ctx, member); err != nil {
+			return nil, consts.ErrCreate
+		}
+		if err = s.NotificationMapper.InsertMany(ctx, []*notification.Notification{{
+			UserId:  req.UserId,
+			Type:    notification.TypeGroupInvite,
+			Title:   "群组邀请",
+			Content: v.self.Name + " 邀请你加入「" + g.Name + "」",
+		}}); err != nil {
+			return nil, err
+		}
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "已邀请",
+	}, nil
+}
+
+func (s *GroupService) ReviewGroupMember(ctx context.Context, req *core_api.ReviewGroupMemberReq) (*core_api.Response, error) {
+	if _, _, _, err := s.requireGroupAdmin(ctx, req.Id); err != nil {
+		return nil, err
+	}
+	member, err := s.GroupMemberMapper.FindOne(ctx, req.Id, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if member.Status != group_member.StatusPending {
+		return nil, consts.ErrGroupMemberStatus
+	}
+	if req.Approve {
+		err = s.transitMember(ctx, member, group_member.StatusPending, bson.M{
+			consts.Status: group_member.StatusActive,
+			"join_time":   time.Now(),
+		})
+	} else {
+		_, err = s.GroupMemberMapper.Delete(ctx, member.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "处理成功",
+	}, nil
+}
+
+func (s *GroupService) SetGroupMemberRole(ctx context.Context, req *core_api.SetGroupMemberRoleReq) (*core_api.Response, error) {
+	if req.Role != group_member.RoleAdmin && req.Role != group_member.RoleMember {
+		return nil, consts.ErrGroupInvalid
+	}
+	if _, _, _, err := s.requireGroupAdmin(ctx, req.Id); err != nil {
+		return nil, err
+	}
+	member, err := s.GroupMemberMapper.FindOne(ctx, req.Id, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if member.Status != group_member.StatusActive {
+		return nil, consts.ErrGroupMemberStatus
+	}
+	if req.Role == group_member.RoleMember {
+		if err = s.checkLastAdmin(ctx, member); err != nil {
+			return nil, err
+		}
+	}
+	if err = s.transitMember(ctx, member, group_member.StatusActive, bson.M{"role": req.Role}); err != nil {
+		return nil, err
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "设置成功",
+	}, nil
+}
+
+// RemoveGroupMember 管理员移除成员或撤回邀请
+func (s *GroupService) RemoveGroupMember(ctx context.Context, req *core_api.RemoveGroupMemberReq) (*core_api.Response, error) {
+	if _, _, _, err := s.requireGroupAdmin(ctx, req.Id); err != nil {
+		return nil, err
+	}
+	member, err := s.GroupMemberMapper.FindOne(ctx, req.Id, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.checkLastAdmin(ctx, member); err != nil {
+		return nil, err
+	}
+	if _, err = s.GroupMemberMapper.Delete(ctx, member.ID); err != nil {
+		return nil, err
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "已移除",
+	}, nil
+}
+
+// GetGroupMembers 正式成员可查看成员列表，待审批和待接受邀请的名单仅管理员可查看
+func (s *GroupService) GetGroupMembers(ctx context.Context, req *core_api.GetGroupMembersReq) (*core_api.GetGroupMembersResp, error) {
+	v, _, self, err := s.requireGroupMember(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	status := strings.TrimSpace(req.Status)
+	if status == "" {
+		status = group_member.StatusActive
+	}
+	if status != group_member.StatusActive && self.Role != group_member.RoleAdmin {
+		return nil, consts.ErrGroupNotAdmin
+	}
+	page, pageSize := normalizePage(req.Page, req.PageSize)
+	data, total, err := s.GroupMemberMapper.FindMany(ctx, bson.M{consts.GroupId: req.Id, consts.Status: status}, offset(page, pageSize), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	userIds := make([]string, 0, len(data))
+	for _, item := range data {
+		userIds = append(userIds, item.UserId)
+	}
+	users, err := findUsers(ctx, s.UserMapper, userIds)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]*core_api.GroupMember, 0, len(data))
+	for _, item := range data {
+		u, ok := users[item.UserId]
+		if !ok || !isLiveUser(u) {
+			continue
+		}
+		members = append(members, &core_api.GroupMember{
+			DirectoryUser: mapDirectoryUser(v, u),
+			Role:          item.Role,
+			Status:        item.Status,
+			JoinTime:      timeToUnix(item.JoinTime),
+		})
+	}
+	return &core_api.GetGroupMembersResp{Total: total, Members: members}, nil
+}
+
+// CreateGroupAnnouncement 发布公告并以站内通知告知其他正式成员
+func (s *GroupService) CreateGroupAnnouncement(ctx context.Context, req *core_api.CreateGroupAnnouncementReq) (*core_api.GroupAnnouncement, error) {
+	v, g, _, err := s.requireGroupAdmin(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	a := &group_announcement.Announcement{
+		GroupId:  req.Id,
+		AuthorId: v.userId,
+		Title:    strings.TrimSpace(req.Title),
+		Content:  strings.TrimSpace(req.Content),
+	}
+	if a.Title == "" || a.Content == "" {
+		return nil, consts.ErrGroupInvalid
+	}
+	if err = s.GroupAnnouncementMapper.Insert(ctx, a); err != nil {
+		return nil, consts.ErrCreate
+	}
+
+	filter := bson.M{consts.GroupId: req.Id, consts.Status: group_member.StatusActive, consts.UserID: bson.M{"$ne": v.userId}}
+	for skip := int64(0); ; skip += announcementBatchSize {
+		members, _, err := s.GroupMemberMapper.FindMany(ctx, filter, skip, announcementBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]*notification.Notification, 0, len(members))
+		for _, member := range members {
+			items = append(items, &notification.Notification{
+				UserId:  member.UserId,
+				Type:    notification.TypeGroupAnnouncement,
+				Title:   g.Name + "：" + a.Title,
+				Content: a.Content,
+			})
+		}
+		if err = s.NotificationMapper.InsertMany(ctx, items); err != nil {
+			return nil, err
+		}
+		if int64(len(members)) < announcementBatchSize {
+			break
+		}
+	}
+	return mapGroupAnnouncement(v, a, v.self), nil
+}
+
+func (s *GroupService) GetGroupAnnouncements(ctx context.Context, req *core_api.GetGroupAnnouncementsReq) (*core_api.GetGroupAnnouncementsResp, error) {
+	v, _, _, err := s.requireGroupMember(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	page, pageSize := normalizePage(req.Page, req.PageSize)
+	data, total, err := s.GroupAnnouncementMapper.FindByGroup(ctx, req.Id, offset(page, pageSize), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	authorIds := make([]string, 0, len(data))
+	for _, item := range data {
+		authorIds = append(authorIds, item.AuthorId)
+	}
+	authors, err := findUsers(ctx, s.UserMapper, authorIds)
+	if err != nil {
+		return nil, err
+	}
+	announcements := make([]*core_api.GroupAnnouncement, 0, len(data))
+	for _, item := range data {
+		announcements = append(announcements, mapGroupAnnouncement(v, item, authors[item.AuthorId]))
+	}
+	return &core_api.GetGroupAnnouncementsResp{Total: total, Announcements: announcements}, nil
+}
+
+func (s *GroupService) DeleteGroupAnnouncement(ctx context.Context, req *core_api.DeleteGroupAnnouncementReq) (*core_api.Response, error) {
+	a, err := s.GroupAnnouncementMapper.FindByID(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, _, err = s.requireGroupAdmin(ctx, a.GroupId); err != nil {
+		return nil, err
+	}
+	if _, err = s.GroupAnnouncementMapper.Delete(ctx, a.ID); err != nil {
+		return nil, err
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "删除成功",
+	}, nil
+}
+
+func (s *GroupService) ListAdminGroups(ctx context.Context, page, pageSize int64, keyword, category, status string) (*PageResult[AdminGroup], error) {
+	page, pageSize = normalizePage(page, pageSize)
+	filter := bson.M{}
+	if keyword = strings.TrimSpace(keyword); keyword != "" {
+		filter[consts.Name] = bson.M{"$regex": regexp.QuoteMeta(keyword), "$options": "i"}
+	}
+	if category = strings.TrimSpace(category); category != "" {
+		filter[consts.Category] = category
+	}
+	if status == "deleted" {
+		filter[consts.Status] = int64(consts.DeleteStatus)
+	} else {
+		filter[consts.Status] = bson.M{"$ne": int64(consts.DeleteStatus)}
+	}
+	data, total, err := s.GroupMapper.FindMany(ctx, filter, offset(page, pageSize), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	groupIds := make([]string, 0, len(data))
+	creatorIds := make([]string, 0, len(data))
+	for _, item := range data {
+		groupIds = append(groupIds, item.ID.Hex())
+		creatorIds = append(creatorIds, item.CreatorId)
+	}
+	counts, err := s.GroupMemberMapper.CountActive(ctx, groupIds)
+	if err != nil {
+		return nil, err
+	}
+	creators, err := findUsers(ctx, s.UserMapper, creatorIds)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]AdminGroup, 0, len(data))
+	for _, item := range data {
+		result := AdminGroup{
+			ID:          item.ID.Hex(),
+			Name:        item.Name,
+			Category:    item.Category,
+			RefID:       item.RefId,
+			Description: item.Description,
+			Policy:      item.Policy,
+			CreatorID:   item.CreatorId,
+			MemberCount: counts[item.ID.Hex()],
+			Deleted:     item.Status == consts.DeleteStatus,
+			CreateTime:  timeToUnix(item.CreateTime),
+		}
+		if creator, ok := creators[item.CreatorId]; ok {
+			result.CreatorName = creator.Name
+		}
+		items = append(items, result)
+	}
+	return &PageResult[AdminGroup]{Items: items, Total: total, Page: page, PageSize: pageSize}, nil
+}
+
+func (s *GroupService) DeleteGroup(ctx context.Context, id string) error {
+	item, err := s.GroupMapper.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	before := snapshot(item)
+	item.Status = consts.DeleteStatus
+	item.DeleteTime = time.Now()
+	if err = s.GroupMapper.Update(ctx, item); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.AuditMapper, "group.delete", AuditGroup, item.ID.Hex(), before, snapshot(item))
+	return nil
+}
+
+func (s *GroupService) RestoreGroup(ctx context.Context, id string) error {
+	item, err := s.GroupMapper.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	before := snapshot(item)
+	item.Status = consts.EffectStatus
+	item.DeleteTime = time.Time{}
+	if err = s.GroupMapper.Update(ctx, item); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.AuditMapper, "group.restore", AuditGroup, item.ID.Hex(), before, snapshot(item))
+	return nil
+}
+
+// SetActivityGroup 设置活动所属群组，groupID 为空时解除关联
+func (s *GroupService) SetActivityGroup(ctx context.Context, activityID, groupID string) error {
+	act, err := s.ActivityMapper.FindById(ctx, activityID)
+	if err != nil {
+		return err
+	}
+	before := snapshot(act)
+	if groupID = strings.TrimSpace(groupID); groupID != "" {
+		if _, err = s.findGroup(ctx, groupID); err != nil {
+			return ErrAdminBadRequest
+		}
+	}
+	act.GroupId = groupID
+	if err = s.ActivityMapper.Update(ctx, act); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.AuditMapper, "activity.set_group", AuditActivity, act.ID.Hex(), before, snapshot(act))
+	return nil
+}
+
+// findGroup 查询未删除的群组
+func (s *GroupService) findGroup(ctx context.Context, id string) (*group.Group, error) {
+	g, err := s.GroupMapper.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if g.Status == consts.DeleteStatus {
+		return nil, consts.ErrNotFound
+	}
+	return g, nil
+}
+
+// requireGroupMember 当前用户须为群组的正式成员
+func (s *GroupService) requireGroupMember(ctx context.Context, groupId string) (*viewer, *group.Group, *group_member.Member, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	g, err := s.findGroup(ctx, groupId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	member, err := s.GroupMemberMapper.FindOne(ctx, groupId, v.userId)
+	if errors.Is(err, consts.ErrNotFound) || (err == nil && member.Status != group_member.StatusActive) {
+		return nil, nil, nil, consts.ErrGroupNotMember
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return v, g, member, nil
+}
+
+// requireGroupAdmin 当前用户须为群组管理员
+func (s *GroupService) requireGroupAdmin(ctx context.Context, groupId string) (*viewer, *group.Group, *group_member.Member, error) {
+	v, g, member, err := s.requireGroupMember(ctx, groupId)
+	if errors.Is(err, consts.ErrGroupNotMember) || (err == nil && member.Role != group_member.RoleAdmin) {
+		return nil, nil, nil, consts.ErrGroupNotAdmin
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return v, g, member, nil
+}
+
+// checkLastAdmin 群组至少保留一名管理员，移除或降级唯一的管理员时报错
+func (s *GroupService) checkLastAdmin(ctx context.Context, member *group_member.Member) error {
+	if member.Role != group_member.RoleAdmin || member.Status != group_member.StatusActive {
+		return nil
+	}
+	admins, err := s.GroupMemberMapper.CountAdmins(ctx, member.GroupId)
+	if err != nil {
+		return err
+	}
+	if admins <= 1 {
+		return consts.ErrGroupLastAdmin
+	}
+	return nil
+}
+
+func (s *GroupService) transitMember(ctx context.Context, member *group_member.Member, from string, set bson.M) error {
+	ok, err := s.GroupMemberMapper.Transit(ctx, member.ID, from, set)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return consts.ErrGroupMemberStatus
+	}
+	return nil
+}
+
+// checkGroupRef 学校、行业群组可关联同类字典条目，同乡群组可关联行政区划，兴趣群组不关联
+func (s *GroupService) checkGroupRef(ctx context.Context, category, refId string) error {
+	switch category {
+	case group.CategorySchool, group.CategoryIndustry:
+		if refId == "" {
+			return nil
+		}
+		entry, err := s.DictionaryMapper.FindByID(ctx, refId)
+		if err != nil || entry.Kind != category {
+			return consts.ErrGroupInvalid
+		}
+	case group.CategoryHometown:
+		if refId != "" && s.Regions.Get(refId) == nil {
+			return consts.ErrRegionInvalid
+		}
+	case group.CategoryHobby:
+		if refId != "" {
+			return consts.ErrGroupInvalid
+		}
+	default:
+		return consts.ErrGroupInvalid
+	}
+	return nil
+}
+
+// mapGroups 批量查询成员数量以及当前用户的成员状态
+func (s *GroupService) mapGroups(ctx context.Context, userId string, data []*group.Group) ([]*core_api.Group, error) {
+	groupIds := make([]string, 0, len(data))
+	for _, item := range data {
+		groupIds = append(groupIds, item.ID.Hex())
+	}
+	counts, err := s.GroupMemberMapper.CountActive(ctx, groupIds)
+	if err != nil {
+		return nil, err
+	}
+	mine := map[string]*group_member.Member{}
+	if userId != "" && len(groupIds) > 0 {
+		members, _, err := s.GroupMemberMapper.FindMany(ctx, bson.M{
+			consts.UserID:  userId,
+			consts.GroupId: bson.M{"$in": groupIds},
+		}, 0, int64(len(groupIds)))
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			mine[member.GroupId] = member
+		}
+	}
+	groups := make([]*core_api.Group, 0, len(data))
+	for _, item := range data {
+		groups = append(groups, mapGroup(item, mine[item.ID.Hex()], counts[item.ID.Hex()]))
+	}
+	return groups, nil
+}
+
+func mapGroup(g *group.Group, self *group_member.Member, memberCount int64) *core_api.Group {
+	item := &core_api.Group{
+		Id:          g.ID.Hex(),
+		Name:        g.Name,
+		Category:    g.Category,
+		RefId:       g.RefId,
+		Avatar:      g.Avatar,
+		Description: g.Description,
+		Policy:      g.Policy,
+		MemberCount: memberCount,
+		CreateTime:  timeToUnix(g.CreateTime),
+	}
+	if self != nil {
+		item.MyRole, item.MyStatus = self.Role, self.Status
+	}
+	return item
+}
+
+func mapGroupAnnouncement(v *viewer, a *group_announcement.Announcement, author *user.User) *core_api.GroupAnnouncement {
+	item := &core_api.GroupAnnouncement{
+		Id:         a.ID.Hex(),
+		Title:      a.Title,
+		Content:    a.Content,
+		CreateTime: timeToUnix(a.CreateTime),
+	}
+	if author != nil && isLiveUser(author) {
+		item.Author = mapDirectoryUser(v, author)
+	}
+	return item
+}
+
+func validGroupPolicy(policy string) bool {
+	return policy == group.PolicyOpen || policy == group.PolicyApproval || policy == group.PolicyInvite
+}
@@ -0,0 +1,512 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/audit"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/job"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/job_application"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultJobExpire = 30 * 24 * time.Hour
+	maxJobExpire     = 180 * 24 * time.Hour
+)
+
+type IJobService interface {
+	CreateJob(ctx context.Context, req *core_api.CreateJobReq) (*core_api.Job, error)
+	SearchJobs(ctx context.Context, req *core_api.SearchJobsReq) (*core_api.GetJobsResp, error)
+	GetJob(ctx context.Context, req *core_api.GetJobReq) (*core_api.Job, error)
+	GetMyJobs(ctx context.Context, req *core_api.GetMyJobsReq) (*core_api.GetJobsResp, error)
+	CloseJob(ctx context.Context, req *core_api.CloseJobReq) (*core_api.Response, error)
+	ApplyJob(ctx context.Context, req *core_api.ApplyJobReq) (*core_api.Response, error)
+	GetJobApplications(ctx context.Context, req *core_api.GetJobApplicationsReq) (*core_api.GetJobApplicationsResp, error)
+	GetJobVocabulary(ctx context.Context, _ *core_api.GetJobVocabularyReq) (*core_api.JobVocabulary, error)
+	ListAdminJobs(ctx context.Context, page, pageSize int64, status string) (*PageResult[AdminJob], error)
+	ApproveJob(ctx context.Context, id string) error
+	RejectJob(ctx context.Context, id, reason string) error
+}
+
+type JobService struct {
+	UserMapper           *user.MongoMapper
+	JobMapper            *job.MongoMapper
+	JobApplicationMapper *job_application.MongoMapper
+	AuditMapper          *audit.MongoMapper
+}
+
+var JobServiceSet = wire.NewSet(
+	wire.Struct(new(JobService), "*"),
+	wire.Bind(new(IJobService), new(*JobService)),
+)
+
+type AdminJob struct {
+	ID           string `json:"id"`
+	PosterID     string `json:"posterId"`
+	PosterName   string `json:"posterName"`
+	PosterPhone  string `json:"posterPhone"`
+	Organization string `json:"organization"`
+	Position     string `json:"position"`
+	Industry     string `json:"industry"`
+	City         string `json:"city"`
+	SalaryMin    int64  `json:"salaryMin"`
+	SalaryMax    int64  `json:"salaryMax"`
+	Description  string `json:"description"`
+	ExpireTime   int64  `json:"expireTime"`
+	Status       string `json:"status"`
+	Reason       string `json:"reason"`
+	ReviewerID   string `json:"reviewerId"`
+	ReviewTime   *int64 `json:"reviewTime"`
+	CreateTime   int64  `json:"createTime"`
+}
+
+// CreateJob 认证校友发布招聘信息，审核通过后才会出现在检索结果中
+func (s *JobService) CreateJob(ctx context.Context, req *core_api.CreateJobReq) (*core_api.Job, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	if !v.verified {
+		return nil, consts.ErrJobNotVerified
+	}
+	j := &job.Job{
+		PosterId:     v.userId,
+		Organization: strings.TrimSpace(req.Organization),
+		Position:     strings.TrimSpace(req.Position),
+		Industry:     strings.TrimSpace(req.Industry),
+		City:         strings.TrimSpace(req.City),
+		SalaryMin:    req.SalaryMin,
+		SalaryMax:    req.SalaryMax,
+		Description:  strings.TrimSpace(req.Description),
+		Status:       job.StatusPending,
+	}
+	if j.Organization == "" || j.Position == "" || j.Industry == "" || j.City == "" || j.Description == "" {
+		return nil, consts.ErrJobInvalid
+	}
+	if j.SalaryMin < 0 || j.SalaryMax < 0 || (j.SalaryMax > 0 && j.SalaryMin > j.SalaryMax) {
+		return nil, consts.ErrJobInvalid
+	}
+	now := time.Now()
+	j.ExpireTime = now.Add(defaultJobExpire)
+	if req.ExpireTime > 0 {
+		j.ExpireTime = time.Unix(req.ExpireTime, 0)
+	}
+	if !j.ExpireTime.After(now) || j.ExpireTime.After(now.Add(maxJobExpire)) {
+		return nil, consts.ErrJobInvalid
+	}
+	// 单位和行业沿用校友工作经历中已有的写法，便于与通讯录、导师推荐按相同取值匹配
+	if j.Organization, err = s.canonicalTerm(ctx, "organization", j.Organization); err != nil {
+		return nil, err
+	}
+	if j.Industry, err = s.canonicalTerm(ctx, "industry", j.Industry); err != nil {
+		return nil, err
+	}
+
+	if err = s.JobMapper.Insert(ctx, j); err != nil {
+		return nil, consts.ErrCreate
+	}
+	return s.mapJob(v, j, v.self, false, 0), nil
+}
+
+// SearchJobs 检索已通过审核且未过期的职位
+func (s *JobService) SearchJobs(ctx context.Context, req *core_api.SearchJobsReq) (*core_api.GetJobsResp, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	page, pageSize := normalizePage(req.Page, req.PageSize)
+	regex := func(value string) bson.M {
+		return bson.M{"$regex": regexp.QuoteMeta(value), "$options": "i"}
+	}
+	conditions := []bson.M{
+		{consts.Status: job.StatusApproved},
+		{"expire_time": bson.M{"$gt": time.Now()}},
+	}
+	if keyword := strings.TrimSpace(req.Keyword); keyword != "" {
+		conditions = append(conditions, bson.M{"$or": []bson.M{
+			{"position": regex(keyword)},
+			{"organization": regex(keyword)},
+			{"description": regex(keyword)},
+		}})
+	}
+	if organization := strings.TrimSpace(req.Organization); organization != "" {
+		conditions = append(conditions, bson.M{"organization": regex(organization)})
+	}
+	if industry := strings.TrimSpace(req.Industry); industry != "" {
+		conditions = append(conditions, bson.M{"industry": regex(industry)})
+	}
+	if city := strings.TrimSpace(req.City); city != "" {
+		conditions = append(conditions, bson.M{"city": regex(city)})
+	}
+	if req.SalaryMin > 0 {
+		conditions = append(conditions, bson.M{"$or": []bson.M{
+			{"salary_max": bson.M{"$gte": req.SalaryMin}},
+			{"salary_min": int64(0), "salary_max": int64(0)},
+		}})
+	}
+	data, total, err := s.JobMapper.FindMany(ctx, bson.M{"$and": conditions}, offset(page, pageSize), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := s.mapJobs(ctx, v, data)
+	if err != nil {
+		return nil, err
+	}
+	return &core_api.GetJobsResp{Total: total, Jobs: jobs}, nil
+}
+
+// GetJob 未通过审核、已关闭或已过期的职位只有发布人可以查看
+func (s *JobService) GetJob(ctx context.Context, req *core_api.GetJobReq) (*core_api.Job, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	j, err := s.JobMapper.FindByID(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if j.PosterId != v.userId && !jobOpen(j) {
+		return nil, consts.ErrJobUnavailable
+	}
+	jobs, err := s.mapJobs(ctx, v, []*job.Job{j})
+	if err != nil {
+		return nil, err
+	}
+	return jobs[0], nil
+}
+
+func (s *JobService) GetMyJobs(ctx context.Context, req *core_api.GetMyJobsReq) (*core_api.GetJobsResp, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	page, pageSize := normalizePage(req.Page, req.PageSize)
+	filter := bson.M{"poster_id": v.userId}
+	switch req.Status {
+	case job.StatusPending, job.StatusApproved, job.StatusRejected, job.StatusClosed:
+		filter[consts.Status] = req.Status
+	}
+	data, total, err := s.JobMapper.FindMany(ctx, filter, offset(page, pageSize), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := s.mapJobs(ctx, v, data)
+	if err != nil {
+		return nil, err
+	}
+	return &core_api.GetJobsResp{Total: total, Jobs: jobs}, nil
+}
+
+// CloseJob 发布人关闭职位，关闭后不再接受投递，已有投递记录仍可查看
+func (s *JobService) CloseJob(ctx context.Context, req *core_api.CloseJobReq) (*core_api.Response, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	j, err := s.JobMapper.FindByID(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if j.PosterId != v.userId {
+		return nil, consts.ErrForbidden
+	}
+	closed, err := s.JobMapper.Close(ctx, j.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		return nil, consts.ErrJobUnavailable
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "职位已关闭",
+	}, nil
+}
+
+// ApplyJob 投递职位，投递后发布人可以查看申请人的完整资料
+func (s *JobService) ApplyJob(ctx context.Context, req *core_api.ApplyJobReq) (*core_api.Response, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	j, err := s.JobMapper.FindByID(ctx, req.JobId)
+	if err != nil {
+		return nil, err
+	}
+	if !jobOpen(j) {
+		return nil, consts.ErrJobUnavailable
+	}
+	if j.PosterId == v.userId {
+		return nil, consts.ErrForbidden
+	}
+	if _, err = s.JobApplicationMapper.FindOne(ctx, req.JobId, v.userId); err == nil {
+		return nil, consts.ErrJobApplied
+	}
+	if err = s.JobApplicationMapper.Insert(ctx, &job_application.JobApplication{
+		JobId:   req.JobId,
+		UserId:  v.userId,
+		Message: strings.TrimSpace(req.Message),
+	}); err != nil {
+		return nil, consts.ErrCreate
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "投递成功",
+	}, nil
+}
+
+// GetJobApplications 发布人查看投递记录，已停用或注销的申请人不再展示资料
+func (s *JobService) GetJobApplications(ctx context.Context, req *core_api.GetJobApplicationsReq) (*core_api.GetJobApplicationsResp, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	j, err := s.JobMapper.FindByID(ctx, req.JobId)
+	if err != nil {
+		return nil, err
+	}
+	if j.PosterId != v.userId {
+		return nil, consts.ErrForbidden
+	}
+	page, pageSize := normalizePage(req.Page, req.PageSize)
+	data, total, err := s.JobApplicationMapper.FindByJob(ctx, req.JobId, offset(page, pageSize), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	userIds := make([]string, 0, len(data))
+	for _, item := range data {
+		userIds = append(userIds, item.UserId)
+	}
+	users, err := findUsers(ctx, s.UserMapper, userIds)
+	if err != nil {
+		return nil, err
+	}
+
+	applications := make([]*core_api.JobApplication, 0, len(data))
+	for _, item := range data {
+		application := &core_api.JobApplication{
+			Id:         item.ID.Hex(),
+			Message:    item.Message,
+			CreateTime: timeToUnix(item.CreateTime),
+		}
+		if applicant, ok := users[item.UserId]; ok && isLiveUser(applicant) {
+			// 申请人以本人视角展示，不受其隐私设置限制
+			application.Applicant = mapDirectoryUser(&viewer{userId: applicant.ID.Hex()}, applicant)
+		}
+		applications = append(applications, application)
+	}
+	return &core_api.GetJobApplicationsResp{Total: total, Applications: applications}, nil
+}
+
+func (s *JobService) GetJobVocabulary(ctx context.Context, _ *core_api.GetJobVocabularyReq) (*core_api.JobVocabulary, error) {
+	if _, err := currentViewer(ctx, s.UserMapper); err != nil {
+		return nil, err
+	}
+	organizations, err := s.vocabulary(ctx, "organization")
+	if err != nil {
+		return nil, err
+	}
+	industries, err := s.vocabulary(ctx, "industry")
+	if err != nil {
+		return nil, err
+	}
+	return &core_api.JobVocabulary{Organizations: organizations, Industries: industries}, nil
+}
+
+func (s *JobService) ListAdminJobs(ctx context.Context, page, pageSize int64, status string) (*PageResult[AdminJob], error) {
+	page, pageSize = normalizePage(page, pageSize)
+	filter := bson.M{}
+	if status = strings.TrimSpace(status); status != "" {
+		filter[consts.Status] = status
+	}
+	data, total, err := s.JobMapper.FindMany(ctx, filter, offset(page, pageSize), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	posterIds := make([]string, 0, len(data))
+	for _, item := range data {
+		posterIds = append(posterIds, item.PosterId)
+	}
+	posters, err := findUsers(ctx, s.UserMapper, posterIds)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]AdminJob, 0, len(data))
+	for _, item := range data {
+		result := AdminJob{
+			ID:           item.ID.Hex(),
+			PosterID:     item.PosterId,
+			Organization: item.Organization,
+			Position:     item.Position,
+			Industry:     item.Industry,
+			City:         item.City,
+			SalaryMin:    item.SalaryMin,
+			SalaryMax:    item.SalaryMax,
+			Description:  item.Description,
+			ExpireTime:   timeToUnix(item.ExpireTime),
+			Status:       item.Status,
+			Reason:       item.Reason,
+			ReviewerID:   item.ReviewerId,
+			ReviewTime:   nullableTimeToUnix(item.ReviewTime),
+			CreateTime:   timeToUnix(item.CreateTime),
+		}
+		if u, ok := posters[item.PosterId]; ok {
+			result.PosterName = u.Name
+			result.PosterPhone = u.Phone
+		}
+		items = append(items, result)
+	}
+	return &PageResult[AdminJob]{Items: items, Total: total, Page: page, PageSize: pageSize}, nil
+}
+
+func (s *JobService) ApproveJob(ctx context.Context, id string) error {
+	return s.review(ctx, id, job.StatusApproved, "")
+}
+
+func (s *JobService) RejectJob(ctx context.Context, id, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return ErrAdminBadRequest
+	}
+	return s.review(ctx, id, job.StatusRejected, reason)
+}
+
+// review 记录审核结果及审核人，只有待审核的职位可以审核
+func (s *JobService) review(ctx context.Context, id, to, reason string) error {
+	item, err := s.JobMapper.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return reviewRecord(ctx, s.AuditMapper, AuditJob, item.ID.Hex(), to, item, func(reviewerID string) (bool, error) {
+		return s.JobMapper.Review(ctx, item.ID, to, reason, reviewerID)
+	}, func(reviewerID string) {
+		item.Status, item.Reason, item.ReviewerId, item.ReviewTime = to, reason, reviewerID, time.Now()
+	})
+}
+
+// vocabulary 校友工作经历中某个字段的全部取值，按字典序排列
+func (s *JobService) vocabulary(ctx context.Context, field string) ([]string, error) {
+	values, err := s.UserMapper.DistinctEmployments(ctx, field)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || seen[strings.ToLower(value)] {
+			continue
+		}
+		seen[strings.ToLower(value)] = true
+		result = append(result, value)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// canonicalTerm 忽略大小写和首尾空白匹配已有取值，匹配不到时保留原值
+func (s *JobService) canonicalTerm(ctx context.Context, field, value string) (string, error) {
+	values, err := s.vocabulary(ctx, field)
+	if err != nil {
+		return "", err
+	}
+	for _, existing := range values {
+		if strings.EqualFold(existing, value) {
+			return existing, nil
+		}
+	}
+	return value, nil
+}
+
+// findUsers 按 id 批量查询用户，非法 id 和不存在的用户不出现在结果中
+func findUsers(ctx context.Context, userMapper *user.MongoMapper, userIds []string) (map[string]*user.User, error) {
+	ids := make([]primitive.ObjectID, 0, len(userIds))
+	for _, id := range userIds {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			ids = append(ids, oid)
+		}
+	}
+	users := make(map[string]*user.User, len(ids))
+	if len(ids) == 0 {
+		return users, nil
+	}
+	found, _, err := userMapper.FindMany(ctx, bson.M{consts.ID: bson.M{"$in": ids}}, 0, int64(len(ids)))
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range found {
+		users[u.ID.Hex()] = u
+	}
+	return users, nil
+}
+
+// mapJobs 批量查询发布人、投递数量以及当前用户是否已投递
+func (s *JobService) mapJobs(ctx context.Context, v *viewer, data []*job.Job) ([]*core_api.Job, error) {
+	jobIds := make([]string, 0, len(data))
+	posterIds := make([]string, 0, len(data))
+	mine := make([]string, 0, len(data))
+	for _, item := range data {
+		jobIds = append(jobIds, item.ID.Hex())
+		posterIds = append(posterIds, item.PosterId)
+		if item.PosterId == v.userId {
+			mine = append(mine, item.ID.Hex())
+		}
+	}
+	posters, err := findUsers(ctx, s.UserMapper, posterIds)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := s.JobApplicationMapper.FindApplied(ctx, v.userId, jobIds)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.JobApplicationMapper.CountByJobs(ctx, mine)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*core_api.Job, 0, len(data))
+	for _, item := range data {
+		jobs = append(jobs, s.mapJob(v, item, posters[item.PosterId], applied[item.ID.Hex()], counts[item.ID.Hex()]))
+	}
+	return jobs, nil
+}
+
+// mapJob 驳回理由和投递数量只返回给发布人
+func (s *JobService) mapJob(v *viewer, j *job.Job, poster *user.User, applied bool, applications int64) *core_api.Job {
+	item := &core_api.Job{
+		Id:           j.ID.Hex(),
+		Organization: j.Organization,
+		Position:     j.Position,
+		Industry:     j.Industry,
+		City:         j.City,
+		SalaryMin:    j.SalaryMin,
+		SalaryMax:    j.SalaryMax,
+		Description:  j.Description,
+		ExpireTime:   timeToUnix(j.ExpireTime),
+		Status:       j.Status,
+		Applied:      applied,
+		CreateTime:   timeToUnix(j.CreateTime),
+	}
+	if poster != nil && isLiveUser(poster) {
+		item.Poster = mapDirectoryUser(v, poster)
+	}
+	if j.PosterId == v.userId {
+		item.Reason = j.Reason
+		item.Applications = applications
+	}
+	return item
+}
+
+// jobOpen 职位已通过审核且未过期
+func jobOpen(j *job.Job) bool {
+	return j.Status == job.StatusApproved && j.ExpireTime.After(time.Now())
+}
@@ -0,0 +1,432 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/mentorship"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultMentorCapacity = int64(3)
+	maxMentorCapacity     = int64(20)
+	maxMentorTopics       = 10
+	// mentorCandidateLimit 参与推荐排序的导师数量上限，超出部分按注册时间截断，并在结果中标记 Capped
+	mentorCandidateLimit = int64(500)
+)
+
+type IMentorshipService interface {
+	GetMentorSetting(ctx context.Context, _ *core_api.GetMentorSettingReq) (*core_api.MentorSetting, error)
+	UpdateMentorSetting(ctx context.Context, req *core_api.MentorSetting) (*core_api.Response, error)
+	SuggestMentors(ctx context.Context, req *core_api.SuggestMentorsReq) (*core_api.SuggestMentorsResp, error)
+	CreateMentorship(ctx context.Context, req *core_api.CreateMentorshipReq) (*core_api.Mentorship, error)
+	GetMentorships(ctx context.Context, req *core_api.GetMentorshipsReq) (*core_api.GetMentorshipsResp, error)
+	RespondMentorship(ctx context.Context, req *core_api.RespondMentorshipReq) (*core_api.Response, error)
+	CompleteMentorship(ctx context.Context, req *core_api.CompleteMentorshipReq) (*core_api.Response, error)
+}
+
+type MentorshipService struct {
+	UserMapper       *user.MongoMapper
+	MentorshipMapper *mentorship.MongoMapper
+}
+
+var MentorshipServiceSet = wire.NewSet(
+	wire.Struct(new(MentorshipService), "*"),
+	wire.Bind(new(IMentorshipService), new(*MentorshipService)),
+)
+
+type mentorHit struct {
+	user      *user.User
+	available int64
+	score     int64
+	matched   []string
+}
+
+func (s *MentorshipService) GetMentorSetting(ctx context.Context, _ *core_api.GetMentorSettingReq) (*core_api.MentorSetting, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.MentorshipMapper.CountAccepted(ctx, []string{v.userId})
+	if err != nil {
+		return nil, err
+	}
+	mentor := v.self.Mentor
+	topics := mentor.Topics
+	if topics == nil {
+		topics = []string{}
+	}
+	return &core_api.MentorSetting{
+		Enabled:  mentor.Enabled,
+		Topics:   topics,
+		Capacity: mentor.Capacity,
+		Intro:    mentor.Intro,
+		Active:   counts[v.userId],
+	}, nil
+}
+
+// UpdateMentorSetting 认证校友开启或关闭导师身份，关闭后不再出现在推荐中，进行中的指导不受影响
+func (s *MentorshipService) UpdateMentorSetting(ctx context.Context, req *core_api.MentorSetting) (*core_api.Response, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	if req.Enabled && !v.verified {
+		return nil, consts.ErrMentorNotVerified
+	}
+	capacity := req.Capacity
+	if capacity <= 0 {
+		capacity = defaultMentorCapacity
+	}
+	if capacity > maxMentorCapacity {
+		capacity = maxMentorCapacity
+	}
+	mentor := user.Mentor{
+		Enabled:  req.Enabled,
+		Topics:   normalizeTopics(req.Topics),
+		Capacity: capacity,
+		Intro:    strings.TrimSpace(req.Intro),
+	}
+	if err = s.UserMapper.UpdateMentor(ctx, v.self.ID, mentor); err != nil {
+		return nil, consts.ErrUpdate
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "更新成功",
+	}, nil
+}
+
+// SuggestMentors 按与当前用户的共同学校、家乡、行业以及指导方向推荐仍有名额的导师
+func (s *MentorshipService) SuggestMentors(ctx context.Context, req *core_api.SuggestMentorsReq) (*core_api.SuggestMentorsResp, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	page, pageSize := normalizePage(req.Page, req.PageSize)
+	candidates, matched, err := s.UserMapper.FindMany(ctx, bson.M{
+		"mentor.enabled": true,
+		consts.ID:        bson.M{"$ne": v.self.ID},
+		"$and": []bson.M{
+			{"$or": []bson.M{{consts.Status: int64(0)}, {consts.Status: bson.M{"$exists": false}}}},
+			{"$or": []bson.M{{consts.DeleteTime: bson.M{"$exists": false}}, {consts.DeleteTime: time.Time{}}}},
+		},
+	}, 0, mentorCandidateLimit)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.ID.Hex())
+	}
+	counts, err := s.MentorshipMapper.CountAccepted(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]mentorHit, 0, len(candidates))
+	for _, candidate := range candidates {
+		available := candidate.Mentor.Capacity - counts[candidate.ID.Hex()]
+		if available <= 0 {
+			continue
+		}
+		hit := scoreMentor(v, candidate, req.Topic)
+		hit.available = available
+		hits = append(hits, hit)
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].score > hits[j].score
+	})
+
+	total := int64(len(hits))
+	start, end := offset(page, pageSize), offset(page, pageSize)+pageSize
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+	mentors := make([]*core_api.Mentor, 0, end-start)
+	for _, hit := range hits[start:end] {
+		card := mapDirectoryUser(v, hit.user)
+		card.Matched = hit.matched
+		mentors = append(mentors, &core_api.Mentor{
+			DirectoryUser: card,
+			Topics:        hit.user.Mentor.Topics,
+			Intro:         hit.user.Mentor.Intro,
+			Available:     hit.available,
+			Score:         hit.score,
+		})
+	}
+	return &core_api.SuggestMentorsResp{Total: total, Capped: matched > int64(len(candidates)), Mentors: mentors}, nil
+}
+
+func (s *MentorshipService) CreateMentorship(ctx context.Context, req *core_api.CreateMentorshipReq) (*core_api.Mentorship, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	if req.MentorId == v.userId {
+		return nil, consts.ErrMentorUnavailable
+	}
+	mentor, err := s.UserMapper.FindOne(ctx, req.MentorId)
+	if err != nil {
+		return nil, err
+	}
+	if !mentor.Mentor.Enabled || !isLiveUser(mentor) {
+		return nil, consts.ErrMentorUnavailable
+	}
+	if err = s.checkCapacity(ctx, mentor); err != nil {
+		return nil, err
+	}
+	if _, err = s.MentorshipMapper.FindOpen(ctx, req.MentorId, v.userId); err == nil {
+		return nil, consts.ErrMentorshipExists
+	}
+
+	ms := &mentorship.Mentorship{
+		MentorId: req.MentorId,
+		MenteeId: v.userId,
+		Topic:    strings.TrimSpace(req.Topic),
+		Message:  strings.TrimSpace(req.Message),
+		Status:   mentorship.StatusPending,
+	}
+	if err = s.MentorshipMapper.Insert(ctx, ms); err != nil {
+		return nil, consts.ErrCreate
+	}
+	return mapMentorship(v, ms, map[string]*user.User{mentor.ID.Hex(): mentor, v.userId: v.self}), nil
+}
+
+func (s *MentorshipService) GetMentorships(ctx context.Context, req *core_api.GetMentorshipsReq) (*core_api.GetMentorshipsResp, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	page, pageSize := normalizePage(req.Page, req.PageSize)
+	filter := bson.M{"mentee_id": v.userId}
+	if req.Role == "mentor" {
+		filter = bson.M{"mentor_id": v.userId}
+	}
+	switch req.Status {
+	case mentorship.StatusPending, mentorship.StatusAccepted, mentorship.StatusDeclined, mentorship.StatusCompleted:
+		filter[consts.Status] = req.Status
+	}
+	data, total, err := s.MentorshipMapper.FindMany(ctx, filter, offset(page, pageSize), pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(data))
+	for _, item := range data {
+		for _, id := range []string{item.MentorId, item.MenteeId} {
+			if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+				ids = append(ids, oid)
+			}
+		}
+	}
+	users := make(map[string]*user.User, len(ids))
+	if len(ids) > 0 {
+		found, _, err := s.UserMapper.FindMany(ctx, bson.M{consts.ID: bson.M{"$in": ids}}, 0, int64(len(ids)))
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range found {
+			users[u.ID.Hex()] = u
+		}
+	}
+
+	mentorships := make([]*core_api.Mentorship, 0, len(data))
+	for _, item := range data {
+		mentorships = append(mentorships, mapMentorship(v, item, users))
+	}
+	return &core_api.GetMentorshipsResp{Total: total, Mentorships: mentorships}, nil
+}
+
+// RespondMentorship 导师处理待处理的申请，接受时校验剩余名额
+func (s *MentorshipService) RespondMentorship(ctx context.Context, req *core_api.RespondMentorshipReq) (*core_api.Response, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	ms, err := s.MentorshipMapper.FindByID(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if ms.MentorId != v.userId {
+		return nil, consts.ErrForbidden
+	}
+	to := mentorship.StatusDeclined
+	if req.Accept {
+		if err = s.checkCapacity(ctx, v.self); err != nil {
+			return nil, err
+		}
+		to = mentorship.StatusAccepted
+	}
+	ok, err := s.MentorshipMapper.Transit(ctx, ms.ID, mentorship.StatusPending, to, strings.TrimSpace(req.Reply))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, consts.ErrMentorshipStatus
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "处理成功",
+	}, nil
+}
+
+func (s *MentorshipService) CompleteMentorship(ctx context.Context, req *core_api.CompleteMentorshipReq) (*core_api.Response, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	ms, err := s.MentorshipMapper.FindByID(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if ms.MentorId != v.userId && ms.MenteeId != v.userId {
+		return nil, consts.ErrForbidden
+	}
+	ok, err := s.MentorshipMapper.Transit(ctx, ms.ID, mentorship.StatusAccepted, mentorship.StatusCompleted, "")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, consts.ErrMentorshipStatus
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "指导已完成",
+	}, nil
+}
+
+// checkCapacity 导师进行中的指导数量达到上限时返回 ErrMentorFull
+func (s *MentorshipService) checkCapacity(ctx context.Context, mentor *user.User) error {
+	counts, err := s.MentorshipMapper.CountAccepted(ctx, []string{mentor.ID.Hex()})
+	if err != nil {
+		return err
+	}
+	if counts[mentor.ID.Hex()] >= mentor.Mentor.Capacity {
+		return consts.ErrMentorFull
+	}
+	return nil
+}
+
+// scoreMentor 只比较导师对查看者可见的字段：指导方向、学校、家乡、行业，以及毕业年份早于查看者
+func scoreMentor(v *viewer, mentor *user.User, topic string) mentorHit {
+	hit := mentorHit{user: mentor, matched: []string{}}
+	add := func(score int64, matched string) {
+		hit.score += score
+		hit.matched = append(hit.matched, matched)
+	}
+	self := v.self
+
+	if topic = strings.TrimSpace(topic); topic != "" {
+		for _, t := range mentor.Mentor.Topics {
+			if containsFold(t, topic) || containsFold(topic, t) {
+				add(3, "topic")
+				break
+			}
+		}
+	}
+	if v.canSee(mentor, user.FieldEducations) {
+		if sharedSchool(educationsOf(self), educationsOf(mentor)) {
+			add(3, "school")
+		}
+		if mentorYear, selfYear := earliestYear(mentor), earliestYear(self); mentorYear > 0 && selfYear > 0 && mentorYear < selfYear {
+			add(1, "senior")
+		}
+	}
+	if v.canSee(mentor, user.FieldHometown) && sameHometown(self, mentor) {
+		add(2, user.FieldHometown)
+	}
+	if v.canSee(mentor, user.FieldEmployments) && sharedIndustry(self.Employments, mentor.Employments) {
+		add(2, "industry")
+	}
+	return hit
+}
+
+func educationsOf(u *user.User) []user.Education {
+	return append(append([]user.Education{}, u.HomeEducations...), u.ShanghaiEducations...)
+}
+
+func sharedSchool(a, b []user.Education) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if sameSchool(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sharedIndustry(a, b []user.Employment) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if sameIndustry(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// earliestYear 最早的毕业年份，未填写时返回 0
+func earliestYear(u *user.User) int64 {
+	var year int64
+	for _, edu := range educationsOf(u) {
+		if edu.Year > 0 && (year == 0 || edu.Year < year) {
+			year = edu.Year
+		}
+	}
+	return year
+}
+
+// normalizeTopics 去掉空白与重复的指导方向，最多保留 maxMentorTopics 个
+func normalizeTopics(topics []string) []string {
+	result := make([]string, 0, len(topics))
+	seen := map[string]bool{}
+	for _, topic := range topics {
+		topic = strings.TrimSpace(topic)
+		if topic == "" || seen[strings.ToLower(topic)] {
+			continue
+		}
+		seen[strings.ToLower(topic)] = true
+		result = append(result, topic)
+		if len(result) == maxMentorTopics {
+			break
+		}
+	}
+	return result
+}
+
+// mapMentorship 双方资料按隐私设置脱敏，账号已不存在的一方为空
+func mapMentorship(v *viewer, ms *mentorship.Mentorship, users map[string]*user.User) *core_api.Mentorship {
+	party := func(id string) *core_api.DirectoryUser {
+		u, ok := users[id]
+		if !ok {
+			return nil
+		}
+		card := mapDirectoryUser(v, u)
+		card.Matched = []string{}
+		return card
+	}
+	return &core_api.Mentorship{
+		Id:         ms.ID.Hex(),
+		Mentor:     party(ms.MentorId),
+		Mentee:     party(ms.MenteeId),
+		Topic:      ms.Topic,
+		Message:    ms.Message,
+		Reply:      ms.Reply,
+		Status:     ms.Status,
+		CreateTime: timeToUnix(ms.CreateTime),
+		UpdateTime: timeToUnix(ms.UpdateTime),
+	}
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/audit"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/group_announcement"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/group_member"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/job"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/job_application"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/mentorship"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/order"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/organization"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/register"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/verification"
+)
+
+// 合并冲突字段的取值来源
+const (
+	MergeFromSource = "source"
+	MergeFromTarget = "target"
+)
+
+type IMergeService interface {
+	PreviewMerge(ctx context.Context, sourceID, targetID string) (*AdminMergePreview, error)
+	MergeUsers(ctx context.Context, input AdminMergeInput) (*AdminMergeResult, error)
+}
+
+type MergeService struct {
+	UserMapper  *user.MongoMapper
+	AuditMapper *audit.MongoMapper
+	Records     *UserRecords
+}
+
+var MergeServiceSet = wire.NewSet(
+	wire.Struct(new(MergeService), "*"),
+	wire.Bind(new(IMergeService), new(*MergeService)),
+)
+
+// UserRecords 用户名下分散在其他集合中的记录，账号合并时整体转移给保留的账号
+type UserRecords struct {
+	RegisterMapper     *register.MongoMapper
+	OrderMapper        *order.MongoMapper
+	VerificationMapper *verification.MongoMapper
+	OrganizationMapper *organization.MongoMapper
+	MentorshipMapper   *mentorship.MongoMapper
+	JobMapper          *job.MongoMapper
+	ApplicationMapper  *job_application.MongoMapper
+	GroupMemberMapper  *group_member.MongoMapper
+	AnnouncementMapper *group_announcement.MongoMapper
+}
+
+var UserRecordsSet = wire.NewSet(
+	wire.Struct(new(UserRecords), "*"),
+)
+
+type AdminMergePreview struct {
+	Source    AdminUser `json:"source"`
+	Target    AdminUser `json:"target"`
+	Conflicts []string  `json:"conflicts"` // 双方均有值且不一致、需要明确选择来源的字段
+}
+
+// AdminMergeInput 将 SourceID 合并进 TargetID，Choices 为每个冲突字段指定取值来源 source/target
+type AdminMergeInput struct {
+	SourceID string            `json:"sourceId"`
+	TargetID string            `json:"targetId"`
+	Choices  map[string]string `json:"choices"`
+}
+
+type AdminMergeResult struct {
+	User  AdminUser        `json:"user"`
+	Moved map[string]int64 `json:"moved"` // 各类记录转移的数量
+}
+
+// mergeField 可合并的资料字段，一方为空时直接取另一方的值
+type mergeField struct {
+	key   string
+	empty func(u *user.User) bool
+	equal func(a, b *user.User) bool
+	take  func(dst, src *user.User)
+}
+
+func fieldOf[T any](key string, get func(u *user.User) *T) mergeField {
+	return mergeField{
+		key:   key,
+		empty: func(u *user.User) bool { return isEmptyValue(reflect.ValueOf(get(u)).Elem()) },
+		equal: func(a, b *user.User) bool { return reflect.DeepEqual(*get(a), *get(b)) },
+		take:  func(dst, src *user.User) { *get(dst) = *get(src) },
+	}
+}
+
+var mergeFields = []mergeField{
+	fieldOf("avatar", func(u *user.User) *string { return &u.Avatar }),
+	fieldOf("name", func(u *user.User) *string { return &u.Name }),
+	fieldOf("gender", func(u *user.User) *int64 { return &u.Gender }),
+	fieldOf("birthday", func(u *user.User) *time.Time { return &u.Birthday }),
+	fieldOf("phone", func(u *user.User) *string { return &u.Phone }),
+	fieldOf("wxId", func(u *user.User) *string { return &u.WxId }),
+	// 家乡文字与行政区划代码须一起合并，避免文字与代码不对应
+	{
+		key:   "hometown",
+		empty: func(u *user.User) bool { return u.Hometown == "" && u.HometownCode == "" },
+		equal: func(a, b *user.User) bool { return a.Hometown == b.Hometown && a.HometownCode == b.HometownCode },
+		take:  func(dst, src *user.User) { dst.Hometown, dst.HometownCode = src.Hometown, src.HometownCode },
+	},
+	fieldOf("homeEducations", func(u *user.User) *[]user.Education { return &u.HomeEducations }),
+	fieldOf("shanghaiEducations", func(u *user.User) *[]user.Education { return &u.ShanghaiEducations }),
+	fieldOf("employments", func(u *user.User) *[]user.Employment { return &u.Employments }),
+	fieldOf("role", func(u *user.User) *string { return &u.Role }),
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.String:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+func (s *MergeService) PreviewMerge(ctx context.Context, sourceID, targetID string) (*AdminMergePreview, error) {
+	source, target, err := s.findPair(ctx, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return &AdminMergePreview{
+		Source:    mapAdminUser(source),
+		Target:    mapAdminUser(target),
+		Conflicts: mergeConflicts(source, target),
+	}, nil
+}
+
+// MergeUsers 合并两个账号：按选择合并资料，转移源账号名下的记录，源账号软删除并指向保留账号
+func (s *MergeService) MergeUsers(ctx context.Context, input AdminMergeInput) (*AdminMergeResult, error) {
+	source, target, err := s.findPair(ctx, input.SourceID, input.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range mergeConflicts(source, target) {
+		if choice := input.Choices[key]; choice != MergeFromSource && choice != MergeFromTarget {
+			return nil, ErrAdminBadRequest
+		}
+	}
+
+	before := snapshot(target)
+	for _, field := range mergeFields {
+		switch {
+		case field.empty(source):
+		case field.empty(target):
+			field.take(target, source)
+		case !field.equal(source, target) && input.Choices[field.key] == MergeFromSource:
+			field.take(target, source)
+		}
+	}
+	target.AdminRoles = uniqueStrings(append(append([]string{}, target.AdminRoles...), source.AdminRoles...))
+
+	moved, err := s.Records.Move(ctx, source.ID.Hex(), target.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if err = s.UserMapper.UpdateMergedProfile(ctx, target); err != nil {
+		return nil, err
+	}
+	// 源账号修改手机号时记入的中台账号随之转移，以新手机号登录时进入保留的账号
+	if err = s.UserMapper.MovePlatformIds(ctx, source.ID, target.ID, source.PlatformIds); err != nil {
+		return nil, err
+	}
+	target.PlatformIds = uniqueStrings(append(append([]string{}, target.PlatformIds...), source.PlatformIds...))
+	recordAudit(ctx, s.AuditMapper, "user.merge", AuditUser, target.ID.Hex(), before, snapshot(target))
+
+	sourceBefore := snapshot(source)
+	source.Status = 1
+	source.DeleteTime = time.Now()
+	source.MergedInto = target.ID.Hex()
+	if err = s.UserMapper.MarkMerged(ctx, source.ID, source.MergedInto); err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, s.AuditMapper, "user.merged_into", AuditUser, source.ID.Hex(), sourceBefore, snapshot(source))
+
+	return &AdminMergeResult{User: mapAdminUser(target), Moved: moved}, nil
+}
+
+func (s *MergeService) findPair(ctx context.Context, sourceID, targetID string) (*user.User, *user.User, error) {
+	if sourceID == "" || sourceID == targetID {
+		return nil, nil, ErrAdminBadRequest
+	}
+	source, err := s.UserMapper.FindOne(ctx, sourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err := s.UserMapper.FindOne(ctx, targetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if source.MergedInto != "" || !target.DeleteTime.IsZero() {
+		return nil, nil, ErrAdminBadRequest
+	}
+	return source, target, nil
+}
+
+func mergeConflicts(source, target *user.User) []string {
+	conflicts := make([]string, 0)
+	for _, field := range mergeFields {
+		if !field.empty(source) && !field.empty(target) && !field.equal(source, target) {
+			conflicts = append(conflicts, field.key)
+		}
+	}
+	return conflicts
+}
+
+// Move 将 from 用户名下的报名、订单、认证申请、组织成员身份、指导申请、招聘信息、投递记录、群组成员身份和群组公告转移给 to 用户
+func (r *UserRecords) Move(ctx context.Context, from, to string) (map[string]int64, error) {
+	moved := map[string]int64{}
+	var err error
+	if moved[AuditRegistration], err = r.RegisterMapper.ReassignUser(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if moved[AuditOrder], err = r.OrderMapper.ReassignUser(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if moved[AuditVerification], err = r.VerificationMapper.ReassignUser(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if moved[AuditMentorship], err = r.MentorshipMapper.ReassignUser(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if moved[AuditJob], err = r.JobMapper.ReassignPoster(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if moved[AuditApplication], err = r.ApplicationMapper.ReassignUser(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if moved[AuditGroup], err = r.GroupMemberMapper.ReassignUser(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if moved[AuditAnnouncement], err = r.AnnouncementMapper.ReassignAuthor(ctx, from, to); err != nil {
+		return nil, err
+	}
+
+	orgs, err := r.OrganizationMapper.FindByMember(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	for _, org := range orgs {
+		org.Members = mergeMembers(org.Members, from, to)
+		if err = r.OrganizationMapper.Update(ctx, org); err != nil {
+			return nil, err
+		}
+	}
+	moved[AuditOrganization] = int64(len(orgs))
+	return moved, nil
+}
+
+// mergeMembers 双方都是成员时只保留一条，保留较早的加入时间和较高的角色
+func mergeMembers(members []organization.Member, from, to string) []organization.Member {
+	var source *organization.Member
+	result := make([]organization.Member, 0, len(members))
+	for i := range members {
+		if members[i].UserId == from {
+			source = &members[i]
+			continue
+		}
+		result = append(result, members[i])
+	}
+	if source == nil {
+		return result
+	}
+	for i := range result {
+		if result[i].UserId != to {
+			continue
+		}
+		if source.Role == organization.RoleManager {
+			result[i].Role = organization.RoleManager
+		}
+		if source.JoinTime.Before(result[i].JoinTime) {
+			result[i].JoinTime = source.JoinTime
+		}
+		return result
+	}
+	source.UserId = to
+	return append(result, *source)
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/audit"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/notification"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// profileNoticeInterval 同一用户两次资料完善提醒的最短间隔
+	profileNoticeInterval     = 7 * 24 * time.Hour
+	defaultProfileNoticeTitle = "完善资料，让校友找到你"
+)
+
+type INotificationService interface {
+	GetNotifications(ctx context.Context, req *core_api.GetNotificationsReq) (*core_api.GetNotificationsResp, error)
+	ReadNotifications(ctx context.Context, req *core_api.ReadNotificationsReq) (*core_api.Response, error)
+	NotifyIncompleteProfiles(ctx context.Context, input AdminProfileNotice) (*AdminProfileNoticeResult, error)
+}
+
+type NotificationService struct {
+	UserMapper         *user.MongoMapper
+	NotificationMapper *notification.MongoMapper
+	AuditMapper        *audit.MongoMapper
+}
+
+var NotificationServiceSet = wire.NewSet(
+	wire.Struct(new(NotificationService), "*"),
+	wire.Bind(new(INotificationService), new(*NotificationService)),
+)
+
+// AdminProfileNotice 提醒资料不完整的用户，Content 为空时按各用户缺少的部分生成
+type AdminProfileNotice struct {
+	CompletenessFilter
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// AdminProfileNoticeResult Skipped 为间隔期内已提醒过而跳过的人数
+type AdminProfileNoticeResult struct {
+	Matched  int64 `json:"matched"`
+	Notified int64 `json:"notified"`
+	Skipped  int64 `json:"skipped"`
+}
+
+func (s *NotificationService) GetNotifications(ctx context.Context, req *core_api.GetNotificationsReq) (*core_api.GetNotificationsResp, error) {
+	userId := adaptor.ExtractUserMeta(ctx).GetUserId()
+	if userId == "" {
+		return nil, consts.ErrNotAuthentication
+	}
+	page, pageSize := normalizePage(req.Page, req.PageSize)
+	data, total, err := s.NotificationMapper.FindByUser(ctx, userId, offset(page, pageSize), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.NotificationMapper.CountUnread(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*core_api.Notification, 0, len(data))
+	for _, item := range data {
+		items = append(items, &core_api.Notification{
+			Id:         item.ID.Hex(),
+			Type:       item.Type,
+			Title:      item.Title,
+			Content:    item.Content,
+			Read:       !item.ReadTime.IsZero(),
+			CreateTime: timeToUnix(item.CreateTime),
+		})
+	}
+	return &core_api.GetNotificationsResp{Notifications: items, Total: total, Unread: unread}, nil
+}
+
+func (s *NotificationService) ReadNotifications(ctx context.Context, req *core_api.ReadNotificationsReq) (*core_api.Response, error) {
+	userId := adaptor.ExtractUserMeta(ctx).GetUserId()
+	if userId == "" {
+		return nil, consts.ErrNotAuthentication
+	}
+	ids := make([]primitive.ObjectID, 0, len(req.Ids))
+	for _, id := range req.Ids {
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, consts.ErrInvalidObjectId
+		}
+		ids = append(ids, oid)
+	}
+	if _, err := s.NotificationMapper.MarkRead(ctx, userId, ids); err != nil {
+		return nil, consts.ErrUpdate
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "已读",
+	}, nil
+}
+
+// NotifyIncompleteProfiles 向符合筛选条件且资料不完整的正常用户发送站内提醒，间隔期内已提醒过的用户跳过
+func (s *NotificationService) NotifyIncompleteProfiles(ctx context.Context, input AdminProfileNotice) (*AdminProfileNoticeResult, error) {
+	if input.Max <= 0 || input.Max >= 100 {
+		input.Max = 99
+	}
+	cond, err := input.condition()
+	if err != nil {
+		return nil, err
+	}
+	filter := bson.M{"$and": []bson.M{
+		cond,
+		{"$or": []bson.M{{consts.Status: int64(0)}, {consts.Status: bson.M{"$exists": false}}}},
+		{"$or": []bson.M{{consts.DeleteTime: bson.M{"$exists": false}}, {consts.DeleteTime: time.Time{}}}},
+	}}
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		title = defaultProfileNoticeTitle
+	}
+	content := strings.TrimSpace(input.Content)
+
+	result := &AdminProfileNoticeResult{}
+	since := time.Now().Add(-profileNoticeInterval)
+	var after primitive.ObjectID
+	for {
+		users, err := s.UserMapper.FindAfter(ctx, filter, after, migrationBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		userIds := make([]string, 0, len(users))
+		for _, u := range users {
+			userIds = append(userIds, u.ID.Hex())
+		}
+		notified, err := s.NotificationMapper.FindNotified(ctx, notification.TypeProfileIncomplete, userIds, since)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]*notification.Notification, 0, len(users))
+		for _, u := range users {
+			result.Matched++
+			if notified[u.ID.Hex()] {
+				result.Skipped++
+				continue
+			}
+			item := &notification.Notification{
+				UserId:  u.ID.Hex(),
+				Type:    notification.TypeProfileIncomplete,
+				Title:   title,
+				Content: content,
+			}
+			if item.Content == "" {
+				item.Content = profileNoticeContent(profileCompleteness(u))
+			}
+			items = append(items, item)
+		}
+		if err = s.NotificationMapper.InsertMany(ctx, items); err != nil {
+			return nil, err
+		}
+		result.Notified += int64(len(items))
+		if int64(len(users)) < migrationBatchSize {
+			break
+		}
+		after = users[len(users)-1].ID
+	}
+	recordAudit(ctx, s.AuditMapper, "user.notify_incomplete", AuditUser, "", nil, snapshot(map[string]any{
+		"filter": input.CompletenessFilter,
+		"result": result,
+	}))
+	return result, nil
+}
+
+func profileNoticeContent(completeness *core_api.ProfileCompleteness) string {
+	labels := make([]string, 0, len(completeness.Missing))
+	for _, key := range completeness.Missing {
+		labels = append(labels, findProfileSection(key).label)
+	}
+	return "你的资料还缺少" + strings.Join(labels, "、") + "，完善后校友们就能在通讯录中找到你。"
+}
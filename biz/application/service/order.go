@@ -0,0 +1,542 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/activity"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/audit"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/order"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/register"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/payment"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const defaultOrderExpireMinutes = 15
+
+type IOrderService interface {
+	ListTickets(ctx context.Context, req *core_api.ListTicketsReq) (*core_api.ListTicketsResp, error)
+	CreateOrder(ctx context.Context, req *core_api.CreateOrderReq) (*core_api.Order, error)
+	GetOrder(ctx context.Context, req *core_api.GetOrderReq) (*core_api.Order, error)
+	GetOrders(ctx context.Context, req *core_api.GetOrdersReq) (*core_api.GetOrdersResp, error)
+	HandlePayNotify(ctx context.Context, header http.Header, body []byte) error
+}
+
+type OrderService struct {
+	Config         *config.Config
+	ActivityMapper *activity.MongoMapper
+	RegisterMapper *register.MongoMapper
+	OrderMapper    *order.MongoMapper
+	Payment        payment.IPaymentProvider
+	AuditMapper    *audit.MongoMapper
+}
+
+var OrderServiceSet = wire.NewSet(
+	wire.Struct(new(OrderService), "*"),
+	wire.Bind(new(IOrderService), new(*OrderService)),
+)
+
+type AdminOrder struct {
+	ID            string   `json:"id"`
+	ActivityID    string   `json:"activityId"`
+	UserID        string   `json:"userId"`
+	TicketID      string   `json:"ticketId"`
+	TicketName    string   `json:"ticketName"`
+	UnitPrice     int64    `json:"unitPrice"`
+	Quantity      int64    `json:"quantity"`
+	Amount        int64    `json:"amount"`
+	Status        string   `json:"status"`
+	RegisterIDs   []string `json:"registerIds"`
+	TransactionID string   `json:"transactionId"`
+	RefundReason  string   `json:"refundReason"`
+	ExpireTime    int64    `json:"expireTime"`
+	PaidTime      *int64   `json:"paidTime"`
+	RefundTime    *int64   `json:"refundTime"`
+	CreateTime    int64    `json:"createTime"`
+}
+
+func (s *OrderService) ListTickets(ctx context.Context, req *core_api.ListTicketsReq) (*core_api.ListTicketsResp, error) {
+	act, err := s.ActivityMapper.FindById(ctx, req.ActivityId)
+	if err != nil {
+		return nil, err
+	}
+	tickets := make([]*core_api.Ticket, 0, len(act.Tickets))
+	for _, t := range act.Tickets {
+		tickets = append(tickets, &core_api.Ticket{
+			Id:          t.ID,
+			Name:        t.Name,
+			Price:       t.Price,
+			Description: t.Description,
+		})
+	}
+	return &core_api.ListTicketsResp{ActivityId: act.ID.Hex(), Tickets: tickets}, nil
+}
+
+func (s *OrderService) CreateOrder(ctx context.Context, req *core_api.CreateOrderReq) (*core_api.Order, error) {
+	userMeta := adaptor.ExtractUserMeta(ctx)
+	if userMeta.GetUserId() == "" {
+		return nil, consts.ErrNotAuthentication
+	}
+	if len(req.Items) == 0 {
+		return nil, consts.ErrEmptyRegister
+	}
+	act, err := s.ActivityMapper.FindById(ctx, req.ActivityId)
+	if err != nil {
+		return nil, err
+	}
+	ticket := findTicket(act, req.TicketId)
+	if ticket == nil {
+		return nil, consts.ErrTicketNotFound
+	}
+	quantity := int64(len(req.Items))
+	if err = reserveSeats(ctx, s.ActivityMapper, s.OrderMapper, s.RegisterMapper, act, quantity); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	o := &order.Order{
+		ActivityId: act.ID.Hex(),
+		UserId:     userMeta.GetUserId(),
+		TicketId:   ticket.ID,
+		TicketName: ticket.Name,
+		UnitPrice:  ticket.Price,
+		Quantity:   quantity,
+		Amount:     ticket.Price * quantity,
+		Status:     order.StatusPending,
+		ExpireTime: now.Add(s.expireDuration()),
+	}
+	if o.Amount == 0 {
+		o.Status = order.StatusPaid
+		o.PaidTime = now
+	}
+
+	o.ID = primitive.NewObjectID()
+	o.RegisterIds = make([]string, 0, quantity)
+	for _, item := range req.Items {
+		r := &register.Register{
+			ActivityId: o.ActivityId,
+			UserId:     o.UserId,
+			Name:       item.Name,
+			Phone:      normalizePhone(item.Phone),
+			CheckIn:    false,
+			OrderId:    o.ID.Hex(),
+			PayStatus:  o.Status,
+		}
+		if err = s.RegisterMapper.Insert(ctx, r); err != nil {
+			s.abandonOrder(ctx, o)
+			return nil, consts.ErrCreate
+		}
+		o.RegisterIds = append(o.RegisterIds, r.Id.Hex())
+	}
+	if err = s.OrderMapper.Insert(ctx, o); err != nil {
+		s.abandonOrder(ctx, o)
+		return nil, consts.ErrCreate
+	}
+
+	result := mapOrder(o)
+	if o.Status == order.StatusPaid {
+		return result, nil
+	}
+	prepay, err := s.Payment.Prepay(ctx, &payment.PrepayReq{
+		OrderNo:     o.ID.Hex(),
+		Description: truncateRunes(act.Name+"-"+ticket.Name, 40),
+		Amount:      o.Amount,
+		OpenId:      strings.TrimSpace(req.OpenId),
+		ExpireTime:  o.ExpireTime,
+	})
+	if err != nil {
+		log.CtxError(ctx, "prepay fail, orderId=%s, err=%v", o.ID.Hex(), err)
+		_, _ = s.transit(ctx, o, order.StatusPending, order.StatusExpired, nil)
+		return nil, consts.ErrPay
+	}
+	result.PayParams = prepay.Params
+	return result, nil
+}
+
+func (s *OrderService) GetOrder(ctx context.Context, req *core_api.GetOrderReq) (*core_api.Order, error) {
+	userMeta := adaptor.ExtractUserMeta(ctx)
+	if userMeta.GetUserId() == "" {
+		return nil, consts.ErrNotAuthentication
+	}
+	o, err := s.OrderMapper.FindByID(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if o.UserId != userMeta.GetUserId() {
+		return nil, consts.ErrNotFound
+	}
+	return mapOrder(o), nil
+}
+
+func (s *OrderService) GetOrders(ctx context.Context, req *core_api.GetOrdersReq) (*core_api.GetOrdersResp, error) {
+	userMeta := adaptor.ExtractUserMeta(ctx)
+	if userMeta.GetUserId() == "" {
+		return nil, consts.ErrNotAuthentication
+	}
+	page, pageSize := normalizePage(req.Page, req.PageSize)
+	filter := bson.M{consts.UserID: userMeta.GetUserId()}
+	if req.ActivityId != "" {
+		filter[consts.ActivityId] = req.ActivityId
+	}
+	data, total, err := s.OrderMapper.FindManyByFilter(ctx, filter, offset(page, pageSize), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	orders := make([]*core_api.Order, 0, len(data))
+	for _, o := range data {
+		orders = append(orders, mapOrder(o))
+	}
+	return &core_api.GetOrdersResp{Total: total, Orders: orders}, nil
+}
+
+// HandlePayNotify 处理支付渠道回调。已超时关闭的订单若收到支付成功通知同样置为已支付并重新占用名额，
+// 款项已实际到账，此时不检查名额上限，由运营线下处理超额名额
+func (s *OrderService) HandlePayNotify(ctx context.Context, header http.Header, body []byte) error {
+	n, err := s.Payment.ParseNotify(ctx, header, body)
+	if err != nil {
+		return err
+	}
+	if !n.Paid {
+		return nil
+	}
+	o, err := s.OrderMapper.FindByID(ctx, n.OrderNo)
+	if err != nil {
+		return err
+	}
+	if o.Amount != n.Amount {
+		log.CtxError(ctx, "pay notify amount mismatch, orderId=%s, expect=%d, actual=%d", o.ID.Hex(), o.Amount, n.Amount)
+		return consts.ErrOrderStatus
+	}
+	if o.Status == order.StatusPaid {
+		return nil
+	}
+	if o.Status == order.StatusExpired {
+		log.CtxInfo(ctx, "expired order paid, orderId=%s", o.ID.Hex())
+	}
+	fields := bson.M{
+		"transaction_id": n.TransactionId,
+		"paid_time":      time.Now(),
+	}
+	ok, err := s.transit(ctx, o, order.StatusPending, order.StatusPaid, fields)
+	if err == nil && !ok {
+		ok, err = s.transit(ctx, o, order.StatusExpired, order.StatusPaid, fields)
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return consts.ErrOrderStatus
+	}
+	return nil
+}
+
+func (s *OrderService) ListAdminOrders(ctx context.Context, page, pageSize int64, activityID, status string) (*PageResult[AdminOrder], error) {
+	page, pageSize = normalizePage(page, pageSize)
+	filter := bson.M{}
+	if activityID != "" {
+		filter[consts.ActivityId] = activityID
+	}
+	// 超过支付期限但尚未被定时任务关闭的待支付订单按已超时处理
+	now := time.Now()
+	switch status {
+	case "":
+	case order.StatusPending:
+		filter[consts.Status] = status
+		filter[consts.ExpireTime] = bson.M{"$gt": now}
+	case order.StatusExpired:
+		filter["$or"] = []bson.M{
+			{consts.Status: status},
+			{consts.Status: order.StatusPending, consts.ExpireTime: bson.M{"$lte": now}},
+		}
+	default:
+		filter[consts.Status] = status
+	}
+	data, total, err := s.OrderMapper.FindManyByFilter(ctx, filter, offset(page, pageSize), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]AdminOrder, 0, len(data))
+	for _, item := range data {
+		items = append(items, mapAdminOrder(item))
+	}
+	return &PageResult[AdminOrder]{Items: items, Total: total, Page: page, PageSize: pageSize}, nil
+}
+
+func (s *OrderService) RefundOrder(ctx context.Context, id, reason string) error {
+	o, err := s.OrderMapper.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if o.Status != order.StatusPaid {
+		return ErrAdminBadRequest
+	}
+	if o.Amount > 0 {
+		if err = s.Payment.Refund(ctx, &payment.RefundReq{
+			OrderNo:  o.ID.Hex(),
+			RefundNo: "R" + o.ID.Hex(),
+			Amount:   o.Amount,
+			Total:    o.Amount,
+			Reason:   strings.TrimSpace(reason),
+		}); err != nil {
+			return err
+		}
+	}
+	ok, err := s.transit(ctx, o, order.StatusPaid, order.StatusRefunded, bson.M{
+		"refund_reason": strings.TrimSpace(reason),
+		"refund_time":   time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAdminBadRequest
+	}
+	var after map[string]any
+	if refunded, err := s.OrderMapper.FindByID(ctx, id); err == nil {
+		after = snapshot(refunded)
+	}
+	recordAudit(ctx, s.AuditMapper, "order.refund", AuditOrder, o.ID.Hex(), snapshot(o), after)
+	return nil
+}
+
+// RunExpirySweeper 按固定间隔关闭超过支付期限的待支付订单，直到 ctx 结束
+func (s *OrderService) RunExpirySweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := expireOverdueOrders(ctx, s.ActivityMapper, s.OrderMapper, s.RegisterMapper, ""); err != nil {
+			log.CtxError(ctx, "[RunExpirySweeper] expire failed, err=%v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *OrderService) transit(ctx context.Context, o *order.Order, from, to string, fields bson.M) (bool, error) {
+	return transitOrder(ctx, s.ActivityMapper, s.OrderMapper, s.RegisterMapper, o, from, to, fields)
+}
+
+// abandonOrder 订单未能创建时关闭已写入的报名并释放预占的名额
+func (s *OrderService) abandonOrder(ctx context.Context, o *order.Order) {
+	if err := s.RegisterMapper.UpdatePayStatusByOrder(ctx, o.ID.Hex(), order.StatusExpired); err != nil {
+		log.CtxError(ctx, "abandon order fail, orderId=%s, err=%v", o.ID.Hex(), err)
+	}
+	releaseSeats(ctx, s.ActivityMapper, o.ActivityId, o.Quantity)
+}
+
+func (s *OrderService) expireDuration() time.Duration {
+	minutes := s.Config.Pay.ExpireMinutes
+	if minutes <= 0 {
+		minutes = defaultOrderExpireMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// transitOrder 切换订单状态并同步关联报名的支付状态，报名是否占用名额由支付状态决定，名额计数随之增减
+func transitOrder(ctx context.Context, activityMapper *activity.MongoMapper, orderMapper *order.MongoMapper, registerMapper *register.MongoMapper, o *order.Order, from, to string, fields bson.M) (bool, error) {
+	ok, err := orderMapper.Transit(ctx, o.ID, []string{from}, to, fields)
+	if err != nil || !ok {
+		return ok, err
+	}
+	o.Status = to
+	if err = registerMapper.UpdatePayStatusByOrder(ctx, o.ID.Hex(), to); err != nil {
+		return true, err
+	}
+	if occupiesSeat(from) == occupiesSeat(to) {
+		return true, nil
+	}
+	// 管理员删除的报名已单独释放名额，这里只计未删除的报名
+	n, err := registerMapper.CountByFilter(ctx, bson.M{
+		consts.OrderId: o.ID.Hex(),
+		consts.Status:  bson.M{"$ne": int64(consts.DeleteStatus)},
+	})
+	if err != nil {
+		log.CtxError(ctx, "count order registers fail, orderId=%s, err=%v", o.ID.Hex(), err)
+		return true, nil
+	}
+	if occupiesSeat(to) {
+		occupySeats(ctx, activityMapper, o.ActivityId, n)
+	} else {
+		releaseSeats(ctx, activityMapper, o.ActivityId, n)
+	}
+	return true, nil
+}
+
+// expireOverdueOrders 关闭超过支付期限的待支付订单，释放其占用的名额
+func expireOverdueOrders(ctx context.Context, activityMapper *activity.MongoMapper, orderMapper *order.MongoMapper, registerMapper *register.MongoMapper, activityId string) error {
+	overdue, err := orderMapper.FindOverdue(ctx, activityId, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, o := range overdue {
+		if _, err = transitOrder(ctx, activityMapper, orderMapper, registerMapper, o, order.StatusPending, order.StatusExpired, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// occupiedFilter 占用名额的报名：未删除，且未关联订单或订单为待支付、已支付
+func occupiedFilter(activityId string) bson.M {
+	return bson.M{
+		consts.ActivityId: activityId,
+		consts.Status:     bson.M{"$ne": int64(consts.DeleteStatus)},
+		consts.PayStatus:  bson.M{"$nin": []string{order.StatusExpired, order.StatusRefunded}},
+	}
+}
+
+// occupiesSeat 未关联订单的报名 payStatus 为空，同样占用名额
+func occupiesSeat(payStatus string) bool {
+	return payStatus != order.StatusExpired && payStatus != order.StatusRefunded
+}
+
+// countOccupied 只读统计，超过支付期限的待支付订单由 RunExpirySweeper 或下一次报名关闭后才释放名额
+func countOccupied(ctx context.Context, registerMapper *register.MongoMapper, activityId string) (int64, error) {
+	return registerMapper.CountByFilter(ctx, occupiedFilter(activityId))
+}
+
+// reserveSeats 在活动的名额计数上原子地占用 n 个名额，Limit 大于 0 时限制名额，否则不限。
+// 计数在活动首次报名时按已占用的报名数初始化，之后只随报名与订单状态增减
+func reserveSeats(ctx context.Context, activityMapper *activity.MongoMapper, orderMapper *order.MongoMapper, registerMapper *register.MongoMapper, act *activity.Activity, n int64) error {
+	id := act.ID.Hex()
+	if err := expireOverdueOrders(ctx, activityMapper, orderMapper, registerMapper, id); err != nil {
+		return consts.ErrCount
+	}
+	return reserveCountedSeats(ctx, activityMapper, id, n, func() (int64, error) {
+		return registerMapper.CountByFilter(ctx, occupiedFilter(id))
+	})
+}
+
+// seatCounter 活动的名额计数
+type seatCounter interface {
+	ReserveSeats(ctx context.Context, id string, n int64) (bool, error)
+	InitSeats(ctx context.Context, id string, occupied int64) (bool, error)
+}
+
+// reserveCountedSeats 占用名额，计数尚未初始化时按 count 统计的已占用数初始化后重试；
+// 并发的首次报名中只有一个能完成初始化，其余请求同样重试，不视为名额已满
+func reserveCountedSeats(ctx context.Context, seats seatCounter, id string, n int64, count func() (int64, error)) error {
+	ok, err := seats.ReserveSeats(ctx, id, n)
+	if err != nil {
+		return consts.ErrCount
+	}
+	if ok {
+		return nil
+	}
+	occupied, err := count()
+	if err != nil {
+		return consts.ErrCount
+	}
+	if _, err = seats.InitSeats(ctx, id, occupied); err != nil {
+		return consts.ErrCount
+	}
+	if ok, err = seats.ReserveSeats(ctx, id, n); err != nil {
+		return consts.ErrCount
+	}
+	if !ok {
+		return consts.ErrActivityFull
+	}
+	return nil
+}
+
+// occupySeats 不检查上限地计入名额，用于款项已到账或管理员代报名等无法拒绝的情况
+func occupySeats(ctx context.Context, activityMapper *activity.MongoMapper, activityId string, n int64) {
+	if err := activityMapper.AddSeats(ctx, activityId, n); err != nil {
+		log.CtxError(ctx, "occupy seats fail, activityId=%s, n=%d, err=%v", activityId, n, err)
+	}
+}
+
+// releaseSeats 释放名额失败只记录日志，不影响已完成的状态切换
+func releaseSeats(ctx context.Context, activityMapper *activity.MongoMapper, activityId string, n int64) {
+	if n <= 0 {
+		return
+	}
+	if err := activityMapper.AddSeats(ctx, activityId, -n); err != nil {
+		log.CtxError(ctx, "release seats fail, activityId=%s, n=%d, err=%v", activityId, n, err)
+	}
+}
+
+func findTicket(act *activity.Activity, ticketId string) *activity.Ticket {
+	for i := range act.Tickets {
+		if act.Tickets[i].ID == ticketId {
+			return &act.Tickets[i]
+		}
+	}
+	return nil
+}
+
+func isPaidActivity(act *activity.Activity) bool {
+	for _, t := range act.Tickets {
+		if t.Price > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+// displayStatus 超过支付期限但尚未关闭的待支付订单展示为已超时，读取时不修改订单
+func displayStatus(o *order.Order) string {
+	if o.Status == order.StatusPending && !o.ExpireTime.After(time.Now()) {
+		return order.StatusExpired
+	}
+	return o.Status
+}
+
+func mapOrder(o *order.Order) *core_api.Order {
+	return &core_api.Order{
+		Id:          o.ID.Hex(),
+		ActivityId:  o.ActivityId,
+		TicketId:    o.TicketId,
+		TicketName:  o.TicketName,
+		UnitPrice:   o.UnitPrice,
+		Quantity:    o.Quantity,
+		Amount:      o.Amount,
+		Status:      displayStatus(o),
+		RegisterIds: o.RegisterIds,
+		ExpireTime:  timeToUnix(o.ExpireTime),
+		PaidTime:    timeToUnix(o.PaidTime),
+		RefundTime:  timeToUnix(o.RefundTime),
+		CreateTime:  timeToUnix(o.CreateTime),
+	}
+}
+
+func mapAdminOrder(o *order.Order) AdminOrder {
+	return AdminOrder{
+		ID:            o.ID.Hex(),
+		ActivityID:    o.ActivityId,
+		UserID:        o.UserId,
+		TicketID:      o.TicketId,
+		TicketName:    o.TicketName,
+		UnitPrice:     o.UnitPrice,
+		Quantity:      o.Quantity,
+		Amount:        o.Amount,
+		Status:        displayStatus(o),
+		RegisterIDs:   o.RegisterIds,
+		TransactionID: o.TransactionId,
+		RefundReason:  o.RefundReason,
+		ExpireTime:    timeToUnix(o.ExpireTime),
+		PaidTime:      nullableTimeToUnix(o.PaidTime),
+		RefundTime:    nullableTimeToUnix(o.RefundTime),
+		CreateTime:    timeToUnix(o.CreateTime),
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+)
+
+// memSeats 内存中的名额计数，语义与活动名额的条件更新一致
+type memSeats struct {
+	mu          sync.Mutex
+	limit       int64
+	occupied    int64
+	initialized bool
+}
+
+func (s *memSeats) ReserveSeats(_ context.Context, _ string, n int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.initialized || s.occupied+n > s.limit {
+		return false, nil
+	}
+	s.occupied += n
+	return true, nil
+}
+
+func (s *memSeats) InitSeats(_ context.Context, _ string, occupied int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.initialized {
+		return false, nil
+	}
+	s.occupied, s.initialized = occupied, true
+	return true, nil
+}
+
+func TestReserveCountedSeatsConcurrentFirstReservations(t *testing.T) {
+	seats := &memSeats{limit: 2}
+	// 两个请求都在计数初始化前统计完已占用数，随后竞争初始化
+	var counted sync.WaitGroup
+	counted.Add(2)
+	count := func() (int64, error) {
+		counted.Done()
+		counted.Wait()
+		return 0, nil
+	}
+
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = reserveCountedSeats(context.Background(), seats, "activity", 1, count)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("reservation %d: %v", i, err)
+		}
+	}
+	if seats.occupied != 2 {
+		t.Fatalf("occupied = %d, want 2", seats.occupied)
+	}
+}
+
+func TestReserveCountedSeatsFull(t *testing.T) {
+	seats := &memSeats{limit: 1}
+	count := func() (int64, error) { return 1, nil }
+	if err := reserveCountedSeats(context.Background(), seats, "activity", 1, count); err != consts.ErrActivityFull {
+		t.Fatalf("err = %v, want ErrActivityFull", err)
+	}
+}
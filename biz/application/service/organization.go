@@ -0,0 +1,429 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/activity"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/audit"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/organization"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/role"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type IOrganizationService interface {
+	GetOrganizations(ctx context.Context, req *core_api.GetOrganizationsReq) (*core_api.GetOrganizationsResp, error)
+	GetOrganization(ctx context.Context, req *core_api.GetOrganizationReq) (*core_api.Organization, error)
+	GetOrganizationActivities(ctx context.Context, req *core_api.GetOrganizationActivitiesReq) (*core_api.GetActivitiesResp, error)
+	GetActivityOrganizations(ctx context.Context, req *core_api.GetActivityOrganizationsReq) (*core_api.GetActivityOrganizationsResp, error)
+}
+
+type OrganizationService struct {
+	OrganizationMapper *organization.MongoMapper
+	ActivityMapper     *activity.MongoMapper
+	UserMapper         *user.MongoMapper
+	AuditMapper        *audit.MongoMapper
+}
+
+var OrganizationServiceSet = wire.NewSet(
+	wire.Struct(new(OrganizationService), "*"),
+	wire.Bind(new(IOrganizationService), new(*OrganizationService)),
+)
+
+type AdminOrganization struct {
+	ID          string                    `json:"id"`
+	Name        string                    `json:"name"`
+	Logo        string                    `json:"logo"`
+	Description string                    `json:"description"`
+	Contact     string                    `json:"contact"`
+	Members     []AdminOrganizationMember `json:"members"`
+	Deleted     bool                      `json:"deleted"`
+	CreateTime  int64                     `json:"createTime"`
+}
+
+type AdminOrganizationMember struct {
+	UserID   string `json:"userId"`
+	Name     string `json:"name"`
+	Phone    string `json:"phone"`
+	Role     string `json:"role"`
+	JoinTime int64  `json:"joinTime"`
+}
+
+type AdminOrganizationInput struct {
+	Name        string `json:"name"`
+	Logo        string `json:"logo"`
+	Description string `json:"description"`
+	Contact     string `json:"contact"`
+}
+
+type AdminOrganizationMemberInput struct {
+	UserID string `json:"userId"`
+	Role   string `json:"role"`
+}
+
+func (s *OrganizationService) GetOrganizations(ctx context.Context, req *core_api.GetOrganizationsReq) (*core_api.GetOrganizationsResp, error) {
+	userId := adaptor.ExtractUserMeta(ctx).GetUserId()
+	page, pageSize := normalizePage(req.Page, req.PageSize)
+	filter := bson.M{consts.Status: bson.M{"$ne": int64(consts.DeleteStatus)}}
+	if keyword := strings.TrimSpace(req.Keyword); keyword != "" {
+		filter[consts.Name] = bson.M{"$regex": regexp.QuoteMeta(keyword), "$options": "i"}
+	}
+	if req.Mine {
+		if userId == "" {
+			return nil, consts.ErrNotAuthentication
+		}
+		filter["members.user_id"] = userId
+	}
+	data, total, err := s.OrganizationMapper.FindMany(ctx, filter, offset(page, pageSize), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	orgs := make([]*core_api.Organization, 0, len(data))
+	for _, item := range data {
+		orgs = append(orgs, mapOrganization(item, userId))
+	}
+	return &core_api.GetOrganizationsResp{Total: total, Organizations: orgs}, nil
+}
+
+func (s *OrganizationService) GetOrganization(ctx context.Context, req *core_api.GetOrganizationReq) (*core_api.Organization, error) {
+	item, err := s.OrganizationMapper.FindByID(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if item.Status == consts.DeleteStatus {
+		return nil, consts.ErrNotFound
+	}
+	return mapOrganization(item, adaptor.ExtractUserMeta(ctx).GetUserId()), nil
+}
+
+func (s *OrganizationService) GetOrganizationActivities(ctx context.Context, req *core_api.GetOrganizationActivitiesReq) (*core_api.GetActivitiesResp, error) {
+	page, pageSize := normalizePage(req.Page, req.PageSize)
+	data, total, err := s.ActivityMapper.FindManyByFilter(ctx, bson.M{
+		consts.Status:          consts.EffectStatus,
+		consts.OrganizationIds: req.Id,
+	}, offset(page, pageSize), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	activities := make([]*core_api.Activity, 0, len(data))
+	for _, act := range data {
+		activities = append(activities, mapActivity(act))
+	}
+	return &core_api.GetActivitiesResp{Total: total, Activities: activities}, nil
+}
+
+func (s *OrganizationService) GetActivityOrganizations(ctx context.Context, req *core_api.GetActivityOrganizationsReq) (*core_api.GetActivityOrganizationsResp, error) {
+	act, err := s.ActivityMapper.FindById(ctx, req.ActivityId)
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.OrganizationMapper.FindByIDs(ctx, act.OrganizationIds)
+	if err != nil {
+		return nil, err
+	}
+	userId := adaptor.ExtractUserMeta(ctx).GetUserId()
+	orgs := make([]*core_api.Organization, 0, len(data))
+	for _, item := range data {
+		orgs = append(orgs, mapOrganization(item, userId))
+	}
+	return &core_api.GetActivityOrganizationsResp{Organizations: orgs}, nil
+}
+
+func (s *OrganizationService) ListAdminOrganizations(ctx context.Context, page, pageSize int64, keyword, status string) (*PageResult[AdminOrganization], error) {
+	page, pageSize = normalizePage(page, pageSize)
+	filter := bson.M{}
+	if keyword = strings.TrimSpace(keyword); keyword != "" {
+		filter[consts.Name] = bson.M{"$regex": regexp.QuoteMeta(keyword), "$options": "i"}
+	}
+	if status == "deleted" {
+		filter[consts.Status] = int64(consts.DeleteStatus)
+	} else {
+		filter[consts.Status] = bson.M{"$ne": int64(consts.DeleteStatus)}
+	}
+	data, total, err := s.OrganizationMapper.FindMany(ctx, filter, offset(page, pageSize), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]AdminOrganization, 0, len(data))
+	for _, item := range data {
+		items = append(items, s.mapAdminOrganization(ctx, item))
+	}
+	return &PageResult[AdminOrganization]{Items: items, Total: total, Page: page, PageSize: pageSize}, nil
+}
+
+func (s *OrganizationService) GetAdminOrganization(ctx context.Context, id string) (*AdminOrganization, error) {
+	item, err := s.OrganizationMapper.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result := s.mapAdminOrganization(ctx, item)
+	return &result, nil
+}
+
+func (s *OrganizationService) CreateOrganization(ctx context.Context, input AdminOrganizationInput) (*AdminOrganization, error) {
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, ErrAdminBadRequest
+	}
+	item := &organization.Organization{
+		Name:        strings.TrimSpace(input.Name),
+		Logo:        strings.TrimSpace(input.Logo),
+		Description: strings.TrimSpace(input.Description),
+		Contact:     strings.TrimSpace(input.Contact),
+		Members:     []organization.Member{},
+		Status:      consts.EffectStatus,
+	}
+	if err := s.OrganizationMapper.Insert(ctx, item); err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, s.AuditMapper, "organization.create", AuditOrganization, item.ID.Hex(), nil, snapshot(item))
+	result := s.mapAdminOrganization(ctx, item)
+	return &result, nil
+}
+
+func (s *OrganizationService) UpdateOrganization(ctx context.Context, id string, input AdminOrganizationInput) (*AdminOrganization, error) {
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, ErrAdminBadRequest
+	}
+	item, err := s.OrganizationMapper.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := snapshot(item)
+	item.Name = strings.TrimSpace(input.Name)
+	item.Logo = strings.TrimSpace(input.Logo)
+	item.Description = strings.TrimSpace(input.Description)
+	item.Contact = strings.TrimSpace(input.Contact)
+	if err = s.OrganizationMapper.Update(ctx, item); err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, s.AuditMapper, "organization.update", AuditOrganization, item.ID.Hex(), before, snapshot(item))
+	result := s.mapAdminOrganization(ctx, item)
+	return &result, nil
+}
+
+func (s *OrganizationService) DeleteOrganization(ctx context.Context, id string) error {
+	item, err := s.OrganizationMapper.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	before := snapshot(item)
+	item.Status = consts.DeleteStatus
+	item.DeleteTime = time.Now()
+	if err = s.OrganizationMapper.Update(ctx, item); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.AuditMapper, "organization.delete", AuditOrganization, item.ID.Hex(), before, snapshot(item))
+	return nil
+}
+
+func (s *OrganizationService) RestoreOrganization(ctx context.Context, id string) error {
+	item, err := s.OrganizationMapper.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	before := snapshot(item)
+	item.Status = consts.EffectStatus
+	item.DeleteTime = time.Time{}
+	if err = s.OrganizationMapper.Update(ctx, item); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.AuditMapper, "organization.restore", AuditOrganization, item.ID.Hex(), before, snapshot(item))
+	return nil
+}
+
+// SetOrganizationMembers 整体替换组织成员，保留已有成员的加入时间
+func (s *OrganizationService) SetOrganizationMembers(ctx context.Context, id string, inputs []AdminOrganizationMemberInput) (*AdminOrganization, error) {
+	item, err := s.OrganizationMapper.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := snapshot(item)
+	joined := make(map[string]time.Time, len(item.Members))
+	for _, m := range item.Members {
+		joined[m.UserId] = m.JoinTime
+	}
+	now := time.Now()
+	members := make([]organization.Member, 0, len(inputs))
+	seen := make(map[string]bool, len(inputs))
+	for _, input := range inputs {
+		userId := strings.TrimSpace(input.UserID)
+		if userId == "" || seen[userId] || !validOrganizationRole(input.Role) {
+			return nil, ErrAdminBadRequest
+		}
+		if _, err = s.UserMapper.FindOne(ctx, userId); err != nil {
+			return nil, ErrAdminBadRequest
+		}
+		seen[userId] = true
+		joinTime, ok := joined[userId]
+		if !ok {
+			joinTime = now
+		}
+		members = append(members, organization.Member{UserId: userId, Role: input.Role, JoinTime: joinTime})
+	}
+	item.Members = members
+	if err = s.OrganizationMapper.Update(ctx, item); err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, s.AuditMapper, "organization.set_members", AuditOrganization, item.ID.Hex(), before, snapshot(item))
+	result := s.mapAdminOrganization(ctx, item)
+	return &result, nil
+}
+
+func (s *OrganizationService) SetActivityOrganizations(ctx context.Context, activityID string, organizationIDs []string) error {
+	act, err := s.ActivityMapper.FindById(ctx, activityID)
+	if err != nil {
+		return err
+	}
+	before := snapshot(act)
+	orgs, err := s.OrganizationMapper.FindByIDs(ctx, organizationIDs)
+	if err != nil {
+		return err
+	}
+	if len(orgs) != len(uniqueStrings(organizationIDs)) {
+		return ErrAdminBadRequest
+	}
+	act.OrganizationIds = organizationIDsOf(orgs)
+	if err = s.ActivityMapper.Update(ctx, act); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.AuditMapper, "activity.set_organizations", AuditActivity, act.ID.Hex(), before, snapshot(act))
+	return nil
+}
+
+func (s *OrganizationService) mapAdminOrganization(ctx context.Context, item *organization.Organization) AdminOrganization {
+	members := make([]AdminOrganizationMember, 0, len(item.Members))
+	for _, m := range item.Members {
+		member := AdminOrganizationMember{UserID: m.UserId, Role: m.Role, JoinTime: timeToUnix(m.JoinTime)}
+		if u, err := s.UserMapper.FindOne(ctx, m.UserId); err == nil {
+			member.Name = u.Name
+			member.Phone = u.Phone
+		}
+		members = append(members, member)
+	}
+	return AdminOrganization{
+		ID:          item.ID.Hex(),
+		Name:        item.Name,
+		Logo:        item.Logo,
+		Description: item.Description,
+		Contact:     item.Contact,
+		Members:     members,
+		Deleted:     item.Status == consts.DeleteStatus,
+		CreateTime:  timeToUnix(item.CreateTime),
+	}
+}
+
+// activityAuthority 当前用户的活动管理权限：管理员可管理全部活动，组织管理者仅可管理关联了其所管理组织的活动
+type activityAuthority struct {
+	userId  string
+	admin   bool // 拥有后台活动管理权限
+	managed map[string]bool
+}
+
+func loadActivityAuthority(ctx context.Context, userMapper *user.MongoMapper, organizationMapper *organization.MongoMapper, roleMapper *role.MongoMapper) (*activityAuthority, error) {
+	userMeta := adaptor.ExtractUserMeta(ctx)
+	if userMeta.GetUserId() == "" {
+		return nil, consts.ErrNotAuthentication
+	}
+	authority := &activityAuthority{userId: userMeta.GetUserId(), managed: map[string]bool{}}
+	if adaptor.IsDevModeRequest(ctx) && authority.userId == consts.DevMockUserID {
+		authority.admin = true
+		return authority, nil
+	}
+	aUser, err := userMapper.FindOne(ctx, authority.userId)
+	if err != nil {
+		return nil, consts.ErrNotAuthentication
+	}
+	if aUser.Status != 0 || !aUser.DeleteTime.IsZero() {
+		return nil, consts.ErrForbidden
+	}
+	_, perms, err := resolvePermissions(ctx, roleMapper, aUser)
+	if err != nil {
+		return nil, err
+	}
+	authority.admin = hasPermission(perms, PermActivityWrite)
+	orgs, err := organizationMapper.FindManagedBy(ctx, authority.userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, org := range orgs {
+		authority.managed[org.ID.Hex()] = true
+	}
+	return authority, nil
+}
+
+// canManage 活动关联的组织中至少有一个由当前用户管理
+func (a *activityAuthority) canManage(organizationIds []string) bool {
+	if a.admin {
+		return true
+	}
+	for _, id := range organizationIds {
+		if a.managed[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// canCreateFor 以组织名义创建活动时，所列组织必须全部由当前用户管理
+func (a *activityAuthority) canCreateFor(organizationIds []string) bool {
+	if a.admin {
+		return true
+	}
+	if len(organizationIds) == 0 {
+		return false
+	}
+	for _, id := range organizationIds {
+		if !a.managed[id] {
+			return false
+		}
+	}
+	return true
+}
+
+func validOrganizationRole(role string) bool {
+	return role == organization.RoleManager || role == organization.RoleMember
+}
+
+func organizationIDsOf(orgs []*organization.Organization) []string {
+	ids := make([]string, 0, len(orgs))
+	for _, org := range orgs {
+		ids = append(ids, org.ID.Hex())
+	}
+	return ids
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" && !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func mapOrganization(item *organization.Organization, viewerId string) *core_api.Organization {
+	org := &core_api.Organization{
+		Id:          item.ID.Hex(),
+		Name:        item.Name,
+		Logo:        item.Logo,
+		Description: item.Description,
+		Contact:     item.Contact,
+		MemberCount: int64(len(item.Members)),
+	}
+	for _, m := range item.Members {
+		if viewerId != "" && m.UserId == viewerId {
+			org.MyRole = m.Role
+		}
+	}
+	return org
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/rpc/platform_auth"
+)
+
+var phonePattern = regexp.MustCompile(`^1\d{10}$`)
+
+// SendPhoneChangeCode 向新手机号发送验证码，号码已被其他账号绑定时不发送
+func (u *UserService) SendPhoneChangeCode(ctx context.Context, req *core_api.SendPhoneChangeCodeReq) (*core_api.Response, error) {
+	aUser, err := u.findAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	phone, err := u.checkNewPhone(ctx, aUser, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkVerifyCodeQuota(ctx, u.Limiter, u.Captcha, phone); err != nil {
+		return nil, err
+	}
+	if err = u.PlatformAuth.SendVerifyCode(ctx, "phone", phone); err != nil {
+		return nil, err
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "发送验证码成功，请注意查收",
+	}, nil
+}
+
+// ChangePhone 校验新手机号的验证码后更新绑定的手机号
+func (u *UserService) ChangePhone(ctx context.Context, req *core_api.ChangePhoneReq) (*core_api.Response, error) {
+	aUser, err := u.findAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	phone, err := u.checkNewPhone(ctx, aUser, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+	code := strings.TrimSpace(req.VerifyCode)
+	if code == "" {
+		return nil, consts.ErrVerifyCode
+	}
+
+	// 中台未提供单独的验证码校验接口，也不支持修改账号的手机号，以验证码登录新手机号的方式校验；
+	// 新手机号在中台对应的账号（可能因此新建）记入 PlatformIds，之后以新手机号登录仍指向本账号
+	signInResp, err := u.PlatformAuth.SignIn(ctx, &platform_auth.SignInReq{AuthType: "phone", AuthId: phone, VerifyCode: &code})
+	if err == consts.ErrSignIn {
+		return nil, consts.ErrVerifyCode
+	} else if err != nil {
+		return nil, err
+	}
+	platformUserID := signInResp.UserId
+	if platformUserID == aUser.ID.Hex() {
+		platformUserID = ""
+	} else if owner, err := u.findPlatformUser(ctx, platformUserID); err == nil && owner.ID != aUser.ID && isLiveUser(owner) {
+		// 新手机号在中台对应的账号已是本平台的其他用户时，不允许绑定
+		return nil, consts.ErrPhoneTaken
+	}
+
+	if err = u.UserMapper.UpdatePhone(ctx, aUser.ID, phone, platformUserID); err != nil {
+		return nil, consts.ErrUpdate
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "手机号修改成功",
+	}, nil
+}
+
+// checkNewPhone 校验新手机号格式，并确认未被其他正常状态的账号绑定
+func (u *UserService) checkNewPhone(ctx context.Context, aUser *user.User, phone string) (string, error) {
+	phone = strings.TrimSpace(phone)
+	if !phonePattern.MatchString(phone) || phone == aUser.Phone {
+		return "", consts.ErrPhoneInvalid
+	}
+	switch _, err := u.UserMapper.FindLiveByPhone(ctx, phone, aUser.ID); {
+	case err == nil:
+		return "", consts.ErrPhoneTaken
+	case !errors.Is(err, consts.ErrNotFound):
+		return "", err
+	}
+	return phone, nil
+}
+
+func isLiveUser(u *user.User) bool {
+	return u.Status == 0 && u.DeleteTime.IsZero()
+}
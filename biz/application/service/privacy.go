@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type IPrivacyService interface {
+	GetPrivacySetting(ctx context.Context, req *core_api.GetPrivacySettingReq) (*core_api.PrivacySetting, error)
+	UpdatePrivacySetting(ctx context.Context, req *core_api.PrivacySetting) (*core_api.Response, error)
+}
+
+type PrivacyService struct {
+	UserMapper *user.MongoMapper
+}
+
+var PrivacyServiceSet = wire.NewSet(
+	wire.Struct(new(PrivacyService), "*"),
+	wire.Bind(new(IPrivacyService), new(*PrivacyService)),
+)
+
+func (s *PrivacyService) GetPrivacySetting(ctx context.Context, _ *core_api.GetPrivacySettingReq) (*core_api.PrivacySetting, error) {
+	userMeta := adaptor.ExtractUserMeta(ctx)
+	if userMeta.GetUserId() == "" {
+		return nil, consts.ErrNotAuthentication
+	}
+	aUser, err := s.UserMapper.FindOne(ctx, userMeta.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	privacy := make(map[string]string, len(user.PrivacyFields))
+	for _, field := range user.PrivacyFields {
+		privacy[field] = aUser.PrivacyLevel(field)
+	}
+	return &core_api.PrivacySetting{Privacy: privacy}, nil
+}
+
+// UpdatePrivacySetting 仅更新请求中出现的字段分组
+func (s *PrivacyService) UpdatePrivacySetting(ctx context.Context, req *core_api.PrivacySetting) (*core_api.Response, error) {
+	userMeta := adaptor.ExtractUserMeta(ctx)
+	if userMeta.GetUserId() == "" {
+		return nil, consts.ErrNotAuthentication
+	}
+	aUser, err := s.UserMapper.FindOne(ctx, userMeta.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if aUser.Privacy == nil {
+		aUser.Privacy = make(map[string]string, len(req.Privacy))
+	}
+	for field, level := range req.Privacy {
+		if !validPrivacyField(field) || !validPrivacyLevel(level) {
+			return nil, consts.ErrUpdate
+		}
+		aUser.Privacy[field] = level
+	}
+	if err = s.UserMapper.UpdatePrivacy(ctx, aUser.ID, aUser.Privacy); err != nil {
+		return nil, consts.ErrUpdate
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "更新成功",
+	}, nil
+}
+
+// viewer 查看他人资料的一方，所有返回他人资料的读路径都通过它按隐私设置裁剪
+type viewer struct {
+	userId   string
+	verified bool       // 认证校友或管理员
+	self     *user.User // 查看者本人资料，匿名时为空
+}
+
+// loadViewer 未登录或查询失败时按匿名查看者处理，只能看到公开给所有人的字段
+func loadViewer(ctx context.Context, userMapper *user.MongoMapper) *viewer {
+	v := &viewer{userId: adaptor.ExtractUserMeta(ctx).GetUserId()}
+	if v.userId == "" {
+		return v
+	}
+	if aUser, err := userMapper.FindOne(ctx, v.userId); err == nil {
+		v.self = aUser
+		v.verified = aUser.Status == 0 && aUser.DeleteTime.IsZero() && (aUser.Role == "alumni" || aUser.Role == "admin")
+	}
+	return v
+}
+
+// currentViewer 返回已登录且状态正常的查看者
+func currentViewer(ctx context.Context, userMapper *user.MongoMapper) (*viewer, error) {
+	v := loadViewer(ctx, userMapper)
+	if v.self == nil {
+		return nil, consts.ErrNotAuthentication
+	}
+	if !isLiveUser(v.self) {
+		return nil, consts.ErrForbidden
+	}
+	return v, nil
+}
+
+// levels 查看者能看到的他人字段可见范围
+func (v *viewer) levels() []string {
+	if v.verified {
+		return []string{user.PrivacyPublic, user.PrivacyAlumni}
+	}
+	return []string{user.PrivacyPublic}
+}
+
+func (v *viewer) canSee(owner *user.User, field string) bool {
+	if v.userId != "" && v.userId == owner.ID.Hex() {
+		return true
+	}
+	switch owner.PrivacyLevel(field) {
+	case user.PrivacyPublic:
+		return true
+	case user.PrivacyAlumni:
+		return v.verified
+	default:
+		return false
+	}
+}
+
+// fieldCondition 查询条件：字段分组对查看者可见，未设置时按默认范围判断
+func (v *viewer) fieldCondition(field string) bson.M {
+	levels := v.levels()
+	conditions := []bson.M{{"privacy." + field: bson.M{"$in": levels}}}
+	for _, level := range levels {
+		if level == user.DefaultPrivacyLevel(field) {
+			conditions = append(conditions, bson.M{"privacy." + field: bson.M{"$in": []any{nil, ""}}})
+			break
+		}
+	}
+	return bson.M{"$or": conditions}
+}
+
+// redact 返回按隐私设置裁剪后的副本，不修改原对象
+func (v *viewer) redact(owner *user.User) *user.User {
+	masked := *owner
+	if !v.canSee(owner, user.FieldHometown) {
+		masked.Hometown, masked.HometownCode = "", ""
+	}
+	if !v.canSee(owner, user.FieldEducations) {
+		masked.HomeEducations, masked.ShanghaiEducations = nil, nil
+	}
+	if !v.canSee(owner, user.FieldEmployments) {
+		masked.Employments = nil
+	}
+	if !v.canSee(owner, user.FieldPhone) {
+		masked.Phone = ""
+	}
+	if !v.canSee(owner, user.FieldWxId) {
+		masked.WxId = ""
+	}
+	if !v.canSee(owner, user.FieldBirthday) {
+		masked.Birthday = time.Time{}
+	}
+	masked.Privacy = nil
+	return &masked
+}
+
+func validPrivacyField(field string) bool {
+	for _, f := range user.PrivacyFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func validPrivacyLevel(level string) bool {
+	switch level {
+	case user.PrivacyPrivate, user.PrivacyAlumni, user.PrivacyPublic:
+		return true
+	default:
+		return false
+	}
+}
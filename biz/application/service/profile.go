@@ -0,0 +1,84 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+)
+
+// sharedContexts 计算查看者与对方的共同点，target 须为已按隐私设置裁剪的资料，避免通过共同点泄露未公开信息
+func sharedContexts(self, target *user.User) []*core_api.SharedContext {
+	shared := make([]*core_api.SharedContext, 0)
+	if self == nil || self.ID == target.ID {
+		return shared
+	}
+	if sameHometown(self, target) {
+		shared = append(shared, &core_api.SharedContext{Type: user.FieldHometown, Value: target.Hometown})
+	}
+
+	schools := map[string]*core_api.SharedContext{}
+	mine := append(append([]user.Education{}, self.HomeEducations...), self.ShanghaiEducations...)
+	theirs := append(append([]user.Education{}, target.HomeEducations...), target.ShanghaiEducations...)
+	for _, a := range theirs {
+		for _, b := range mine {
+			if !sameSchool(a, b) {
+				continue
+			}
+			key := strings.ToLower(strings.TrimSpace(a.School))
+			if a.SchoolId != "" {
+				key = a.SchoolId
+			}
+			item, ok := schools[key]
+			if !ok {
+				item = &core_api.SharedContext{Type: "school", Value: a.School}
+				schools[key] = item
+				shared = append(shared, item)
+			}
+			if a.Year > 0 && a.Year == b.Year {
+				item.Year = a.Year
+			}
+		}
+	}
+
+	organizations := map[string]bool{}
+	for _, a := range target.Employments {
+		for _, b := range self.Employments {
+			key := strings.ToLower(strings.TrimSpace(a.Organization))
+			if a.Organization == "" || organizations[key] || !sameText(a.Organization, b.Organization) {
+				continue
+			}
+			organizations[key] = true
+			shared = append(shared, &core_api.SharedContext{Type: "organization", Value: a.Organization})
+		}
+	}
+	return shared
+}
+
+// sameHometown 双方都填写了行政区划时按代码判断，否则比较填写的文字
+func sameHometown(a, b *user.User) bool {
+	if a.HometownCode != "" && b.HometownCode != "" {
+		return a.HometownCode == b.HometownCode
+	}
+	return a.Hometown != "" && sameText(a.Hometown, b.Hometown)
+}
+
+func sameText(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// sameSchool 两段教育经历都关联了学校字典时按字典判断，否则比较填写的文字
+func sameSchool(a, b user.Education) bool {
+	if a.SchoolId != "" && b.SchoolId != "" {
+		return a.SchoolId == b.SchoolId
+	}
+	return a.School != "" && sameText(a.School, b.School)
+}
+
+// sameIndustry 两段工作经历都关联了行业字典时按字典判断，否则比较填写的文字
+func sameIndustry(a, b user.Employment) bool {
+	if a.IndustryId != "" && b.IndustryId != "" {
+		return a.IndustryId == b.IndustryId
+	}
+	return a.Industry != "" && sameText(a.Industry, b.Industry)
+}
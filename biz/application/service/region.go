@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/audit"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/region"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultRegionLimit = 20
+	maxRegionLimit     = 50
+)
+
+type IRegionService interface {
+	GetRegions(ctx context.Context, req *core_api.GetRegionsReq) (*core_api.GetRegionsResp, error)
+	SearchRegions(ctx context.Context, req *core_api.SearchRegionsReq) (*core_api.GetRegionsResp, error)
+	UpdateHometown(ctx context.Context, req *core_api.UpdateHometownReq) (*core_api.Response, error)
+	GetRegionStats(ctx context.Context, req *core_api.GetRegionStatsReq) (*core_api.RegionStatsResp, error)
+	AdminRegionStats(ctx context.Context, code string) (*core_api.RegionStatsResp, error)
+	MigrateHometowns(ctx context.Context) (*MigrationResult, error)
+}
+
+type RegionService struct {
+	UserMapper  *user.MongoMapper
+	AuditMapper *audit.MongoMapper
+	Regions     *region.Dataset
+}
+
+var RegionServiceSet = wire.NewSet(
+	wire.Struct(new(RegionService), "*"),
+	wire.Bind(new(IRegionService), new(*RegionService)),
+)
+
+func (s *RegionService) GetRegions(_ context.Context, req *core_api.GetRegionsReq) (*core_api.GetRegionsResp, error) {
+	parentCode := strings.TrimSpace(req.ParentCode)
+	if parentCode != "" && s.Regions.Get(parentCode) == nil {
+		return nil, consts.ErrRegionInvalid
+	}
+	return &core_api.GetRegionsResp{Regions: mapRegions(s.Regions, s.Regions.Children(parentCode))}, nil
+}
+
+func (s *RegionService) SearchRegions(_ context.Context, req *core_api.SearchRegionsReq) (*core_api.GetRegionsResp, error) {
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultRegionLimit
+	}
+	if limit > maxRegionLimit {
+		limit = maxRegionLimit
+	}
+	return &core_api.GetRegionsResp{Regions: mapRegions(s.Regions, s.Regions.Search(req.Keyword, limit))}, nil
+}
+
+// UpdateHometown 按行政区划代码设置家乡，Hometown 同步为区划的完整名称
+func (s *RegionService) UpdateHometown(ctx context.Context, req *core_api.UpdateHometownReq) (*core_api.Response, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	code, hometown := strings.TrimSpace(req.Code), ""
+	if code != "" {
+		if s.Regions.Get(code) == nil {
+			return nil, consts.ErrRegionInvalid
+		}
+		hometown = s.Regions.FullName(code)
+	}
+	if err = s.UserMapper.UpdateHometown(ctx, v.self.ID, hometown, code); err != nil {
+		return nil, consts.ErrUpdate
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "更新成功",
+	}, nil
+}
+
+// GetRegionStats 认证校友查看各地同乡人数，只统计家乡对认证校友可见的用户
+func (s *RegionService) GetRegionStats(ctx context.Context, req *core_api.GetRegionStatsReq) (*core_api.RegionStatsResp, error) {
+	v, err := currentViewer(ctx, s.UserMapper)
+	if err != nil {
+		return nil, err
+	}
+	if !v.verified {
+		return nil, consts.ErrRegionNotVerified
+	}
+	code := strings.TrimSpace(req.Code)
+	if code != "" && s.Regions.Get(code) == nil {
+		return nil, consts.ErrRegionInvalid
+	}
+	return s.regionStats(ctx, code, v.fieldCondition(user.FieldHometown))
+}
+
+func (s *RegionService) AdminRegionStats(ctx context.Context, code string) (*core_api.RegionStatsResp, error) {
+	code = strings.TrimSpace(code)
+	if code != "" && s.Regions.Get(code) == nil {
+		return nil, ErrAdminBadRequest
+	}
+	return s.regionStats(ctx, code, bson.M{})
+}
+
+// MigrateHometowns 为只填写了文字的家乡匹配行政区划代码，保留原文字，可在补充区划数据后重复执行
+func (s *RegionService) MigrateHometowns(ctx context.Context) (*MigrationResult, error) {
+	result := &MigrationResult{}
+	misses := missCounter{}
+	filter := bson.M{
+		"hometown":      bson.M{"$nin": []any{nil, ""}},
+		"hometown_code": bson.M{"$in": []any{nil, ""}},
+	}
+	var after primitive.ObjectID
+	for {
+		users, err := s.UserMapper.FindAfter(ctx, filter, after, migrationBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			result.Scanned++
+			code := s.Regions.Resolve(u.Hometown)
+			if code == "" {
+				misses.add(u.Hometown)
+				continue
+			}
+			if err = s.UserMapper.UpdateHometown(ctx, u.ID, u.Hometown, code); err != nil {
+				return nil, err
+			}
+			result.Matched++
+			result.Updated++
+		}
+		if int64(len(users)) < migrationBatchSize {
+			break
+		}
+		after = users[len(users)-1].ID
+	}
+	result.Unmatched = misses.top(maxUnmatchedTexts)
+	recordAudit(ctx, s.AuditMapper, "user.migrate_hometown", AuditUser, "", nil, snapshot(result))
+	return result, nil
+}
+
+// regionStats 统计 code 下各下级区划的人数，code 为空时按省级区划统计；数据中不存在的代码计入最近的上级
+func (s *RegionService) regionStats(ctx context.Context, code string, filter bson.M) (*core_api.RegionStatsResp, error) {
+	resp := &core_api.RegionStatsResp{Stats: []*core_api.RegionStat{}}
+	conditions := []bson.M{
+		filter,
+		{"$or": []bson.M{{consts.Status: int64(0)}, {consts.Status: bson.M{"$exists": false}}}},
+		{"$or": []bson.M{{consts.DeleteTime: bson.M{"$exists": false}}, {consts.DeleteTime: time.Time{}}}},
+	}
+	if r := s.Regions.Get(code); r != nil {
+		resp.Region = mapRegion(s.Regions, r)
+		conditions = append(conditions, bson.M{"hometown_code": bson.M{"$regex": "^" + regexp.QuoteMeta(r.Prefix())}})
+	}
+	counts, err := s.UserMapper.CountByHometown(ctx, bson.M{"$and": conditions})
+	if err != nil {
+		return nil, err
+	}
+
+	children := map[string]int64{}
+	for hometownCode, count := range counts {
+		nearest := s.Regions.Nearest(hometownCode)
+		if nearest == nil {
+			continue
+		}
+		path := s.Regions.Path(nearest.Code)
+		depth := 0
+		if code != "" {
+			for depth < len(path) && path[depth].Code != code {
+				depth++
+			}
+			if depth == len(path) {
+				continue
+			}
+			depth++
+		}
+		resp.Total += count
+		if depth == len(path) {
+			resp.Direct += count
+			continue
+		}
+		children[path[depth].Code] += count
+	}
+	for childCode, count := range children {
+		resp.Stats = append(resp.Stats, &core_api.RegionStat{Region: mapRegion(s.Regions, s.Regions.Get(childCode)), Count: count})
+	}
+	sort.Slice(resp.Stats, func(i, j int) bool {
+		if resp.Stats[i].Count != resp.Stats[j].Count {
+			return resp.Stats[i].Count > resp.Stats[j].Count
+		}
+		return resp.Stats[i].Region.Code < resp.Stats[j].Region.Code
+	})
+	return resp, nil
+}
+
+func mapRegions(regions *region.Dataset, items []*region.Region) []*core_api.Region {
+	result := make([]*core_api.Region, 0, len(items))
+	for _, item := range items {
+		result = append(result, mapRegion(regions, item))
+	}
+	return result
+}
+
+func mapRegion(regions *region.Dataset, item *region.Region) *core_api.Region {
+	return &core_api.Region{
+		Code:       item.Code,
+		Name:       item.Name,
+		Level:      int64(item.Level),
+		ParentCode: item.Parent,
+		FullName:   regions.FullName(item.Code),
+	}
+}
@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/audit"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/role"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// 后台权限
+const (
+	PermUserRead           = "user:read"
+	PermUserWrite          = "user:write"
+	PermRegistrationRead   = "registration:read"
+	PermRegistrationWrite  = "registration:write"
+	PermActivityWrite      = "activity:write"
+	PermOrderRead          = "order:read"
+	PermOrderRefund        = "order:refund"
+	PermOrganizationRead   = "organization:read"
+	PermOrganizationWrite  = "organization:write"
+	PermVerificationReview = "verification:review"
+	PermArticleRead        = "article:read"
+	PermArticleWrite       = "article:write"
+	PermRoleManage         = "role:manage"
+	PermAuditRead          = "audit:read"
+	PermJobReview          = "job:review"
+	PermDictionaryWrite    = "dictionary:write"
+	PermGroupManage        = "group:manage"
+)
+
+// RoleSuperAdmin 超级管理员拥有全部权限且不可修改，用户 Role 为 admin 时同样视为超级管理员
+const RoleSuperAdmin = "super_admin"
+
+type Permission struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+var permissions = []Permission{
+	{Key: PermUserRead, Name: "查看用户"},
+	{Key: PermUserWrite, Name: "编辑用户"},
+	{Key: PermRegistrationRead, Name: "查看报名"},
+	{Key: PermRegistrationWrite, Name: "管理报名"},
+	{Key: PermActivityWrite, Name: "管理活动"},
+	{Key: PermOrderRead, Name: "查看订单"},
+	{Key: PermOrderRefund, Name: "订单退款"},
+	{Key: PermOrganizationRead, Name: "查看组织"},
+	{Key: PermOrganizationWrite, Name: "管理组织"},
+	{Key: PermVerificationReview, Name: "审核校友认证"},
+	{Key: PermArticleRead, Name: "查看文章"},
+	{Key: PermArticleWrite, Name: "管理文章"},
+	{Key: PermRoleManage, Name: "管理角色与权限"},
+	{Key: PermAuditRead, Name: "查看审计日志"},
+	{Key: PermJobReview, Name: "审核招聘信息"},
+	{Key: PermDictionaryWrite, Name: "管理学校与行业字典"},
+	{Key: PermGroupManage, Name: "管理校友群组"},
+}
+
+// builtinRoles 内置角色，除超级管理员外可通过后台覆盖其权限，删除覆盖后恢复默认
+var builtinRoles = []role.Role{
+	{Key: RoleSuperAdmin, Name: "超级管理员", Permissions: allPermissions()},
+	{Key: "content_editor", Name: "内容编辑", Permissions: []string{PermArticleRead, PermArticleWrite}},
+	{Key: "event_manager", Name: "活动管理员", Permissions: []string{
+		PermActivityWrite, PermRegistrationRead, PermRegistrationWrite, PermOrderRead, PermOrderRefund, PermOrganizationRead,
+	}},
+	{Key: "user_auditor", Name: "用户审计员", Permissions: []string{PermUserRead}},
+}
+
+var roleKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,31}$`)
+
+type IRoleService interface {
+	ListPermissions(ctx context.Context) []Permission
+	ListRoles(ctx context.Context) ([]AdminRole, error)
+	CreateRole(ctx context.Context, actor *AdminSession, input AdminRoleInput) (*AdminRole, error)
+	UpdateRole(ctx context.Context, actor *AdminSession, key string, input AdminRoleInput) (*AdminRole, error)
+	DeleteRole(ctx context.Context, key string) error
+	SetUserAdminRoles(ctx context.Context, actor *AdminSession, id string, roles []string) error
+}
+
+type RoleService struct {
+	UserMapper  *user.MongoMapper
+	RoleMapper  *role.MongoMapper
+	AuditMapper *audit.MongoMapper
+}
+
+var RoleServiceSet = wire.NewSet(
+	wire.Struct(new(RoleService), "*"),
+	wire.Bind(new(IRoleService), new(*RoleService)),
+)
+
+type AdminRole struct {
+	Key         string   `json:"key"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+	Builtin     bool     `json:"builtin"`
+	Customized  bool     `json:"customized"` // 内置角色的权限已被覆盖
+}
+
+type AdminRoleInput struct {
+	Key         string   `json:"key"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+func (s *RoleService) ListPermissions(_ context.Context) []Permission {
+	return permissions
+}
+
+func (s *RoleService) ListRoles(ctx context.Context) ([]AdminRole, error) {
+	stored, err := s.RoleMapper.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	overrides := make(map[string]*role.Role, len(stored))
+	for _, item := range stored {
+		overrides[item.Key] = item
+	}
+	result := make([]AdminRole, 0, len(builtinRoles)+len(stored))
+	for _, item := range builtinRoles {
+		adminRole := AdminRole{Key: item.Key, Name: item.Name, Permissions: item.Permissions, Builtin: true}
+		if override, ok := overrides[item.Key]; ok && item.Key != RoleSuperAdmin {
+			adminRole.Name, adminRole.Permissions, adminRole.Customized = override.Name, override.Permissions, true
+		}
+		delete(overrides, item.Key)
+		result = append(result, adminRole)
+	}
+	for _, item := range stored {
+		if _, ok := overrides[item.Key]; ok {
+			result = append(result, AdminRole{Key: item.Key, Name: item.Name, Permissions: item.Permissions})
+		}
+	}
+	return result, nil
+}
+
+func (s *RoleService) CreateRole(ctx context.Context, actor *AdminSession, input AdminRoleInput) (*AdminRole, error) {
+	input.Key = strings.TrimSpace(input.Key)
+	if !roleKeyPattern.MatchString(input.Key) || builtinRole(input.Key) != nil {
+		return nil, ErrAdminBadRequest
+	}
+	existing, err := s.RoleMapper.FindByKeys(ctx, []string{input.Key})
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, ErrAdminBadRequest
+	}
+	return s.saveRole(ctx, actor, input.Key, input, nil)
+}
+
+func (s *RoleService) UpdateRole(ctx context.Context, actor *AdminSession, key string, input AdminRoleInput) (*AdminRole, error) {
+	if key == RoleSuperAdmin {
+		return nil, ErrAdminBadRequest
+	}
+	existing, err := findRoles(ctx, s.RoleMapper, []string{key})
+	if err != nil {
+		return nil, err
+	}
+	if existing[key] == nil {
+		return nil, ErrAdminNotFound
+	}
+	if !canGrant(actor, existing[key]) {
+		return nil, ErrAdminForbidden
+	}
+	return s.saveRole(ctx, actor, key, input, existing[key])
+}
+
+// DeleteRole 删除自定义角色，仍有用户使用时拒绝删除；对内置角色则是恢复默认权限
+func (s *RoleService) DeleteRole(ctx context.Context, key string) error {
+	if key == RoleSuperAdmin {
+		return ErrAdminBadRequest
+	}
+	if builtinRole(key) == nil {
+		_, total, err := s.UserMapper.FindMany(ctx, bson.M{"admin_roles": key}, 0, 1)
+		if err != nil {
+			return err
+		}
+		if total > 0 {
+			return ErrAdminBadRequest
+		}
+	}
+	stored, err := s.RoleMapper.FindByKeys(ctx, []string{key})
+	if err != nil {
+		return err
+	}
+	deleted, err := s.RoleMapper.DeleteByKey(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		if builtinRole(key) == nil {
+			return ErrAdminNotFound
+		}
+		return nil
+	}
+	var after map[string]any
+	if item := builtinRole(key); item != nil {
+		after = snapshot(item)
+	}
+	recordAudit(ctx, s.AuditMapper, "role.delete", AuditRole, key, snapshot(stored[0]), after)
+	return nil
+}
+
+// SetUserAdminRoles 设置用户的后台角色，操作者只能授予或收回自身权限范围内的角色
+func (s *RoleService) SetUserAdminRoles(ctx context.Context, actor *AdminSession, id string, roles []string) error {
+	roles = uniqueStrings(roles)
+	item, err := s.UserMapper.FindOne(ctx, id)
+	if err != nil {
+		return err
+	}
+	changed := changedRoles(item.AdminRoles, roles)
+	definitions, err := findRoles(ctx, s.RoleMapper, append(append([]string{}, roles...), changed...))
+	if err != nil {
+		return err
+	}
+	for _, key := range roles {
+		if definitions[key] == nil {
+			return ErrAdminBadRequest
+		}
+	}
+	for _, key := range changed {
+		if definition := definitions[key]; definition != nil && !canGrant(actor, definition) {
+			return ErrAdminForbidden
+		}
+	}
+	before := snapshot(item)
+	item.AdminRoles = roles
+	if err = s.UserMapper.UpdateAdminRoles(ctx, item.ID, roles); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.AuditMapper, "user.set_admin_roles", AuditUser, item.ID.Hex(), before, snapshot(item))
+	return nil
+}
+
+// saveRole 新建或覆盖角色定义并记录审计，before 为变更前的角色定义
+func (s *RoleService) saveRole(ctx context.Context, actor *AdminSession, key string, input AdminRoleInput, before *role.Role) (*AdminRole, error) {
+	name := strings.TrimSpace(input.Name)
+	perms := uniqueStrings(input.Permissions)
+	if name == "" {
+		return nil, ErrAdminBadRequest
+	}
+	for _, perm := range perms {
+		if !validPermission(perm) {
+			return nil, ErrAdminBadRequest
+		}
+	}
+	if !canGrant(actor, &role.Role{Key: key, Permissions: perms}) {
+		return nil, ErrAdminForbidden
+	}
+	item := &role.Role{Key: key, Name: name, Permissions: perms}
+	if err := s.RoleMapper.Upsert(ctx, item); err != nil {
+		return nil, err
+	}
+	action := "role.create"
+	var previous map[string]any
+	if before != nil {
+		action, previous = "role.update", snapshot(before)
+	}
+	recordAudit(ctx, s.AuditMapper, action, AuditRole, key, previous, snapshot(item))
+	return &AdminRole{Key: key, Name: name, Permissions: perms, Builtin: builtinRole(key) != nil, Customized: builtinRole(key) != nil}, nil
+}
+
+// findRoles 查找角色定义，库中的定义优先于内置默认值，超级管理员始终使用内置定义
+func findRoles(ctx context.Context, roleMapper *role.MongoMapper, keys []string) (map[string]*role.Role, error) {
+	stored, err := roleMapper.FindByKeys(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*role.Role, len(keys))
+	for _, item := range stored {
+		if item.Key != RoleSuperAdmin {
+			result[item.Key] = item
+		}
+	}
+	for _, key := range keys {
+		if _, ok := result[key]; !ok {
+			if item := builtinRole(key); item != nil {
+				result[key] = item
+			}
+		}
+	}
+	return result, nil
+}
+
+// resolvePermissions 计算用户的后台角色与有效权限
+func resolvePermissions(ctx context.Context, roleMapper *role.MongoMapper, u *user.User) ([]string, []string, error) {
+	keys := append([]string{}, u.AdminRoles...)
+	if u.Role == "admin" {
+		keys = append(keys, RoleSuperAdmin)
+	}
+	keys = uniqueStrings(keys)
+	definitions, err := findRoles(ctx, roleMapper, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	granted := map[string]bool{}
+	roles := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if item, ok := definitions[key]; ok {
+			roles = append(roles, key)
+			for _, perm := range item.Permissions {
+				granted[perm] = true
+			}
+		}
+	}
+	perms := make([]string, 0, len(granted))
+	for _, perm := range permissions {
+		if granted[perm.Key] {
+			perms = append(perms, perm.Key)
+		}
+	}
+	return roles, perms, nil
+}
+
+// canGrant 判断操作者能否授予、收回或修改角色：超级管理员只能由超级管理员授予，其余角色的权限须为操作者权限的子集
+func canGrant(actor *AdminSession, r *role.Role) bool {
+	if actor == nil {
+		return false
+	}
+	if r.Key == RoleSuperAdmin {
+		return slices.Contains(actor.Roles, RoleSuperAdmin)
+	}
+	for _, perm := range r.Permissions {
+		if !actor.Can(perm) {
+			return false
+		}
+	}
+	return true
+}
+
+// changedRoles 返回 before 与 after 中只出现在一方的角色
+func changedRoles(before, after []string) []string {
+	result := make([]string, 0)
+	for _, key := range before {
+		if !slices.Contains(after, key) {
+			result = append(result, key)
+		}
+	}
+	for _, key := range after {
+		if !slices.Contains(before, key) {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+func builtinRole(key string) *role.Role {
+	for i := range builtinRoles {
+		if builtinRoles[i].Key == key {
+			return &builtinRoles[i]
+		}
+	}
+	return nil
+}
+
+func hasPermission(perms []string, perm string) bool {
+	for _, item := range perms {
+		if item == perm {
+			return true
+		}
+	}
+	return false
+}
+
+func validPermission(perm string) bool {
+	for _, item := range permissions {
+		if item.Key == perm {
+			return true
+		}
+	}
+	return false
+}
+
+func allPermissions() []string {
+	result := make([]string, 0, len(permissions))
+	for _, item := range permissions {
+		result = append(result, item.Key)
+	}
+	return result
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/role"
+)
+
+func TestCanGrant(t *testing.T) {
+	manager := &AdminSession{Roles: []string{"role_manager"}, Permissions: []string{PermRoleManage, PermArticleRead, PermArticleWrite}}
+	superAdmin := &AdminSession{Roles: []string{RoleSuperAdmin}, Permissions: allPermissions()}
+	cases := []struct {
+		name  string
+		actor *AdminSession
+		role  *role.Role
+		want  bool
+	}{
+		{"subset of own permissions", manager, builtinRole("content_editor"), true},
+		{"permission the actor lacks", manager, builtinRole("event_manager"), false},
+		{"super admin by role manager", manager, builtinRole(RoleSuperAdmin), false},
+		{"super admin by super admin", superAdmin, builtinRole(RoleSuperAdmin), true},
+		{"all permissions without super admin", &AdminSession{Permissions: allPermissions()}, builtinRole(RoleSuperAdmin), false},
+		{"missing session", nil, builtinRole("content_editor"), false},
+	}
+	for _, c := range cases {
+		if got := canGrant(c.actor, c.role); got != c.want {
+			t.Errorf("%s: canGrant = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/session"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util/log"
+	"github.com/xh-polaris/service-idl-gen-go/kitex_gen/basic"
+)
+
+const defaultRefreshExpire = int64(30 * 24 * 3600)
+
+type ISessionService interface {
+	RefreshSession(ctx context.Context, req *core_api.RefreshSessionReq) (*core_api.AuthTokens, error)
+	Logout(ctx context.Context, req *core_api.LogoutReq) (*core_api.Response, error)
+	ValidateSession(ctx context.Context, meta *basic.UserMeta, issuedAt int64) bool
+}
+
+type SessionService struct {
+	UserMapper    *user.MongoMapper
+	SessionMapper *session.MongoMapper
+}
+
+var SessionServiceSet = wire.NewSet(
+	wire.Struct(new(SessionService), "*"),
+	wire.Bind(new(ISessionService), new(*SessionService)),
+)
+
+// RefreshSession 使用刷新令牌换取新的令牌，旧刷新令牌随即失效；已轮换的刷新令牌再次出现时吊销整个会话
+func (s *SessionService) RefreshSession(ctx context.Context, req *core_api.RefreshSessionReq) (*core_api.AuthTokens, error) {
+	if req.RefreshToken == "" {
+		return nil, consts.ErrRefreshToken
+	}
+	hash := hashRefreshToken(req.RefreshToken)
+	sess, err := s.SessionMapper.FindByRefreshHash(ctx, hash)
+	if err != nil {
+		return nil, consts.ErrRefreshToken
+	}
+	if sess.RefreshHash != hash {
+		log.CtxInfo(ctx, "[RefreshSession] rotated refresh token reused, session=%s", sess.ID.Hex())
+		if err = s.SessionMapper.Revoke(ctx, sess.ID); err != nil {
+			return nil, err
+		}
+		return nil, consts.ErrRefreshToken
+	}
+	if sess.Revoked() || time.Now().After(sess.ExpireTime) {
+		return nil, consts.ErrRefreshToken
+	}
+	aUser, err := s.UserMapper.FindOne(ctx, sess.UserId)
+	if err != nil || aUser.Deletion.Purged || !isLiveUser(aUser) {
+		return nil, consts.ErrRefreshToken
+	}
+
+	refreshToken, nextHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	expire := time.Now().Add(time.Duration(refreshExpire()) * time.Second)
+	rotated, err := s.SessionMapper.Rotate(ctx, sess.ID, hash, nextHash, expire)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, consts.ErrRefreshToken
+	}
+	return signSession(sess.UserId, sess.ID.Hex(), refreshToken, expire, wechatMeta(aUser))
+}
+
+// Logout 吊销当前会话，All 为 true 时吊销全部会话，并使此前签发的其他令牌（包括中台签发的令牌）失效
+func (s *SessionService) Logout(ctx context.Context, req *core_api.LogoutReq) (*core_api.Response, error) {
+	userMeta := adaptor.ExtractUserMeta(ctx)
+	if userMeta.GetUserId() == "" {
+		return nil, consts.ErrNotAuthentication
+	}
+	if req.All {
+		if _, err := s.SessionMapper.RevokeByUser(ctx, userMeta.GetUserId()); err != nil {
+			return nil, err
+		}
+		aUser, err := s.UserMapper.FindOne(ctx, userMeta.GetUserId())
+		if err != nil {
+			return nil, err
+		}
+		if err = s.UserMapper.UpdateTokenValidAfter(ctx, aUser.ID, time.Now()); err != nil {
+			return nil, consts.ErrUpdate
+		}
+	} else if sess, err := s.SessionMapper.FindByID(ctx, userMeta.GetSessionDeviceId()); err == nil && sess.UserId == userMeta.GetUserId() {
+		if err = s.SessionMapper.Revoke(ctx, sess.ID); err != nil {
+			return nil, err
+		}
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "已退出登录",
+	}, nil
+}
+
+// ValidateSession 鉴权时校验令牌：本服务签发的令牌要求会话未被吊销，所有令牌都要求用户未被停用或注销
+func (s *SessionService) ValidateSession(ctx context.Context, meta *basic.UserMeta, issuedAt int64) bool {
+	userId := meta.GetUserId()
+	if userId == "" {
+		return true
+	}
+	// 中台签发的令牌中的 DeviceId 不是会话 id，查不到会话时只校验用户状态；数据库异常时拒绝
+	sess, err := s.SessionMapper.FindByID(ctx, meta.GetSessionDeviceId())
+	switch {
+	case err == nil:
+		if sess.Revoked() || sess.UserId != userId {
+			return false
+		}
+	case errors.Is(err, consts.ErrNotFound), errors.Is(err, consts.ErrInvalidObjectId):
+	default:
+		return false
+	}
+	aUser, err := s.UserMapper.FindOne(ctx, userId)
+	if errors.Is(err, consts.ErrNotFound) {
+		// 首次登录时本地用户尚未创建
+		return true
+	} else if err != nil {
+		return false
+	}
+	if aUser.Deletion.Purged || !isLiveUser(aUser) {
+		return false
+	}
+	return aUser.TokenValidAfter.IsZero() || issuedAt >= aUser.TokenValidAfter.Unix()
+}
+
+// issueSession 登录成功后创建会话并签发令牌
+func issueSession(ctx context.Context, sessionMapper *session.MongoMapper, userId string, wechat *basic.WechatUserMeta) (*core_api.AuthTokens, error) {
+	refreshToken, hash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	sess := &session.Session{
+		UserId:      userId,
+		RefreshHash: hash,
+		ClientIP:    adaptor.ExtractExtra(ctx).GetClientIP(),
+		ExpireTime:  time.Now().Add(time.Duration(refreshExpire()) * time.Second),
+	}
+	if err = sessionMapper.Insert(ctx, sess); err != nil {
+		return nil, err
+	}
+	return signSession(userId, sess.ID.Hex(), refreshToken, sess.ExpireTime, wechat)
+}
+
+func signSession(userId, sessionId, refreshToken string, refreshExpire time.Time, wechat *basic.WechatUserMeta) (*core_api.AuthTokens, error) {
+	accessToken, accessExpire, err := util.SignAccessToken(userId, sessionId, wechat)
+	if err != nil {
+		return nil, err
+	}
+	return &core_api.AuthTokens{
+		Id:            userId,
+		AccessToken:   accessToken,
+		AccessExpire:  accessExpire,
+		RefreshToken:  refreshToken,
+		RefreshExpire: refreshExpire.Unix(),
+	}, nil
+}
+
+func refreshExpire() int64 {
+	if expire := config.GetConfig().Auth.RefreshExpire; expire > 0 {
+		return expire
+	}
+	return defaultRefreshExpire
+}
+
+// newRefreshToken 生成随机刷新令牌，数据库中只保存其哈希
+func newRefreshToken() (token, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err = rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// checkSignIn 登录前检查 IP 失败次数与账号锁定状态，返回手机号对应的本地用户，未注册时返回 nil
+func (u *UserService) checkSignIn(ctx context.Context, phone string) (*user.User, error) {
+	conf := config.GetConfig().SignInGuard
+	if clientIP := adaptor.ExtractExtra(ctx).GetClientIP(); clientIP != "" && conf.IPFailureLimit > 0 {
+		failures, err := u.Limiter.Count(ctx, signInIPKey(clientIP), time.Duration(conf.IPWindow)*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		if failures >= conf.IPFailureLimit {
+			log.CtxInfo(ctx, "[SignIn] too many failures from ip=%s", clientIP)
+			return nil, consts.ErrTooManyRequests
+		}
+	}
+	aUser, err := u.UserMapper.FindLiveByPhone(ctx, phone, primitive.NilObjectID)
+	if err == consts.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if aUser.SignInGuard.Locked(time.Now()) {
+		return nil, consts.ErrSignInLocked
+	}
+	return aUser, nil
+}
+
+// recordSignInFailure 记录一次登录失败，账号连续失败达到上限时按指数退避锁定
+func (u *UserService) recordSignInFailure(ctx context.Context, aUser *user.User) {
+	conf := config.GetConfig().SignInGuard
+	if clientIP := adaptor.ExtractExtra(ctx).GetClientIP(); clientIP != "" && conf.IPFailureLimit > 0 {
+		if _, err := u.Limiter.Allow(ctx, signInIPKey(clientIP), conf.IPFailureLimit, time.Duration(conf.IPWindow)*time.Second); err != nil {
+			log.CtxError(ctx, "[SignIn] record ip failure failed, err=%v", err)
+		}
+	}
+	if aUser == nil || conf.FailureLimit <= 0 {
+		return
+	}
+
+	now := time.Now()
+	guard := aUser.SignInGuard
+	sinceLastFail := now.Sub(guard.LastFailTime)
+	if sinceLastFail > time.Duration(conf.FailureWindow)*time.Second {
+		guard.Failures = 0
+	}
+	// 长时间没有失败记录后，锁定时长重新从 LockSeconds 开始计算
+	if sinceLastFail > time.Duration(conf.MaxLockSeconds)*time.Second {
+		guard.Lockouts = 0
+	}
+	guard.Failures++
+	guard.LastFailTime = now
+	if guard.Failures >= conf.FailureLimit {
+		guard.LockedUntil = now.Add(lockDuration(conf, guard.Lockouts))
+		guard.Lockouts++
+		guard.Failures = 0
+		log.CtxInfo(ctx, "[SignIn] user locked, id=%s, until=%s", aUser.ID.Hex(), guard.LockedUntil.Format(time.DateTime))
+	}
+	if err := u.UserMapper.UpdateSignInGuard(ctx, aUser.ID, guard); err != nil {
+		log.CtxError(ctx, "[SignIn] record failure failed, id=%s, err=%v", aUser.ID.Hex(), err)
+	}
+}
+
+// clearSignInGuard 登录成功后清除失败记录
+func (u *UserService) clearSignInGuard(ctx context.Context, aUser *user.User) {
+	if aUser == nil || aUser.SignInGuard == (user.SignInGuard{}) {
+		return
+	}
+	if err := u.UserMapper.UpdateSignInGuard(ctx, aUser.ID, user.SignInGuard{}); err != nil {
+		log.CtxError(ctx, "[SignIn] clear failures failed, id=%s, err=%v", aUser.ID.Hex(), err)
+	}
+}
+
+// lockDuration 第 lockouts+1 次锁定的时长，从 LockSeconds 起每次翻倍，不超过 MaxLockSeconds
+func lockDuration(conf config.SignInGuard, lockouts int64) time.Duration {
+	lock := time.Duration(conf.LockSeconds) * time.Second
+	maxLock := time.Duration(conf.MaxLockSeconds) * time.Second
+	for i := int64(0); i < lockouts && lock < maxLock; i++ {
+		lock *= 2
+	}
+	if maxLock > 0 && lock > maxLock {
+		lock = maxLock
+	}
+	return lock
+}
+
+func signInIPKey(clientIP string) string {
+	return "sign_in_fail:ip:" + clientIP
+}
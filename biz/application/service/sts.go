@@ -7,12 +7,16 @@ import (
 	"github.com/google/wire"
 	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
 	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/captcha"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/limiter"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/rpc/platform_auth"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/rpc/platform_sts"
-	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util/log"
 	"github.com/xh-polaris/service-idl-gen-go/kitex_gen/platform/sts"
 	"net/http"
+	"strings"
 )
 
 type IStsService interface {
@@ -21,8 +25,11 @@ type IStsService interface {
 }
 
 type StsService struct {
-	PlatformSts platform_sts.IPlatformSts
-	UserMapper  *user.MongoMapper
+	PlatformSts  platform_sts.IPlatformSts
+	PlatformAuth platform_auth.IPlatformAuth
+	UserMapper   *user.MongoMapper
+	Limiter      limiter.ILimiter
+	Captcha      captcha.ICaptcha
 }
 
 var StsServiceSet = wire.NewSet(
@@ -59,31 +66,31 @@ func (s *StsService) ApplySignedUrl(ctx context.Context, req *core_api.ApplySign
 }
 
 func (s *StsService) SendVerifyCode(ctx context.Context, req *core_api.SendVerifyCodeReq) (*core_api.Response, error) {
-	aUser, err := s.UserMapper.FindOneByPhone(ctx, req.AuthId)
-	if req.Type == 1 { // 登录验证码
-		// 查找数据库判断手机号是否注册过
-		if errors.Is(err, consts.ErrNotFound) || aUser == nil { // 未找到，说明没有注册
-			return nil, consts.ErrNotSignUp
-		} else if err != nil {
-			return nil, consts.ErrSend
-		}
-	} else {
-		if err == nil && aUser != nil {
-			return nil, consts.ErrRepeatedSignUp
-		} else if err != nil && !errors.Is(err, consts.ErrNotFound) {
-			return nil, consts.ErrSignUp
-		}
+	authId := strings.TrimSpace(req.AuthId)
+	if authId == "" {
+		return nil, consts.ErrSend
+	}
+	if err := checkVerifyCodeQuota(ctx, s.Limiter, s.Captcha, authId); err != nil {
+		return nil, err
 	}
 
-	// 通过中台发送验证码
-	httpClient := util.NewHttpClient()
-	_, err = httpClient.SendVerifyCode(req.AuthType, req.AuthId)
-	if err != nil {
+	aUser, err := s.UserMapper.FindOneByPhone(ctx, authId)
+	if err != nil && !errors.Is(err, consts.ErrNotFound) {
 		return nil, consts.ErrSend
 	}
+	registered := err == nil && aUser != nil
+	// 登录验证码只发给已注册的手机号，注册验证码只发给未注册的手机号，不满足时不发送但返回相同的结果
+	if registered == (req.Type == 1) {
+		// 通过中台发送验证码
+		if err = s.PlatformAuth.SendVerifyCode(ctx, req.AuthType, authId); err != nil {
+			return nil, err
+		}
+	} else {
+		log.CtxInfo(ctx, "[SendVerifyCode] skip sending, type=%d, registered=%v", req.Type, registered)
+	}
 
 	return &core_api.Response{
 		Code: 0,
-		Msg:  "发送验证码成功，请注意查收",
+		Msg:  verifyCodeSentMsg,
 	}, nil
 }
@@ -2,14 +2,21 @@ package service
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	"github.com/google/wire"
 	"github.com/jinzhu/copier"
 	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
 	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/captcha"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/limiter"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/dictionary"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/session"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/region"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/rpc/platform_auth"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util"
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -17,15 +24,27 @@ import (
 )
 
 type IUserService interface {
-	SignUp(ctx context.Context, req *core_api.SignUpReq) (*core_api.SignUpResp, error)
-	SignIn(ctx context.Context, req *core_api.SignInReq) (*core_api.SignInResp, error)
+	SignUp(ctx context.Context, req *core_api.SignUpReq) (*core_api.AuthTokens, error)
+	SignIn(ctx context.Context, req *core_api.SignInReq) (*core_api.AuthTokens, error)
 	UpdateUserInfo(ctx context.Context, req *core_api.UpdateUserInfoReq) (resp *core_api.Response, err error)
 	UpdateEducation(ctx context.Context, req *core_api.UpdateEducationReq) (resp *core_api.Response, err error)
-	GetUserInfo(ctx context.Context, req *core_api.GetUserInfoReq) (resp *core_api.GetUserInfoResp, err error)
+	GetUserInfo(ctx context.Context, req *core_api.GetUserInfoReq) (resp *core_api.UserInfo, err error)
+	GetUserProfile(ctx context.Context, req *core_api.GetUserProfileReq) (*core_api.UserProfile, error)
 	ExchangeWxPhone(ctx context.Context, code string) (*core_api.ExchangeWxPhoneResp, error)
+	SendPhoneChangeCode(ctx context.Context, req *core_api.SendPhoneChangeCodeReq) (*core_api.Response, error)
+	ChangePhone(ctx context.Context, req *core_api.ChangePhoneReq) (*core_api.Response, error)
+	WxLogin(ctx context.Context, req *core_api.WxLoginReq) (*core_api.WxLoginResp, error)
+	WxBindPhone(ctx context.Context, req *core_api.WxBindPhoneReq) (*core_api.WxLoginResp, error)
 }
 type UserService struct {
-	UserMapper *user.MongoMapper
+	UserMapper       *user.MongoMapper
+	SessionMapper    *session.MongoMapper
+	PlatformAuth     platform_auth.IPlatformAuth
+	Limiter          limiter.ILimiter
+	Captcha          captcha.ICaptcha
+	DictionaryMapper *dictionary.MongoMapper
+	Regions          *region.Dataset
+	Records          *UserRecords
 }
 
 var UserServiceSet = wire.NewSet(
@@ -51,41 +70,55 @@ func (u *UserService) findAuthenticatedUser(ctx context.Context) (*user.User, er
 	return nil, err
 }
 
-func (u *UserService) ensureLocalUser(ctx context.Context, platformUserID, phone, name string) error {
+// ensureLocalUser 确保中台账号对应的本地用户存在，返回本地用户 id，账号已被合并时返回保留的账号；
+// 手机号只在本地用户没有手机号时写入，修改过手机号的用户以旧手机号登录时不会被改回
+func (u *UserService) ensureLocalUser(ctx context.Context, platformUserID, phone, name string) (string, error) {
 	phone = strings.TrimSpace(phone)
 	name = strings.TrimSpace(name)
 
-	if existing, err := u.UserMapper.FindOne(ctx, platformUserID); err == nil {
-		changed := false
-		if phone != "" && existing.Phone != phone {
-			existing.Phone = phone
-			changed = true
+	if existing, err := u.findPlatformUser(ctx, platformUserID); err == nil {
+		if existing, err = u.followMerged(ctx, existing); err != nil {
+			return "", err
+		}
+		if existing.Deletion.Purged {
+			return "", consts.ErrAccountDeleted
+		}
+		fill := map[string]string{}
+		if phone != "" && existing.Phone == "" {
+			fill[consts.Phone] = phone
 		}
 		if name != "" && existing.Name == "" {
-			existing.Name = name
-			changed = true
+			fill[consts.Name] = name
 		}
 		if existing.Role == "" {
-			existing.Role = "user"
-			changed = true
-		}
-		if changed {
-			return u.UserMapper.Update(ctx, existing)
+			fill["role"] = "user"
 		}
-		return nil
+		return existing.ID.Hex(), u.UserMapper.FillEmpty(ctx, existing.ID, fill)
 	} else if err != consts.ErrNotFound {
-		return err
+		return "", err
 	}
 
-	existing, err := u.UserMapper.FindOneByPhone(ctx, phone)
-	if err == nil {
-		return u.linkUserToPlatformID(ctx, existing, platformUserID, name)
-	}
-	if err != consts.ErrNotFound {
-		return err
+	// 只关联正常状态的账号，已注销或已合并的账号即使仍保留该手机号也不会被恢复
+	if phone != "" {
+		existing, err := u.UserMapper.FindLiveByPhone(ctx, phone, primitive.NilObjectID)
+		if err == nil {
+			return platformUserID, u.linkUserToPlatformID(ctx, existing, platformUserID, name)
+		}
+		if !errors.Is(err, consts.ErrNotFound) {
+			return "", err
+		}
 	}
 
-	return u.createLocalUser(ctx, platformUserID, phone, name)
+	return platformUserID, u.createLocalUser(ctx, platformUserID, phone, name)
+}
+
+// findPlatformUser 按中台账号 id 查找本地用户，包括修改手机号后记入 PlatformIds 的账号
+func (u *UserService) findPlatformUser(ctx context.Context, platformUserID string) (*user.User, error) {
+	existing, err := u.UserMapper.FindOne(ctx, platformUserID)
+	if err == consts.ErrNotFound {
+		return u.UserMapper.FindOneByPlatformId(ctx, platformUserID)
+	}
+	return existing, err
 }
 
 func (u *UserService) createLocalUser(ctx context.Context, platformUserID, phone, name string) error {
@@ -129,44 +162,45 @@ func (u *UserService) linkUserToPlatformID(ctx context.Context, existing *user.U
 	if err := u.UserMapper.Insert(ctx, &migrated); err != nil {
 		return err
 	}
-	return u.UserMapper.SoftDeleteByID(ctx, existing.ID)
+	if _, err := u.Records.Move(ctx, existing.ID.Hex(), oid.Hex()); err != nil {
+		return err
+	}
+	return u.UserMapper.MarkMerged(ctx, existing.ID, oid.Hex())
 }
 
-func (u *UserService) SignUp(ctx context.Context, req *core_api.SignUpReq) (*core_api.SignUpResp, error) {
+func (u *UserService) SignUp(ctx context.Context, req *core_api.SignUpReq) (*core_api.AuthTokens, error) {
 	if req.AuthType != "phone" || strings.TrimSpace(req.AuthId) == "" || strings.TrimSpace(req.Password) == "" {
 		return nil, consts.ErrSignUp
 	}
 
-	httpClient := util.NewHttpClient()
-	signUpResponse, err := httpClient.SignUp(req.AuthType, strings.TrimSpace(req.AuthId), &req.VerifyCode)
-	if err != nil {
+	signUpResp, err := u.PlatformAuth.SignIn(ctx, &platform_auth.SignInReq{
+		AuthType:   req.AuthType,
+		AuthId:     strings.TrimSpace(req.AuthId),
+		VerifyCode: &req.VerifyCode,
+	})
+	if err == consts.ErrSignIn {
 		return nil, consts.ErrSignUp
+	} else if err != nil {
+		return nil, err
 	}
-
-	authorization := signUpResponse["accessToken"].(string)
-	_, err = httpClient.SetPassword(authorization, req.Password)
-	if err != nil {
-		return nil, consts.ErrSignUp
+	if err = u.PlatformAuth.SetPassword(ctx, signUpResp.AccessToken, req.Password); err != nil {
+		return nil, err
 	}
 
-	userId := signUpResponse["userId"].(string)
-	if err := u.ensureLocalUser(ctx, userId, req.AuthId, req.Name); err != nil {
+	userId, err := u.ensureLocalUser(ctx, signUpResp.UserId, req.AuthId, req.Name)
+	if err != nil {
 		return nil, consts.ErrSignUp
 	}
 
-	return &core_api.SignUpResp{
-		Id:           userId,
-		AccessToken:  authorization,
-		AccessExpire: int64(signUpResponse["accessExpire"].(float64)),
-	}, nil
+	return issueSession(ctx, u.SessionMapper, userId, nil)
 }
 
-func (u *UserService) SignIn(ctx context.Context, req *core_api.SignInReq) (*core_api.SignInResp, error) {
+func (u *UserService) SignIn(ctx context.Context, req *core_api.SignInReq) (*core_api.AuthTokens, error) {
 	if adaptor.IsDevModeRequest(ctx) {
-		if err := u.ensureLocalUser(ctx, consts.DevMockUserID, "13800000000", "开发测试用户"); err != nil {
+		if _, err := u.ensureLocalUser(ctx, consts.DevMockUserID, "13800000000", "开发测试用户"); err != nil {
 			return nil, consts.ErrSignIn
 		}
-		return &core_api.SignInResp{
+		return &core_api.AuthTokens{
 			Id:           consts.DevMockUserID,
 			AccessToken:  consts.DevMockAccessToken,
 			AccessExpire: time.Now().Add(24 * time.Hour).Unix(),
@@ -177,22 +211,32 @@ func (u *UserService) SignIn(ctx context.Context, req *core_api.SignInReq) (*cor
 		return nil, consts.ErrSignIn
 	}
 
-	httpClient := util.NewHttpClient()
-	signInResponse, err := httpClient.SignIn(req.AuthType, strings.TrimSpace(req.AuthId), req.VerifyCode, req.Password)
+	authId := strings.TrimSpace(req.AuthId)
+	existing, err := u.checkSignIn(ctx, authId)
 	if err != nil {
-		return nil, consts.ErrSignIn
+		return nil, err
+	}
+	signInResp, err := u.PlatformAuth.SignIn(ctx, &platform_auth.SignInReq{
+		AuthType:   req.AuthType,
+		AuthId:     authId,
+		VerifyCode: req.VerifyCode,
+		Password:   req.Password,
+	})
+	if err != nil {
+		// 中台可用时返回的错误均为凭证被拒绝，计入登录失败
+		if err != consts.ErrPlatformUnavailable {
+			u.recordSignInFailure(ctx, existing)
+		}
+		return nil, err
 	}
+	u.clearSignInGuard(ctx, existing)
 
-	userId := signInResponse["userId"].(string)
-	if err := u.ensureLocalUser(ctx, userId, req.AuthId, ""); err != nil {
+	userId, err := u.ensureLocalUser(ctx, signInResp.UserId, req.AuthId, "")
+	if err != nil {
 		return nil, consts.ErrSignIn
 	}
 
-	return &core_api.SignInResp{
-		Id:           userId,
-		AccessToken:  signInResponse["accessToken"].(string),
-		AccessExpire: int64(signInResponse["accessExpire"].(float64)),
-	}, nil
+	return issueSession(ctx, u.SessionMapper, userId, nil)
 }
 
 func (u *UserService) UpdateUserInfo(ctx context.Context, req *core_api.UpdateUserInfoReq) (resp *core_api.Response, err error) {
@@ -201,9 +245,9 @@ func (u *UserService) UpdateUserInfo(ctx context.Context, req *core_api.UpdateUs
 		return nil, err
 	}
 
-	if req.Phone != nil {
-		aUser.Phone = *req.Phone
-		req.GetPhone()
+	// 手机号用于登录与账号关联，只能通过 ChangePhone 验证后修改
+	if req.Phone != nil && strings.TrimSpace(*req.Phone) != aUser.Phone {
+		return nil, consts.ErrPhoneUnverified
 	}
 	if req.Avatar != nil {
 		aUser.Avatar = *req.Avatar
@@ -222,9 +266,10 @@ func (u *UserService) UpdateUserInfo(ctx context.Context, req *core_api.UpdateUs
 	}
 	if req.Hometown != nil {
 		aUser.Hometown = *req.Hometown
+		aUser.HometownCode = u.Regions.Resolve(*req.Hometown)
 	}
 
-	err = u.UserMapper.Update(ctx, aUser)
+	err = u.UserMapper.UpdateProfile(ctx, aUser)
 	if err != nil {
 		return nil, consts.ErrUpdate
 	}
@@ -247,6 +292,9 @@ func (u *UserService) UpdateEducation(ctx context.Context, req *core_api.UpdateE
 		if err2 != nil {
 			return nil, consts.ErrCopier
 		}
+		if e.SchoolId, err = resolveDictionaryId(ctx, u.DictionaryMapper, dictionary.KindSchool, e.School); err != nil {
+			return nil, err
+		}
 		educations = append(educations, e)
 	}
 
@@ -257,7 +305,7 @@ func (u *UserService) UpdateEducation(ctx context.Context, req *core_api.UpdateE
 		aUser.ShanghaiEducations = educations
 	}
 
-	err = u.UserMapper.Update(ctx, aUser)
+	err = u.UserMapper.UpdateEducations(ctx, aUser.ID, aUser.HomeEducations, aUser.ShanghaiEducations)
 	if err != nil {
 		return nil, consts.ErrUpdate
 	}
@@ -281,11 +329,13 @@ func (u *UserService) UpdateEmployment(ctx context.Context, req *core_api.Update
 		if err2 != nil {
 			return nil, consts.ErrCopier
 		}
+		if e.IndustryId, err = resolveDictionaryId(ctx, u.DictionaryMapper, dictionary.KindIndustry, e.Industry); err != nil {
+			return nil, err
+		}
 		employments = append(employments, e)
 	}
 
-	aUser.Employments = employments
-	err = u.UserMapper.Update(ctx, aUser)
+	err = u.UserMapper.UpdateEmployments(ctx, aUser.ID, employments)
 	if err != nil {
 		return nil, consts.ErrUpdate
 	}
@@ -296,12 +346,64 @@ func (u *UserService) UpdateEmployment(ctx context.Context, req *core_api.Update
 	}, nil
 }
 
-func (u *UserService) GetUserInfo(ctx context.Context, req *core_api.GetUserInfoReq) (resp *core_api.GetUserInfoResp, err error) {
+func (u *UserService) GetUserInfo(ctx context.Context, req *core_api.GetUserInfoReq) (resp *core_api.UserInfo, err error) {
 	aUser, err := u.findAuthenticatedUser(ctx)
 	if err != nil {
 		return nil, err
 	}
+	info, err := mapUserInfo(aUser)
+	if err != nil {
+		return nil, err
+	}
+	return &core_api.UserInfo{
+		GetUserInfoResp: info,
+		HometownCode:    aUser.HometownCode,
+		Completeness:    profileCompleteness(aUser),
+	}, nil
+}
 
+// GetUserProfile 查看其他校友的资料，字段按对方的隐私设置裁剪
+func (u *UserService) GetUserProfile(ctx context.Context, req *core_api.GetUserProfileReq) (*core_api.UserProfile, error) {
+	target, err := u.UserMapper.FindOne(ctx, req.Id)
+	if err != nil {
+		return nil, consts.ErrNotFound
+	}
+	if target.Status != 0 || !target.DeleteTime.IsZero() {
+		return nil, consts.ErrNotFound
+	}
+	v := loadViewer(ctx, u.UserMapper)
+	masked := v.redact(target)
+	info, err := mapUserInfo(masked)
+	if err != nil {
+		return nil, err
+	}
+	info.Birthday = timeToUnix(masked.Birthday)
+	return &core_api.UserProfile{
+		Id:              target.ID.Hex(),
+		GetUserInfoResp: info,
+		HometownCode:    masked.HometownCode,
+		Shared:          sharedContexts(v.self, masked),
+	}, nil
+}
+
+func (u *UserService) ExchangeWxPhone(ctx context.Context, code string) (*core_api.ExchangeWxPhoneResp, error) {
+	_, err := u.findAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	wxClient := util.NewWxClient()
+	phoneNumber, err := wxClient.GetPhoneNumber(code)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", consts.ErrWxPhoneExchange, err)
+	}
+
+	return &core_api.ExchangeWxPhoneResp{
+		PhoneNumber: phoneNumber,
+	}, nil
+}
+
+func mapUserInfo(aUser *user.User) (resp *core_api.GetUserInfoResp, err error) {
 	homeEducations := make([]*core_api.Education, 0)
 	for _, edu := range aUser.HomeEducations {
 		var e core_api.Education
@@ -346,20 +448,3 @@ func (u *UserService) GetUserInfo(ctx context.Context, req *core_api.GetUserInfo
 	}
 	return resp, nil
 }
-
-func (u *UserService) ExchangeWxPhone(ctx context.Context, code string) (*core_api.ExchangeWxPhoneResp, error) {
-	_, err := u.findAuthenticatedUser(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	wxClient := util.NewWxClient()
-	phoneNumber, err := wxClient.GetPhoneNumber(code)
-	if err != nil {
-		return nil, fmt.Errorf("%w: %v", consts.ErrWxPhoneExchange, err)
-	}
-
-	return &core_api.ExchangeWxPhoneResp{
-		PhoneNumber: phoneNumber,
-	}, nil
-}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/audit"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/verification"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// maxVerificationProofs 单次申请最多上传的证明材料数量
+const maxVerificationProofs = 9
+
+type IVerificationService interface {
+	SubmitVerification(ctx context.Context, req *core_api.SubmitVerificationReq) (*core_api.Response, error)
+	GetVerifications(ctx context.Context, req *core_api.GetVerificationsReq) (*core_api.GetVerificationsResp, error)
+	ListAdminVerifications(ctx context.Context, page, pageSize int64, status string) (*PageResult[AdminVerification], error)
+	ApproveVerification(ctx context.Context, id string) error
+	RejectVerification(ctx context.Context, id, reason string) error
+}
+
+type VerificationService struct {
+	UserMapper         *user.MongoMapper
+	VerificationMapper *verification.MongoMapper
+	AuditMapper        *audit.MongoMapper
+}
+
+var VerificationServiceSet = wire.NewSet(
+	wire.Struct(new(VerificationService), "*"),
+	wire.Bind(new(IVerificationService), new(*VerificationService)),
+)
+
+type AdminVerification struct {
+	ID         string   `json:"id"`
+	UserID     string   `json:"userId"`
+	UserName   string   `json:"userName"`
+	UserPhone  string   `json:"userPhone"`
+	School     string   `json:"school"`
+	Year       int64    `json:"year"`
+	Proofs     []string `json:"proofs"`
+	Status     string   `json:"status"`
+	Reason     string   `json:"reason"`
+	ReviewerID string   `json:"reviewerId"`
+	ReviewTime *int64   `json:"reviewTime"`
+	CreateTime int64    `json:"createTime"`
+}
+
+func (s *VerificationService) SubmitVerification(ctx context.Context, req *core_api.SubmitVerificationReq) (*core_api.Response, error) {
+	userMeta := adaptor.ExtractUserMeta(ctx)
+	if userMeta.GetUserId() == "" {
+		return nil, consts.ErrNotAuthentication
+	}
+	aUser, err := s.UserMapper.FindOne(ctx, userMeta.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if aUser.Status != 0 || !aUser.DeleteTime.IsZero() {
+		return nil, consts.ErrForbidden
+	}
+	if aUser.Role == "alumni" || aUser.Role == "admin" {
+		return nil, consts.ErrVerified
+	}
+	if _, err = s.VerificationMapper.FindPendingByUser(ctx, aUser.ID.Hex()); err == nil {
+		return nil, consts.ErrVerificationPending
+	}
+
+	school := strings.TrimSpace(req.School)
+	if school == "" || req.Year < 1900 || req.Year > int64(time.Now().Year()) {
+		return nil, consts.ErrVerificationInvalid
+	}
+	proofs := make([]string, 0, len(req.Proofs))
+	for _, proof := range req.Proofs {
+		if proof = strings.TrimSpace(proof); proof != "" {
+			proofs = append(proofs, proof)
+		}
+	}
+	if len(proofs) > maxVerificationProofs {
+		return nil, consts.ErrVerificationInvalid
+	}
+
+	if err = s.VerificationMapper.Insert(ctx, &verification.Verification{
+		UserId: aUser.ID.Hex(),
+		School: school,
+		Year:   req.Year,
+		Proofs: proofs,
+		Status: verification.StatusPending,
+	}); err != nil {
+		return nil, consts.ErrCreate
+	}
+	return &core_api.Response{
+		Code: 0,
+		Msg:  "提交成功，请等待审核",
+	}, nil
+}
+
+func (s *VerificationService) GetVerifications(ctx context.Context, _ *core_api.GetVerificationsReq) (*core_api.GetVerificationsResp, error) {
+	userMeta := adaptor.ExtractUserMeta(ctx)
+	if userMeta.GetUserId() == "" {
+		return nil, consts.ErrNotAuthentication
+	}
+	aUser, err := s.UserMapper.FindOne(ctx, userMeta.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	data, _, err := s.VerificationMapper.FindManyByFilter(ctx, bson.M{consts.UserID: aUser.ID.Hex()}, 0, 20)
+	if err != nil {
+		return nil, err
+	}
+	verifications := make([]*core_api.Verification, 0, len(data))
+	for _, item := range data {
+		verifications = append(verifications, mapVerification(item))
+	}
+	return &core_api.GetVerificationsResp{Role: aUser.Role, Verifications: verifications}, nil
+}
+
+func (s *VerificationService) ListAdminVerifications(ctx context.Context, page, pageSize int64, status string) (*PageResult[AdminVerification], error) {
+	page, pageSize = normalizePage(page, pageSize)
+	filter := bson.M{}
+	if status = strings.TrimSpace(status); status != "" {
+		filter[consts.Status] = status
+	}
+	data, total, err := s.VerificationMapper.FindManyByFilter(ctx, filter, offset(page, pageSize), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]AdminVerification, 0, len(data))
+	for _, item := range data {
+		result := AdminVerification{
+			ID:         item.ID.Hex(),
+			UserID:     item.UserId,
+			School:     item.School,
+			Year:       item.Year,
+			Proofs:     item.Proofs,
+			Status:     item.Status,
+			Reason:     item.Reason,
+			ReviewerID: item.ReviewerId,
+			ReviewTime: nullableTimeToUnix(item.ReviewTime),
+			CreateTime: timeToUnix(item.CreateTime),
+		}
+		if u, err := s.UserMapper.FindOne(ctx, item.UserId); err == nil {
+			result.UserName = u.Name
+			result.UserPhone = u.Phone
+		}
+		items = append(items, result)
+	}
+	return &PageResult[AdminVerification]{Items: items, Total: total, Page: page, PageSize: pageSize}, nil
+}
+
+// ApproveVerification 审核通过后将申请人角色改为 alumni，管理员角色不受影响
+func (s *VerificationService) ApproveVerification(ctx context.Context, id string) error {
+	item, err := s.review(ctx, id, verification.StatusApproved, "")
+	if err != nil {
+		return err
+	}
+	aUser, err := s.UserMapper.FindOne(ctx, item.UserId)
+	if err != nil {
+		return err
+	}
+	if aUser.Role == "admin" || aUser.Role == "alumni" {
+		return nil
+	}
+	before := snapshot(aUser)
+	aUser.Role = "alumni"
+	if err = s.UserMapper.UpdateRole(ctx, aUser.ID, aUser.Role); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.AuditMapper, "user.set_role", AuditUser, aUser.ID.Hex(), before, snapshot(aUser))
+	return nil
+}
+
+func (s *VerificationService) RejectVerification(ctx context.Context, id, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return ErrAdminBadRequest
+	}
+	_, err := s.review(ctx, id, verification.StatusRejected, reason)
+	return err
+}
+
+// review 记录审核结果及审核人，只有待审核的申请可以审核
+func (s *VerificationService) review(ctx context.Context, id, to, reason string) (*verification.Verification, error) {
+	item, err := s.VerificationMapper.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	err = reviewRecord(ctx, s.AuditMapper, AuditVerification, item.ID.Hex(), to, item, func(reviewerID string) (bool, error) {
+		return s.VerificationMapper.Review(ctx, item.ID, to, reason, reviewerID)
+	}, func(reviewerID string) {
+		item.Status, item.Reason, item.ReviewerId, item.ReviewTime = to, reason, reviewerID, time.Now()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func mapVerification(item *verification.Verification) *core_api.Verification {
+	return &core_api.Verification{
+		Id:         item.ID.Hex(),
+		School:     item.School,
+		Year:       item.Year,
+		Proofs:     item.Proofs,
+		Status:     item.Status,
+		Reason:     item.Reason,
+		ReviewTime: timeToUnix(item.ReviewTime),
+		CreateTime: timeToUnix(item.CreateTime),
+	}
+}
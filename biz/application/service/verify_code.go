@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/captcha"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/limiter"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util/log"
+)
+
+// verifyCodeSentMsg 无论手机号是否注册都返回相同的提示，避免通过发送验证码探测手机号是否注册
+const verifyCodeSentMsg = "验证码已发送，如未收到请确认手机号是否正确"
+
+// checkVerifyCodeQuota 发送验证码前按 IP 与手机号限流，同一 IP 发送较多时要求人机验证；被后续规则拒绝的请求同样计入 IP 次数
+func checkVerifyCodeQuota(ctx context.Context, l limiter.ILimiter, c captcha.ICaptcha, phone string) error {
+	conf := config.GetConfig().VerifyCode
+	if clientIP := adaptor.ExtractExtra(ctx).GetClientIP(); clientIP != "" {
+		ipWindow := time.Duration(conf.IPWindow) * time.Second
+		if err := allow(ctx, l, "verify_code:ip:"+clientIP, conf.IPLimit, ipWindow); err != nil {
+			return err
+		}
+		trusted, err := l.Allow(ctx, "verify_code:captcha:"+clientIP, conf.CaptchaThreshold, ipWindow)
+		if err != nil {
+			return err
+		}
+		if !trusted {
+			ticket := adaptor.ExtractCaptchaTicket(ctx)
+			if ticket == "" {
+				return consts.ErrCaptchaRequired
+			}
+			if ok, err := c.Verify(ctx, ticket, clientIP); err != nil || !ok {
+				log.CtxInfo(ctx, "[checkVerifyCodeQuota] captcha rejected, ip=%s, err=%v", clientIP, err)
+				return consts.ErrCaptchaRequired
+			}
+		}
+	}
+	if err := allow(ctx, l, "verify_code:phone_interval:"+phone, 1, time.Duration(conf.PhoneInterval)*time.Second); err != nil {
+		return err
+	}
+	return allow(ctx, l, "verify_code:phone:"+phone, conf.PhoneLimit, time.Duration(conf.PhoneWindow)*time.Second)
+}
+
+func allow(ctx context.Context, l limiter.ILimiter, key string, limit int64, window time.Duration) error {
+	allowed, err := l.Allow(ctx, key, limit, window)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		log.CtxInfo(ctx, "[RateLimit] rejected, key=%s", key)
+		return consts.ErrTooManyRequests
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/application/dto/alumni/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util"
+	"github.com/xh-polaris/service-idl-gen-go/kitex_gen/basic"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxMergeHops 沿 MergedInto 查找保留账号时的最大跳数，防止错误数据导致死循环
+const maxMergeHops = 3
+
+// WxLogin 通过 wx.login 的 code 登录，openid 未绑定任何账号时创建新账号并要求绑定手机号
+func (u *UserService) WxLogin(ctx context.Context, req *core_api.WxLoginReq) (*core_api.WxLoginResp, error) {
+	code := strings.TrimSpace(req.Code)
+	if code == "" {
+		return nil, consts.ErrWxLogin
+	}
+	session, err := util.NewWxClient().Code2Session(code)
+	if err != nil {
+		return nil, consts.ErrWxLogin
+	}
+
+	aUser, err := u.UserMapper.FindOneByWx(ctx, session.OpenId, session.UnionId)
+	switch {
+	case err == nil:
+		if aUser, err = u.resolveMerged(ctx, aUser); err != nil {
+			return nil, err
+		}
+		if aUser.WxOpenId != session.OpenId || (session.UnionId != "" && aUser.WxUnionId != session.UnionId) {
+			aUser.WxOpenId = session.OpenId
+			if session.UnionId != "" {
+				aUser.WxUnionId = session.UnionId
+			}
+			if err = u.UserMapper.UpdateWx(ctx, aUser.ID, aUser.WxOpenId, aUser.WxUnionId); err != nil {
+				return nil, consts.ErrWxLogin
+			}
+		}
+	case err == consts.ErrNotFound:
+		now := time.Now()
+		aUser = &user.User{
+			ID:         primitive.NewObjectID(),
+			Role:       "user",
+			WxOpenId:   session.OpenId,
+			WxUnionId:  session.UnionId,
+			CreateTime: now,
+			UpdateTime: now,
+		}
+		if err = u.UserMapper.Insert(ctx, aUser); err != nil {
+			return nil, consts.ErrWxLogin
+		}
+	default:
+		return nil, err
+	}
+	return u.wxLoginResp(ctx, aUser)
+}
+
+// WxBindPhone 绑定微信授权的手机号；手机号已属于其他账号时，将微信身份转移到该账号并返回该账号的 token
+func (u *UserService) WxBindPhone(ctx context.Context, req *core_api.WxBindPhoneReq) (*core_api.WxLoginResp, error) {
+	aUser, err := u.findAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	phone, err := util.NewWxClient().GetPhoneNumber(req.Code)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", consts.ErrWxPhoneExchange, err)
+	}
+	if phone == aUser.Phone {
+		return u.wxLoginResp(ctx, aUser)
+	}
+
+	owner, err := u.UserMapper.FindLiveByPhone(ctx, phone, aUser.ID)
+	if errors.Is(err, consts.ErrNotFound) {
+		if err = u.UserMapper.UpdatePhone(ctx, aUser.ID, phone, ""); err != nil {
+			return nil, consts.ErrUpdate
+		}
+		aUser.Phone = phone
+		return u.wxLoginResp(ctx, aUser)
+	} else if err != nil {
+		return nil, err
+	}
+
+	// 只有尚未绑定手机号的微信账号可以并入已有账号，且已有账号不能绑定了其他微信
+	if aUser.Phone != "" || aUser.WxOpenId == "" || (owner.WxOpenId != "" && owner.WxOpenId != aUser.WxOpenId) {
+		return nil, consts.ErrPhoneTaken
+	}
+	if err = u.UserMapper.UpdateWx(ctx, owner.ID, aUser.WxOpenId, aUser.WxUnionId); err != nil {
+		return nil, consts.ErrUpdate
+	}
+	owner.WxOpenId, owner.WxUnionId = aUser.WxOpenId, aUser.WxUnionId
+	if _, err = u.Records.Move(ctx, aUser.ID.Hex(), owner.ID.Hex()); err != nil {
+		return nil, err
+	}
+	if err = u.UserMapper.UpdateWx(ctx, aUser.ID, "", ""); err != nil {
+		return nil, consts.ErrUpdate
+	}
+	if err = u.UserMapper.MarkMerged(ctx, aUser.ID, owner.ID.Hex()); err != nil {
+		return nil, consts.ErrUpdate
+	}
+	if _, err = u.SessionMapper.RevokeByUser(ctx, aUser.ID.Hex()); err != nil {
+		return nil, err
+	}
+	return u.wxLoginResp(ctx, owner)
+}
+
+// resolveMerged 账号已被合并时返回保留的账号，已注销或停用的账号不允许登录
+func (u *UserService) resolveMerged(ctx context.Context, aUser *user.User) (*user.User, error) {
+	aUser, err := u.followMerged(ctx, aUser)
+	if err != nil {
+		return nil, err
+	}
+	if aUser.Deletion.Purged {
+		return nil, consts.ErrAccountDeleted
+	}
+	if !isLiveUser(aUser) {
+		return nil, consts.ErrForbidden
+	}
+	return aUser, nil
+}
+
+// followMerged 沿 MergedInto 找到保留的账号，超过 maxMergeHops 仍未找到时视为数据异常
+func (u *UserService) followMerged(ctx context.Context, aUser *user.User) (*user.User, error) {
+	var err error
+	for i := 0; i < maxMergeHops && aUser.MergedInto != ""; i++ {
+		if aUser, err = u.UserMapper.FindOne(ctx, aUser.MergedInto); err != nil {
+			return nil, err
+		}
+	}
+	if aUser.MergedInto != "" {
+		return nil, consts.ErrForbidden
+	}
+	return aUser, nil
+}
+
+func (u *UserService) wxLoginResp(ctx context.Context, aUser *user.User) (*core_api.WxLoginResp, error) {
+	tokens, err := issueSession(ctx, u.SessionMapper, aUser.ID.Hex(), wechatMeta(aUser))
+	if err != nil {
+		return nil, err
+	}
+	return &core_api.WxLoginResp{
+		AuthTokens:    tokens,
+		NeedBindPhone: aUser.Phone == "",
+	}, nil
+}
+
+func wechatMeta(aUser *user.User) *basic.WechatUserMeta {
+	if aUser.WxOpenId == "" {
+		return nil
+	}
+	return &basic.WechatUserMeta{
+		AppId:   config.GetConfig().Wx.AppId,
+		OpenId:  aUser.WxOpenId,
+		UnionId: aUser.WxUnionId,
+	}
+}
@@ -0,0 +1,24 @@
+package captcha
+
+import (
+	"context"
+
+	"github.com/google/wire"
+)
+
+// ICaptcha 人机验证，ticket 由前端完成验证后通过 X-Captcha-Ticket 请求头传入，接入验证服务时替换 CaptchaSet 中的实现即可
+type ICaptcha interface {
+	Verify(ctx context.Context, ticket, clientIP string) (bool, error)
+}
+
+// Reject 未接入人机验证服务时的默认实现，需要人机验证的请求一律拒绝
+type Reject struct{}
+
+var CaptchaSet = wire.NewSet(
+	wire.Struct(new(Reject)),
+	wire.Bind(new(ICaptcha), new(*Reject)),
+)
+
+func (r *Reject) Verify(_ context.Context, _, _ string) (bool, error) {
+	return false, nil
+}
@@ -11,10 +11,25 @@ import (
 
 var config *Config
 
+// StateLocal 本地运行模式，使用内置的模拟中台，无需连接真实中台
+const StateLocal = "local"
+
+// Auth 令牌配置：SecretKey/PublicKey 为当前密钥对，KeyId 非空时本服务签发的令牌携带该 kid；
+// 轮换期间仍需接受的旧公钥放在 Keys 或 JwksFile 中，JwksFile 变更后按 JwksReload 秒的间隔自动重新加载
 type Auth struct {
-	SecretKey    string
-	PublicKey    string
-	AccessExpire int64
+	SecretKey     string
+	PublicKey     string
+	KeyId         string    `json:",optional"`
+	Keys          []AuthKey `json:",optional"`
+	JwksFile      string    `json:",optional"`
+	JwksReload    int64     `json:",default=60"`
+	AccessExpire  int64
+	RefreshExpire int64 `json:",default=2592000"` // 刷新令牌有效期，单位秒
+}
+
+type AuthKey struct {
+	Kid       string
+	PublicKey string
 }
 
 type Wx struct {
@@ -22,18 +37,82 @@ type Wx struct {
 	AppSecret string
 }
 
+// Pay 支付配置，Provider 为 fake 时使用本地模拟支付，仅允许在 dev、test、local 环境中配置，否则启动失败
+type Pay struct {
+	Provider          string `json:",default=wechat,options=wechat|fake"`
+	MchId             string `json:",optional"`
+	MchSerialNo       string `json:",optional"`
+	PrivateKey        string `json:",optional"`
+	ApiV3Key          string `json:",optional"`
+	PlatformPublicKey string `json:",optional"`
+	NotifyUrl         string `json:",optional"`
+	ExpireMinutes     int64  `json:",default=15"`
+}
+
+// Account 账号配置，DeletionGraceDays 为申请注销后的冷静期天数
+type Account struct {
+	DeletionGraceDays int64 `json:",default=15"`
+}
+
+// Platform 中台配置，State 为 local 时启动内置的模拟中台并覆盖 BaseUrl
+type Platform struct {
+	BaseUrl string `json:",optional"`
+	Timeout int64  `json:",default=5000"` // 请求超时，单位毫秒
+}
+
+// VerifyCode 验证码发送限制，间隔与窗口单位为秒；同一 IP 在 IPWindow 内发送超过 CaptchaThreshold 次后需通过人机验证
+type VerifyCode struct {
+	PhoneInterval    int64 `json:",default=60"`
+	PhoneLimit       int64 `json:",default=5"`
+	PhoneWindow      int64 `json:",default=3600"`
+	IPLimit          int64 `json:",default=30"`
+	IPWindow         int64 `json:",default=3600"`
+	CaptchaThreshold int64 `json:",default=10"`
+}
+
+// SignInGuard 登录失败限制，时间单位为秒：同一账号在 FailureWindow 内连续失败 FailureLimit 次后锁定，
+// 锁定时长从 LockSeconds 起每次翻倍，最长 MaxLockSeconds；同一 IP 在 IPWindow 内失败 IPFailureLimit 次后暂停登录
+type SignInGuard struct {
+	FailureLimit   int64 `json:",default=5"`
+	FailureWindow  int64 `json:",default=900"`
+	LockSeconds    int64 `json:",default=300"`
+	MaxLockSeconds int64 `json:",default=86400"`
+	IPFailureLimit int64 `json:",default=50"`
+	IPWindow       int64 `json:",default=3600"`
+}
+
+// Region 行政区划配置，DataFile 为 GB/T 2260 格式（代码到名称）的 JSON 文件，与内置数据合并，可用于补充区县级数据
+type Region struct {
+	DataFile string `json:",optional"`
+}
+
+// Dev 本地联调配置，MockAuth 开启后接受 X-Alumni-Mode: dev 请求头与 mock token，Seed 开启后启动时写入演示管理员
+type Dev struct {
+	MockAuth bool `json:",optional"`
+	Seed     bool `json:",optional"`
+}
 
 type Config struct {
 	service.ServiceConf
-	ListenOn string
-	State    string
-	Wx       Wx
-	Auth     Auth
-	Mongo    struct {
+	ListenOn    string
+	State       string
+	Wx          Wx
+	Auth        Auth
+	Pay         Pay         `json:",optional"`
+	Account     Account     `json:",optional"`
+	Dev         Dev         `json:",optional"`
+	Platform    Platform    `json:",optional"`
+	VerifyCode  VerifyCode  `json:",optional"`
+	SignInGuard SignInGuard `json:",optional"`
+	Region      Region      `json:",optional"`
+	Mongo       struct {
 		URL string
 		DB  string
 	}
 	Cache cache.CacheConf
+
+	// TrustedProxies 反向代理的 CIDR，只采信来自这些地址的 X-Forwarded-For、X-Real-IP；为空时以连接的远端地址作为客户端 IP
+	TrustedProxies []string `json:",optional"`
 }
 
 func NewConfig() (*Config, error) {
@@ -54,6 +133,26 @@ func NewConfig() (*Config, error) {
 	return c, nil
 }
 
+// DevState State 为 dev、test 或 local，mock 鉴权、模拟支付等调试能力只在这些环境中可用
+func (c *Config) DevState() bool {
+	return c.State == "dev" || c.State == "test" || c.State == StateLocal
+}
+
+// MockAuthEnabled 仅在 State 为 dev、test 或 local 且显式开启 Dev.MockAuth 时启用 mock 鉴权，生产环境始终关闭
+func (c *Config) MockAuthEnabled() bool {
+	return c.Dev.MockAuth && c.DevState()
+}
+
+// FakePayEnabled 仅在 State 为 dev、test 或 local 且 Pay.Provider 为 fake 时使用模拟支付
+func (c *Config) FakePayEnabled() bool {
+	return c.Pay.Provider == "fake" && c.DevState()
+}
+
+// SeedEnabled 仅在 State 为 dev、test 或 local 且显式开启 Dev.Seed 时写入演示数据，生产环境始终跳过
+func (c *Config) SeedEnabled() bool {
+	return c.Dev.Seed && c.DevState()
+}
+
 func GetConfig() *Config {
 	return config
 }
@@ -7,30 +7,40 @@ var PageSize int64 = 10
 
 // 数据库相关
 const (
-	ID                        = "_id"
-	UserID                    = "user_id"
-	Status                    = "status"
-	PlatformSendVerifyCodeUrl = "https://api.xhpolaris.com/platform/auth/send_verify_code"
-	CreateTime                = "create_time"
-	UpdateTime                = "update_time"
-	DeleteTime                = "delete_time"
-	ActivityId                = "activity_id"
-	CheckIn                   = "check_in"
-	Phone                     = "phone"
-	Name                      = "name"
-	DeleteStatus              = 1
-	EffectStatus              = 0
+	ID              = "_id"
+	UserID          = "user_id"
+	Status          = "status"
+	CreateTime      = "create_time"
+	UpdateTime      = "update_time"
+	DeleteTime      = "delete_time"
+	ActivityId      = "activity_id"
+	CheckIn         = "check_in"
+	Phone           = "phone"
+	Name            = "name"
+	OrderId         = "order_id"
+	PayStatus       = "pay_status"
+	ExpireTime      = "expire_time"
+	Limit           = "limit"
+	Occupied        = "occupied"
+	GroupId         = "group_id"
+	RefId           = "ref_id"
+	Category        = "category"
+	OrganizationIds = "organization_ids"
+	DeleteStatus    = 1
+	EffectStatus    = 0
 )
 
 // http
 const (
-	Post                   = "POST"
-	PlatformSignInUrl      = "https://api.xhpolaris.com/platform/auth/sign_in"
-	PlatformSetPasswordUrl = "https://api.xhpolaris.com/platform/auth/set_password"
-	ContentTypeJson        = "application/json"
-	CharSetUTF8            = "UTF-8"
-	Beta                   = "beta"
-	OpenApiCallUrl         = "https://api.xhpolaris.com/openapi/call/"
+	Post                       = "POST"
+	PlatformBaseUrl            = "https://api.xhpolaris.com"
+	PlatformSignInPath         = "/platform/auth/sign_in"
+	PlatformSetPasswordPath    = "/platform/auth/set_password"
+	PlatformSendVerifyCodePath = "/platform/auth/send_verify_code"
+	ContentTypeJson            = "application/json"
+	CharSetUTF8                = "UTF-8"
+	Beta                       = "beta"
+	OpenApiCallUrl             = "https://api.xhpolaris.com/openapi/call/"
 )
 
 // 默认值
@@ -47,4 +57,7 @@ const (
 	DevModeValue       = "dev"
 )
 
+// CaptchaTicketHeader 前端完成人机验证后携带 ticket 的请求头
+const CaptchaTicketHeader = "X-Captcha-Ticket"
+
 var ErrWxPhoneExchange = errors.New("微信手机号换取失败")
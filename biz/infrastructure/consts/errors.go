@@ -31,14 +31,82 @@ func NewErrno(code codes.Code, err error) *Errno {
 
 // 定义常量错误
 var (
-	ErrNotAuthentication = NewErrno(codes.Code(1000), errors.New("not authentication"))
-	ErrForbidden         = NewErrno(codes.PermissionDenied, errors.New("forbidden"))
-	ErrSignUp            = NewErrno(codes.Code(1001), errors.New("注册失败，请重试"))
-	ErrSignIn            = NewErrno(codes.Code(1002), errors.New("登录失败，请重试"))
-	ErrCheckIn           = NewErrno(codes.Code(1004), errors.New("签到失败，请重试"))
-	ErrRepeatedSignUp    = NewErrno(codes.Code(1005), errors.New("该手机号已注册"))
-	ErrNotSignUp         = NewErrno(codes.Code(1006), errors.New("请确认手机号已注册"))
-	ErrSend              = NewErrno(codes.Code(1007), errors.New("发送验证码失败，请重试"))
+	ErrNotAuthentication   = NewErrno(codes.Code(1000), errors.New("not authentication"))
+	ErrForbidden           = NewErrno(codes.PermissionDenied, errors.New("forbidden"))
+	ErrSignUp              = NewErrno(codes.Code(1001), errors.New("注册失败，请重试"))
+	ErrSignIn              = NewErrno(codes.Code(1002), errors.New("登录失败，请重试"))
+	ErrCheckIn             = NewErrno(codes.Code(1004), errors.New("签到失败，请重试"))
+	ErrRepeatedSignUp      = NewErrno(codes.Code(1005), errors.New("该手机号已注册"))
+	ErrNotSignUp           = NewErrno(codes.Code(1006), errors.New("请确认手机号已注册"))
+	ErrSend                = NewErrno(codes.Code(1007), errors.New("发送验证码失败，请重试"))
+	ErrAccountDeleted      = NewErrno(codes.Code(1008), errors.New("账号已注销"))
+	ErrPhoneTaken          = NewErrno(codes.Code(1009), errors.New("该手机号已绑定其他账号"))
+	ErrVerifyCode          = NewErrno(codes.Code(1010), errors.New("验证码错误或已过期"))
+	ErrPhoneInvalid        = NewErrno(codes.Code(1011), errors.New("请填写正确的手机号"))
+	ErrPhoneUnverified     = NewErrno(codes.Code(1012), errors.New("修改手机号需先验证新号码"))
+	ErrWxLogin             = NewErrno(codes.Code(1013), errors.New("微信登录失败，请重试"))
+	ErrRefreshToken        = NewErrno(codes.Code(1014), errors.New("登录已过期，请重新登录"))
+	ErrPlatformUnavailable = NewErrno(codes.Code(1015), errors.New("账号服务暂不可用，请稍后重试"))
+	ErrTooManyRequests     = NewErrno(codes.Code(1016), errors.New("操作过于频繁，请稍后再试"))
+	ErrCaptchaRequired     = NewErrno(codes.Code(1017), errors.New("请先完成人机验证"))
+	ErrSignInLocked        = NewErrno(codes.Code(1018), errors.New("登录失败次数过多，账号已临时锁定，请稍后再试"))
+)
+
+// 活动报名与支付相关错误
+var (
+	ErrActivityFull   = NewErrno(codes.Code(1101), errors.New("活动名额已满"))
+	ErrTicketNotFound = NewErrno(codes.Code(1102), errors.New("票种不存在"))
+	ErrPaidActivity   = NewErrno(codes.Code(1103), errors.New("该活动需购票报名"))
+	ErrEmptyRegister  = NewErrno(codes.Code(1104), errors.New("报名人员不能为空"))
+	ErrPay            = NewErrno(codes.Code(1105), errors.New("发起支付失败，请重试"))
+	ErrOrderStatus    = NewErrno(codes.Code(1106), errors.New("订单状态不允许该操作"))
+)
+
+// 校友认证相关错误
+var (
+	ErrVerified            = NewErrno(codes.Code(1201), errors.New("已完成校友认证"))
+	ErrVerificationPending = NewErrno(codes.Code(1202), errors.New("认证申请正在审核中"))
+	ErrVerificationInvalid = NewErrno(codes.Code(1203), errors.New("请填写正确的学校和毕业年份"))
+)
+
+// 校友导师相关错误
+var (
+	ErrMentorUnavailable = NewErrno(codes.Code(1301), errors.New("该校友暂未开放导师申请"))
+	ErrMentorFull        = NewErrno(codes.Code(1302), errors.New("导师名额已满"))
+	ErrMentorshipExists  = NewErrno(codes.Code(1303), errors.New("已向该导师提交过申请"))
+	ErrMentorshipStatus  = NewErrno(codes.Code(1304), errors.New("申请状态不允许该操作"))
+	ErrMentorNotVerified = NewErrno(codes.Code(1305), errors.New("完成校友认证后才能成为导师"))
+)
+
+// 校友招聘相关错误
+var (
+	ErrJobNotVerified = NewErrno(codes.Code(1401), errors.New("完成校友认证后才能发布招聘"))
+	ErrJobInvalid     = NewErrno(codes.Code(1402), errors.New("请完整填写招聘信息"))
+	ErrJobUnavailable = NewErrno(codes.Code(1403), errors.New("该职位已下线或已过期"))
+	ErrJobApplied     = NewErrno(codes.Code(1404), errors.New("已投递过该职位"))
+)
+
+// 学校与行业字典相关错误
+var (
+	ErrDictionaryKind = NewErrno(codes.Code(1501), errors.New("不支持的字典类型"))
+)
+
+// 行政区划相关错误
+var (
+	ErrRegionInvalid     = NewErrno(codes.Code(1601), errors.New("请选择正确的行政区划"))
+	ErrRegionNotVerified = NewErrno(codes.Code(1602), errors.New("完成校友认证后才能查看同乡统计"))
+)
+
+// 群组相关错误
+var (
+	ErrGroupNotVerified  = NewErrno(codes.Code(1701), errors.New("完成校友认证后才能创建群组"))
+	ErrGroupInvalid      = NewErrno(codes.Code(1702), errors.New("请完整填写群组信息"))
+	ErrGroupNotAdmin     = NewErrno(codes.Code(1703), errors.New("只有群组管理员可以进行该操作"))
+	ErrGroupInviteOnly   = NewErrno(codes.Code(1704), errors.New("该群组仅限受邀加入"))
+	ErrGroupJoined       = NewErrno(codes.Code(1705), errors.New("已加入或已申请加入该群组"))
+	ErrGroupLastAdmin    = NewErrno(codes.Code(1706), errors.New("群组至少需要保留一名管理员"))
+	ErrGroupNotMember    = NewErrno(codes.Code(1707), errors.New("加入群组后才能查看"))
+	ErrGroupMemberStatus = NewErrno(codes.Code(1708), errors.New("成员状态不允许该操作"))
 )
 
 // 数据库相关错误
@@ -0,0 +1,188 @@
+package jwks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util/log"
+)
+
+// Algorithm 唯一接受的签名算法，中台与本服务签发的令牌均为 ES256
+const Algorithm = "ES256"
+
+// defaultReloadInterval 未配置 Auth.JwksReload 时检查 JWKS 文件是否变更的间隔
+const defaultReloadInterval = time.Minute
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// KeySet 缓存已解析的验签公钥：未携带 kid 的令牌（中台签发）使用 Auth.PublicKey 校验，携带 kid 的令牌按 kid 查找
+// Auth.Keys 与 JWKS 文件中的公钥，JWKS 文件变更后自动重新加载，便于不停机轮换密钥
+type KeySet struct {
+	mu         sync.RWMutex
+	defaultKey *ecdsa.PublicKey
+	static     map[string]*ecdsa.PublicKey // 来自配置，不随文件重新加载
+	keys       map[string]*ecdsa.PublicKey // 配置与 JWKS 文件合并后的全部公钥
+	file       string
+	modTime    time.Time
+}
+
+func NewKeySet(cfg *config.Config) (*KeySet, error) {
+	k := &KeySet{static: map[string]*ecdsa.PublicKey{}, file: cfg.Auth.JwksFile}
+	if cfg.Auth.PublicKey != "" {
+		key, err := jwt.ParseECPublicKeyFromPEM([]byte(cfg.Auth.PublicKey))
+		if err != nil {
+			return nil, fmt.Errorf("解析 Auth.PublicKey 失败: %w", err)
+		}
+		k.defaultKey = key
+		if cfg.Auth.KeyId != "" {
+			k.static[cfg.Auth.KeyId] = key
+		}
+	}
+	for _, item := range cfg.Auth.Keys {
+		key, err := jwt.ParseECPublicKeyFromPEM([]byte(item.PublicKey))
+		if err != nil {
+			return nil, fmt.Errorf("解析 Auth.Keys[%s] 失败: %w", item.Kid, err)
+		}
+		k.static[item.Kid] = key
+	}
+	k.keys = k.static
+	if k.file != "" {
+		if _, err := k.reload(); err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+// Keyfunc 供 jwt.Parse 使用，只接受 ES256 签名，防止算法混淆
+func (k *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	if token.Method.Alg() != Algorithm {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if k.defaultKey == nil {
+			return nil, ErrUnknownKey
+		}
+		return k.defaultKey, nil
+	}
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: kid=%s", ErrUnknownKey, kid)
+	}
+	return key, nil
+}
+
+// Watch 按 interval 检查 JWKS 文件，修改时间变化时重新加载，加载失败时保留原有公钥，直到 ctx 结束
+func (k *KeySet) Watch(ctx context.Context, interval time.Duration) {
+	if k.file == "" {
+		return
+	}
+	if interval <= 0 {
+		interval = defaultReloadInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if reloaded, err := k.reload(); err != nil {
+			log.Error("[KeySet] reload %s failed, keep current keys, err=%v", k.file, err)
+		} else if reloaded {
+			log.Info("[KeySet] reloaded %s", k.file)
+		}
+	}
+}
+
+// reload 文件未变更时直接返回 false
+func (k *KeySet) reload() (bool, error) {
+	info, err := os.Stat(k.file)
+	if err != nil {
+		return false, err
+	}
+	k.mu.RLock()
+	unchanged := info.ModTime().Equal(k.modTime)
+	k.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+	data, err := os.ReadFile(k.file)
+	if err != nil {
+		return false, err
+	}
+	fileKeys, err := Parse(data)
+	if err != nil {
+		return false, err
+	}
+	keys := make(map[string]*ecdsa.PublicKey, len(k.static)+len(fileKeys))
+	for kid, key := range fileKeys {
+		keys[kid] = key
+	}
+	// 配置中的公钥优先于文件中同 kid 的公钥
+	for kid, key := range k.static {
+		keys[kid] = key
+	}
+	k.mu.Lock()
+	k.keys, k.modTime = keys, info.ModTime()
+	k.mu.Unlock()
+	return true, nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// Parse 解析 JWKS 文档，只接受 P-256 曲线的 EC 签名公钥，任一公钥不合法时整体失败
+func Parse(data []byte) (map[string]*ecdsa.PublicKey, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("解析 JWKS 失败: %w", err)
+	}
+	keys := make(map[string]*ecdsa.PublicKey, len(doc.Keys))
+	for _, item := range doc.Keys {
+		if item.Kid == "" {
+			return nil, errors.New("JWKS 中的公钥缺少 kid")
+		}
+		if item.Kty != "EC" || item.Crv != "P-256" || (item.Alg != "" && item.Alg != Algorithm) || (item.Use != "" && item.Use != "sig") {
+			return nil, fmt.Errorf("不支持的公钥: kid=%s, kty=%s, crv=%s, alg=%s", item.Kid, item.Kty, item.Crv, item.Alg)
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(item.X)
+		y, errY := base64.RawURLEncoding.DecodeString(item.Y)
+		if errX != nil || errY != nil {
+			return nil, fmt.Errorf("公钥坐标编码错误: kid=%s", item.Kid)
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("公钥不在 P-256 曲线上: kid=%s", item.Kid)
+		}
+		if _, ok := keys[item.Kid]; ok {
+			return nil, fmt.Errorf("JWKS 中存在重复的 kid: %s", item.Kid)
+		}
+		keys[item.Kid] = key
+	}
+	return keys, nil
+}
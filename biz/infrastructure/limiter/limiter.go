@@ -0,0 +1,73 @@
+package limiter
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util/log"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+)
+
+const keyPrefix = "ratelimit:"
+
+// ILimiter 滑动窗口限流，Allow 在 window 内已放行的次数小于 limit 时记录本次请求并放行，Count 返回 window 内已记录的次数
+type ILimiter interface {
+	Allow(ctx context.Context, key string, limit int64, window time.Duration) (bool, error)
+	Count(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+// Limiter 优先使用 config.Cache 中的 Redis，多实例共享计数；未配置 Redis 或 Redis 出错时退回单机内存计数
+type Limiter struct {
+	redis  *RedisLimiter
+	memory *MemoryLimiter
+}
+
+var LimiterSet = wire.NewSet(
+	NewLimiter,
+	wire.Bind(new(ILimiter), new(*Limiter)),
+)
+
+func NewLimiter(config *config.Config) *Limiter {
+	l := &Limiter{memory: NewMemoryLimiter()}
+	if len(config.Cache) == 0 {
+		log.Info("[Limiter] redis not configured, use in-memory limiter")
+		return l
+	}
+	rds, err := redis.NewRedis(config.Cache[0].RedisConf)
+	if err != nil {
+		log.Error("[Limiter] connect redis failed, use in-memory limiter, err=%v", err)
+		return l
+	}
+	l.redis = NewRedisLimiter(rds)
+	return l
+}
+
+func (l *Limiter) Allow(ctx context.Context, key string, limit int64, window time.Duration) (bool, error) {
+	if limit <= 0 || window <= 0 {
+		return true, nil
+	}
+	if l.redis != nil {
+		allowed, err := l.redis.Allow(ctx, key, limit, window)
+		if err == nil {
+			return allowed, nil
+		}
+		log.CtxError(ctx, "[Limiter] redis limiter failed, fallback to memory, key=%s, err=%v", key, err)
+	}
+	return l.memory.Allow(ctx, key, limit, window)
+}
+
+func (l *Limiter) Count(ctx context.Context, key string, window time.Duration) (int64, error) {
+	if window <= 0 {
+		return 0, nil
+	}
+	if l.redis != nil {
+		count, err := l.redis.Count(ctx, key, window)
+		if err == nil {
+			return count, nil
+		}
+		log.CtxError(ctx, "[Limiter] redis limiter failed, fallback to memory, key=%s, err=%v", key, err)
+	}
+	return l.memory.Count(ctx, key, window)
+}
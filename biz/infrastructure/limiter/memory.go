@@ -0,0 +1,71 @@
+package limiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval 清理过期计数的间隔，避免长期不再出现的 key 占用内存
+const sweepInterval = time.Minute
+
+// MemoryLimiter 单机内存滑动窗口，多实例部署时各实例分别计数
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	hits      map[string][]time.Time
+	windows   map[string]time.Duration
+	lastSweep time.Time
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		hits:      map[string][]time.Time{},
+		windows:   map[string]time.Duration{},
+		lastSweep: time.Now(),
+	}
+}
+
+func (l *MemoryLimiter) Allow(_ context.Context, key string, limit int64, window time.Duration) (bool, error) {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		l.sweep(now)
+	}
+
+	hits := prune(l.hits[key], now.Add(-window))
+	if int64(len(hits)) >= limit {
+		l.hits[key] = hits
+		return false, nil
+	}
+	l.hits[key] = append(hits, now)
+	l.windows[key] = window
+	return true, nil
+}
+
+func (l *MemoryLimiter) Count(_ context.Context, key string, window time.Duration) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int64(len(prune(l.hits[key], time.Now().Add(-window)))), nil
+}
+
+func (l *MemoryLimiter) sweep(now time.Time) {
+	for key, hits := range l.hits {
+		if hits = prune(hits, now.Add(-l.windows[key])); len(hits) == 0 {
+			delete(l.hits, key)
+			delete(l.windows, key)
+		} else {
+			l.hits[key] = hits
+		}
+	}
+	l.lastSweep = now
+}
+
+// prune 去掉 since 之前的记录，hits 按时间升序排列
+func prune(hits []time.Time, since time.Time) []time.Time {
+	i := 0
+	for i < len(hits) && !hits[i].After(since) {
+		i++
+	}
+	return hits[i:]
+}
@@ -0,0 +1,54 @@
+package limiter
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+)
+
+// slidingWindowScript 使用有序集合记录窗口内每次放行的时间，先清理过期记录再判断数量，整个过程原子执行
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+if redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[3]) then
+	return 0
+end
+redis.call('ZADD', KEYS[1], now, ARGV[4])
+redis.call('PEXPIRE', KEYS[1], window)
+return 1
+`)
+
+var countScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', tonumber(ARGV[1]) - tonumber(ARGV[2]))
+return redis.call('ZCARD', KEYS[1])
+`)
+
+type RedisLimiter struct {
+	rds *redis.Redis
+}
+
+func NewRedisLimiter(rds *redis.Redis) *RedisLimiter {
+	return &RedisLimiter{rds: rds}
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit int64, window time.Duration) (bool, error) {
+	result, err := l.rds.ScriptRunCtx(ctx, slidingWindowScript, []string{keyPrefix + key},
+		time.Now().UnixMilli(), window.Milliseconds(), limit, uuid.NewString())
+	if err != nil {
+		return false, err
+	}
+	allowed, _ := result.(int64)
+	return allowed == 1, nil
+}
+
+func (l *RedisLimiter) Count(ctx context.Context, key string, window time.Duration) (int64, error) {
+	result, err := l.rds.ScriptRunCtx(ctx, countScript, []string{keyPrefix + key}, time.Now().UnixMilli(), window.Milliseconds())
+	if err != nil {
+		return 0, err
+	}
+	count, _ := result.(int64)
+	return count, nil
+}
@@ -6,20 +6,31 @@ import (
 )
 
 type Activity struct {
-	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Cover         string             `bson:"cover" json:"cover"`
-	Name          string             `bson:"name" json:"name"`
-	Location      string             `bson:"location" json:"location"`
-	ExactLocation string             `bson:"exact_location" json:"exactLocation"`
-	Sponsor       string             `bson:"sponsor" json:"sponsor"`
-	Start         int64              `bson:"start" json:"start"`
-	Description   string             `bson:"description" json:"description"`
-	RegisterStart time.Time          `bson:"register_start" json:"registerStart"`
-	RegisterEnd   time.Time          `bson:"register_end" json:"registerEnd"`
-	Contact       string             `bson:"contact" json:"contact"`
-	Limit         int64              `bson:"limit" json:"limit"`
-	Status        int64              `bson:"status" json:"status"`
-	CreateTime    time.Time          `bson:"create_time,omitempty" json:"createTime"`
-	UpdateTime    time.Time          `bson:"update_time,omitempty" json:"updateTime"`
-	DeleteTime    time.Time          `bson:"delete_time,omitempty" json:"deleteTime"`
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Cover           string             `bson:"cover" json:"cover"`
+	Name            string             `bson:"name" json:"name"`
+	Location        string             `bson:"location" json:"location"`
+	ExactLocation   string             `bson:"exact_location" json:"exactLocation"`
+	Sponsor         string             `bson:"sponsor" json:"sponsor"`
+	OrganizationIds []string           `bson:"organization_ids" json:"organizationIds"`
+	GroupId         string             `bson:"group_id,omitempty" json:"groupId"` // 所属群组，为空表示不属于任何群组
+	Start           int64              `bson:"start" json:"start"`
+	Description     string             `bson:"description" json:"description"`
+	RegisterStart   time.Time          `bson:"register_start" json:"registerStart"`
+	RegisterEnd     time.Time          `bson:"register_end" json:"registerEnd"`
+	Contact         string             `bson:"contact" json:"contact"`
+	Limit           int64              `bson:"limit" json:"limit"`
+	Status          int64              `bson:"status" json:"status"`
+	Tickets         []Ticket           `bson:"tickets" json:"tickets"`
+	CreateTime      time.Time          `bson:"create_time,omitempty" json:"createTime"`
+	UpdateTime      time.Time          `bson:"update_time,omitempty" json:"updateTime"`
+	DeleteTime      time.Time          `bson:"delete_time,omitempty" json:"deleteTime"`
+}
+
+// Ticket 活动票种，Price 单位为分，为 0 表示免费
+type Ticket struct {
+	ID          string `bson:"id" json:"id"`
+	Name        string `bson:"name" json:"name"`
+	Price       int64  `bson:"price" json:"price"`
+	Description string `bson:"description" json:"description"`
 }
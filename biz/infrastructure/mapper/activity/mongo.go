@@ -23,7 +23,11 @@ type IMongoMapper interface {
 	Update(ctx context.Context, a *Activity) error
 	FindById(ctx context.Context, id string) (*Activity, error)
 	FindMany(ctx context.Context, p *basic.PaginationOptions) (activities []*Activity, total int64, err error)
+	FindManyByFilter(ctx context.Context, filter bson.M, skip, limit int64) (activities []*Activity, total int64, err error)
 	DeleteById(ctx context.Context, id string) error
+	ReserveSeats(ctx context.Context, id string, n int64) (bool, error)
+	InitSeats(ctx context.Context, id string, occupied int64) (bool, error)
+	AddSeats(ctx context.Context, id string, n int64) error
 }
 
 type MongoMapper struct {
@@ -88,6 +92,24 @@ func (m *MongoMapper) FindMany(ctx context.Context, p *basic.PaginationOptions)
 	return activities, total, nil
 }
 
+func (m *MongoMapper) FindManyByFilter(ctx context.Context, filter bson.M, skip, limit int64) (activities []*Activity, total int64, err error) {
+	activities = make([]*Activity, 0, limit)
+	err = m.conn.Find(ctx, &activities, filter, &options.FindOptions{
+		Skip:  &skip,
+		Limit: &limit,
+		Sort:  bson.M{consts.CreateTime: -1},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err = m.conn.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return activities, total, nil
+}
+
 func (m *MongoMapper) DeleteById(ctx context.Context, id string) error {
 	key := prefixKeyCacheKey + id
 	now := time.Now()
@@ -100,3 +122,53 @@ func (m *MongoMapper) DeleteById(ctx context.Context, id string) error {
 	})
 	return err
 }
+
+// ReserveSeats 原子地占用 n 个名额，Limit 不大于 0 时不限名额，返回是否占用成功；
+// 名额计数 occupied 不在 Activity 中，整体更新活动时不会覆盖，尚未初始化时同样返回 false
+func (m *MongoMapper) ReserveSeats(ctx context.Context, id string, n int64) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, consts.ErrInvalidObjectId
+	}
+	result, err := m.conn.UpdateOneNoCache(ctx, bson.M{
+		consts.ID:       oid,
+		consts.Occupied: bson.M{"$exists": true},
+		"$or": []bson.M{
+			{consts.Limit: bson.M{"$lte": 0}},
+			{"$expr": bson.M{"$lte": bson.A{bson.M{"$add": bson.A{"$" + consts.Occupied, n}}, "$" + consts.Limit}}},
+		},
+	}, bson.M{"$inc": bson.M{consts.Occupied: n}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// InitSeats 名额计数尚未初始化时写入 occupied，返回是否写入
+func (m *MongoMapper) InitSeats(ctx context.Context, id string, occupied int64) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, consts.ErrInvalidObjectId
+	}
+	result, err := m.conn.UpdateOneNoCache(ctx, bson.M{
+		consts.ID:       oid,
+		consts.Occupied: bson.M{"$exists": false},
+	}, bson.M{"$set": bson.M{consts.Occupied: occupied}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// AddSeats 不检查上限地调整名额计数，n 为负数时释放名额；计数尚未初始化时不做处理，初始化时会重新统计
+func (m *MongoMapper) AddSeats(ctx context.Context, id string, n int64) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return consts.ErrInvalidObjectId
+	}
+	_, err = m.conn.UpdateOneNoCache(ctx, bson.M{
+		consts.ID:       oid,
+		consts.Occupied: bson.M{"$exists": true},
+	}, bson.M{"$inc": bson.M{consts.Occupied: n}})
+	return err
+}
@@ -0,0 +1,27 @@
+package audit
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Audit 后台变更审计记录，只追加不修改
+type Audit struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ActorId    string             `bson:"actor_id" json:"actorId"`
+	Action     string             `bson:"action" json:"action"`
+	TargetType string             `bson:"target_type" json:"targetType"`
+	TargetId   string             `bson:"target_id" json:"targetId"`
+	Changes    []Change           `bson:"changes" json:"changes"`
+	ClientIP   string             `bson:"client_ip" json:"clientIp"`
+	TraceId    string             `bson:"trace_id" json:"traceId"`
+	CreateTime time.Time          `bson:"create_time" json:"createTime"`
+}
+
+// Change 单个字段的变更前后值，新建时 Before 为空，删除时 After 为空
+type Change struct {
+	Field  string `bson:"field" json:"field"`
+	Before any    `bson:"before" json:"before"`
+	After  any    `bson:"after" json:"after"`
+}
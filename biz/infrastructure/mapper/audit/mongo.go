@@ -0,0 +1,56 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/zeromicro/go-zero/core/stores/monc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const CollectionName = "audit"
+
+// IMongoMapper 审计记录只提供写入与查询
+type IMongoMapper interface {
+	Insert(ctx context.Context, a *Audit) error
+	FindManyByFilter(ctx context.Context, filter bson.M, skip, limit int64) (audits []*Audit, total int64, err error)
+}
+
+type MongoMapper struct {
+	conn *monc.Model
+}
+
+func NewMongoMapper(config *config.Config) *MongoMapper {
+	conn := monc.MustNewModel(config.Mongo.URL, config.Mongo.DB, CollectionName, config.Cache)
+	return &MongoMapper{conn: conn}
+}
+
+func (m *MongoMapper) Insert(ctx context.Context, a *Audit) error {
+	if a.ID.IsZero() {
+		a.ID = primitive.NewObjectID()
+	}
+	a.CreateTime = time.Now()
+	_, err := m.conn.InsertOneNoCache(ctx, a)
+	return err
+}
+
+func (m *MongoMapper) FindManyByFilter(ctx context.Context, filter bson.M, skip, limit int64) (audits []*Audit, total int64, err error) {
+	audits = make([]*Audit, 0, limit)
+	err = m.conn.Find(ctx, &audits, filter, &options.FindOptions{
+		Skip:  &skip,
+		Limit: &limit,
+		Sort:  bson.M{consts.CreateTime: -1},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err = m.conn.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return audits, total, nil
+}
@@ -0,0 +1,25 @@
+package dictionary
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// 字典类型
+const (
+	KindSchool   = "school"
+	KindIndustry = "industry"
+)
+
+// Entry 管理员维护的标准学校或行业，Aliases 为简称、英文名等别名，学校可填写所在地区，行业通过 ParentId 组织为层级
+type Entry struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Kind       string             `bson:"kind" json:"kind"`
+	Name       string             `bson:"name" json:"name"`
+	Aliases    []string           `bson:"aliases" json:"aliases"`
+	Region     string             `bson:"region,omitempty" json:"region"`
+	ParentId   string             `bson:"parent_id,omitempty" json:"parentId"`
+	CreateTime time.Time          `bson:"create_time,omitempty" json:"createTime"`
+	UpdateTime time.Time          `bson:"update_time,omitempty" json:"updateTime"`
+}
@@ -0,0 +1,117 @@
+package dictionary
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/zeromicro/go-zero/core/stores/monc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	prefixKeyCacheKey = "cache:dictionary"
+	CollectionName    = "dictionary"
+)
+
+type IMongoMapper interface {
+	Insert(ctx context.Context, e *Entry) error
+	Update(ctx context.Context, e *Entry) error
+	FindByID(ctx context.Context, id string) (*Entry, error)
+	FindMany(ctx context.Context, filter bson.M, skip, limit int64) ([]*Entry, int64, error)
+	FindByText(ctx context.Context, kind, text string) (*Entry, error)
+	Delete(ctx context.Context, id primitive.ObjectID) (bool, error)
+}
+
+type MongoMapper struct {
+	conn *monc.Model
+}
+
+func NewMongoMapper(config *config.Config) *MongoMapper {
+	conn := monc.MustNewModel(config.Mongo.URL, config.Mongo.DB, CollectionName, config.Cache)
+	return &MongoMapper{conn: conn}
+}
+
+func (m *MongoMapper) Insert(ctx context.Context, e *Entry) error {
+	if e.ID.IsZero() {
+		e.ID = primitive.NewObjectID()
+	}
+	e.CreateTime = time.Now()
+	e.UpdateTime = e.CreateTime
+	key := prefixKeyCacheKey + e.ID.Hex()
+	_, err := m.conn.InsertOne(ctx, key, e)
+	return err
+}
+
+func (m *MongoMapper) Update(ctx context.Context, e *Entry) error {
+	e.UpdateTime = time.Now()
+	_, err := m.conn.UpdateByIDNoCache(ctx, e.ID, bson.M{"$set": e})
+	return err
+}
+
+func (m *MongoMapper) FindByID(ctx context.Context, id string) (*Entry, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, consts.ErrInvalidObjectId
+	}
+	var e Entry
+	err = m.conn.FindOneNoCache(ctx, &e, bson.M{consts.ID: oid})
+	if err != nil {
+		return nil, consts.ErrNotFound
+	}
+	return &e, nil
+}
+
+// FindMany 按名称排序
+func (m *MongoMapper) FindMany(ctx context.Context, filter bson.M, skip, limit int64) ([]*Entry, int64, error) {
+	entries := make([]*Entry, 0, limit)
+	err := m.conn.Find(ctx, &entries, filter, &options.FindOptions{
+		Skip:  &skip,
+		Limit: &limit,
+		Sort:  bson.M{"name": 1},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := m.conn.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// FindByText 忽略大小写和首尾空白，按名称或别名精确匹配
+func (m *MongoMapper) FindByText(ctx context.Context, kind, text string) (*Entry, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, consts.ErrNotFound
+	}
+	exact := bson.M{"$regex": "^" + regexp.QuoteMeta(text) + "$", "$options": "i"}
+	var e Entry
+	err := m.conn.FindOneNoCache(ctx, &e, bson.M{
+		"kind": kind,
+		"$or":  []bson.M{{"name": exact}, {"aliases": exact}},
+	})
+	switch {
+	case err == nil:
+		return &e, nil
+	case errors.Is(err, monc.ErrNotFound):
+		return nil, consts.ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *MongoMapper) Delete(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	deleted, err := m.conn.DeleteOneNoCache(ctx, bson.M{consts.ID: id})
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
@@ -0,0 +1,38 @@
+package group
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// 群组类别
+const (
+	CategorySchool   = "school"
+	CategoryHometown = "hometown"
+	CategoryIndustry = "industry"
+	CategoryHobby    = "hobby"
+)
+
+// 加入方式：open 直接加入，approval 需管理员审批，invite 仅限受邀
+const (
+	PolicyOpen     = "open"
+	PolicyApproval = "approval"
+	PolicyInvite   = "invite"
+)
+
+// Group 校友群组，RefId 为学校、行业群组关联的字典条目 id，或同乡群组关联的行政区划代码
+type Group struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"`
+	Category    string             `bson:"category" json:"category"`
+	RefId       string             `bson:"ref_id,omitempty" json:"refId"`
+	Avatar      string             `bson:"avatar" json:"avatar"`
+	Description string             `bson:"description" json:"description"`
+	Policy      string             `bson:"policy" json:"policy"`
+	CreatorId   string             `bson:"creator_id" json:"creatorId"`
+	Status      int64              `bson:"status" json:"status"`
+	CreateTime  time.Time          `bson:"create_time,omitempty" json:"createTime"`
+	UpdateTime  time.Time          `bson:"update_time,omitempty" json:"updateTime"`
+	DeleteTime  time.Time          `bson:"delete_time,omitempty" json:"deleteTime"`
+}
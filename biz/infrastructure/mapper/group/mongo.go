@@ -0,0 +1,104 @@
+package group
+
+import (
+	"context"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/zeromicro/go-zero/core/stores/monc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	prefixKeyCacheKey = "cache:group"
+	CollectionName    = "group"
+)
+
+type IMongoMapper interface {
+	Insert(ctx context.Context, g *Group) error
+	Update(ctx context.Context, g *Group) error
+	FindByID(ctx context.Context, id string) (*Group, error)
+	FindByIDs(ctx context.Context, ids []string) ([]*Group, error)
+	FindMany(ctx context.Context, filter bson.M, skip, limit int64) ([]*Group, int64, error)
+}
+
+type MongoMapper struct {
+	conn *monc.Model
+}
+
+func NewMongoMapper(config *config.Config) *MongoMapper {
+	conn := monc.MustNewModel(config.Mongo.URL, config.Mongo.DB, CollectionName, config.Cache)
+	return &MongoMapper{conn: conn}
+}
+
+func (m *MongoMapper) Insert(ctx context.Context, g *Group) error {
+	if g.ID.IsZero() {
+		g.ID = primitive.NewObjectID()
+	}
+	g.CreateTime = time.Now()
+	g.UpdateTime = g.CreateTime
+	key := prefixKeyCacheKey + g.ID.Hex()
+	_, err := m.conn.InsertOne(ctx, key, g)
+	return err
+}
+
+func (m *MongoMapper) Update(ctx context.Context, g *Group) error {
+	g.UpdateTime = time.Now()
+	_, err := m.conn.UpdateByIDNoCache(ctx, g.ID, bson.M{"$set": g})
+	return err
+}
+
+func (m *MongoMapper) FindByID(ctx context.Context, id string) (*Group, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, consts.ErrInvalidObjectId
+	}
+	var g Group
+	err = m.conn.FindOneNoCache(ctx, &g, bson.M{consts.ID: oid})
+	if err != nil {
+		return nil, consts.ErrNotFound
+	}
+	return &g, nil
+}
+
+// FindByIDs 按 id 批量查询未删除的群组，非法 id 直接忽略
+func (m *MongoMapper) FindByIDs(ctx context.Context, ids []string) ([]*Group, error) {
+	oids := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+	groups := make([]*Group, 0, len(oids))
+	if len(oids) == 0 {
+		return groups, nil
+	}
+	err := m.conn.Find(ctx, &groups, bson.M{
+		consts.ID:     bson.M{"$in": oids},
+		consts.Status: bson.M{"$ne": int64(consts.DeleteStatus)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (m *MongoMapper) FindMany(ctx context.Context, filter bson.M, skip, limit int64) ([]*Group, int64, error) {
+	groups := make([]*Group, 0, limit)
+	err := m.conn.Find(ctx, &groups, filter, &options.FindOptions{
+		Skip:  &skip,
+		Limit: &limit,
+		Sort:  bson.M{consts.CreateTime: -1},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := m.conn.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return groups, total, nil
+}
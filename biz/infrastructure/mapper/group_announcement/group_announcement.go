@@ -0,0 +1,17 @@
+package group_announcement
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Announcement 群组公告，仅群组成员可见
+type Announcement struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	GroupId    string             `bson:"group_id" json:"groupId"`
+	AuthorId   string             `bson:"author_id" json:"authorId"`
+	Title      string             `bson:"title" json:"title"`
+	Content    string             `bson:"content" json:"content"`
+	CreateTime time.Time          `bson:"create_time,omitempty" json:"createTime"`
+}
@@ -0,0 +1,101 @@
+package group_announcement
+
+import (
+	"context"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/zeromicro/go-zero/core/stores/monc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	prefixKeyCacheKey = "cache:group_announcement"
+	CollectionName    = "group_announcement"
+)
+
+type IMongoMapper interface {
+	Insert(ctx context.Context, a *Announcement) error
+	FindByID(ctx context.Context, id string) (*Announcement, error)
+	FindByGroup(ctx context.Context, groupId string, skip, limit int64) ([]*Announcement, int64, error)
+	Delete(ctx context.Context, id primitive.ObjectID) (bool, error)
+	FindByAuthor(ctx context.Context, authorId string, skip, limit int64) ([]*Announcement, int64, error)
+	ReassignAuthor(ctx context.Context, from, to string) (int64, error)
+}
+
+type MongoMapper struct {
+	conn *monc.Model
+}
+
+func NewMongoMapper(config *config.Config) *MongoMapper {
+	conn := monc.MustNewModel(config.Mongo.URL, config.Mongo.DB, CollectionName, config.Cache)
+	return &MongoMapper{conn: conn}
+}
+
+func (m *MongoMapper) Insert(ctx context.Context, a *Announcement) error {
+	if a.ID.IsZero() {
+		a.ID = primitive.NewObjectID()
+	}
+	a.CreateTime = time.Now()
+	key := prefixKeyCacheKey + a.ID.Hex()
+	_, err := m.conn.InsertOne(ctx, key, a)
+	return err
+}
+
+func (m *MongoMapper) FindByID(ctx context.Context, id string) (*Announcement, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, consts.ErrInvalidObjectId
+	}
+	var a Announcement
+	err = m.conn.FindOneNoCache(ctx, &a, bson.M{consts.ID: oid})
+	if err != nil {
+		return nil, consts.ErrNotFound
+	}
+	return &a, nil
+}
+
+func (m *MongoMapper) FindByGroup(ctx context.Context, groupId string, skip, limit int64) ([]*Announcement, int64, error) {
+	return m.find(ctx, bson.M{"group_id": groupId}, skip, limit)
+}
+
+func (m *MongoMapper) FindByAuthor(ctx context.Context, authorId string, skip, limit int64) ([]*Announcement, int64, error) {
+	return m.find(ctx, bson.M{"author_id": authorId}, skip, limit)
+}
+
+func (m *MongoMapper) find(ctx context.Context, filter bson.M, skip, limit int64) ([]*Announcement, int64, error) {
+	data := make([]*Announcement, 0, limit)
+	err := m.conn.Find(ctx, &data, filter, &options.FindOptions{
+		Skip:  &skip,
+		Limit: &limit,
+		Sort:  bson.M{consts.CreateTime: -1},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := m.conn.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, total, nil
+}
+
+func (m *MongoMapper) Delete(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	deleted, err := m.conn.DeleteOneNoCache(ctx, bson.M{consts.ID: id})
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+// ReassignAuthor 将 from 用户发布的公告转移给 to 用户，用于账号合并
+func (m *MongoMapper) ReassignAuthor(ctx context.Context, from, to string) (int64, error) {
+	result, err := m.conn.UpdateManyNoCache(ctx, bson.M{"author_id": from}, bson.M{"$set": bson.M{"author_id": to}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
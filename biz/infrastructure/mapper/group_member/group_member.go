@@ -0,0 +1,32 @@
+package group_member
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+// 成员状态：pending 为申请待审批，invited 为已邀请待接受，active 为正式成员
+const (
+	StatusPending = "pending"
+	StatusInvited = "invited"
+	StatusActive  = "active"
+)
+
+// Member 群组成员关系，每个用户在一个群组中至多有一条记录
+type Member struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	GroupId    string             `bson:"group_id" json:"groupId"`
+	UserId     string             `bson:"user_id" json:"userId"`
+	Role       string             `bson:"role" json:"role"`
+	Status     string             `bson:"status" json:"status"`
+	InviterId  string             `bson:"inviter_id,omitempty" json:"inviterId"`
+	JoinTime   time.Time          `bson:"join_time,omitempty" json:"joinTime"` // 成为正式成员的时间
+	CreateTime time.Time          `bson:"create_time,omitempty" json:"createTime"`
+	UpdateTime time.Time          `bson:"update_time,omitempty" json:"updateTime"`
+}
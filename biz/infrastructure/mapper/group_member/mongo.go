@@ -0,0 +1,209 @@
+package group_member
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/zeromicro/go-zero/core/stores/monc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	prefixKeyCacheKey = "cache:group_member"
+	CollectionName    = "group_member"
+)
+
+type IMongoMapper interface {
+	Insert(ctx context.Context, member *Member) error
+	FindOne(ctx context.Context, groupId, userId string) (*Member, error)
+	FindMany(ctx context.Context, filter bson.M, skip, limit int64) ([]*Member, int64, error)
+	Transit(ctx context.Context, id primitive.ObjectID, from string, set bson.M) (bool, error)
+	Delete(ctx context.Context, id primitive.ObjectID) (bool, error)
+	DeleteByUser(ctx context.Context, userId string) (int64, error)
+	CountActive(ctx context.Context, groupIds []string) (map[string]int64, error)
+	CountAdmins(ctx context.Context, groupId string) (int64, error)
+	FindGroupIds(ctx context.Context, userId string) ([]string, error)
+	ReassignUser(ctx context.Context, from, to string) (int64, error)
+}
+
+type MongoMapper struct {
+	conn *monc.Model
+}
+
+func NewMongoMapper(config *config.Config) *MongoMapper {
+	conn := monc.MustNewModel(config.Mongo.URL, config.Mongo.DB, CollectionName, config.Cache)
+	return &MongoMapper{conn: conn}
+}
+
+func (m *MongoMapper) Insert(ctx context.Context, member *Member) error {
+	if member.ID.IsZero() {
+		member.ID = primitive.NewObjectID()
+	}
+	member.CreateTime = time.Now()
+	member.UpdateTime = member.CreateTime
+	key := prefixKeyCacheKey + member.ID.Hex()
+	_, err := m.conn.InsertOne(ctx, key, member)
+	return err
+}
+
+// FindOne 查询用户在群组中的成员记录，包括待审批和待接受邀请的记录
+func (m *MongoMapper) FindOne(ctx context.Context, groupId, userId string) (*Member, error) {
+	var member Member
+	err := m.conn.FindOneNoCache(ctx, &member, bson.M{"group_id": groupId, consts.UserID: userId})
+	switch {
+	case err == nil:
+		return &member, nil
+	case errors.Is(err, monc.ErrNotFound):
+		return nil, consts.ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindMany 管理员在前，其余按加入时间排列
+func (m *MongoMapper) FindMany(ctx context.Context, filter bson.M, skip, limit int64) ([]*Member, int64, error) {
+	data := make([]*Member, 0, limit)
+	err := m.conn.Find(ctx, &data, filter, &options.FindOptions{
+		Skip:  &skip,
+		Limit: &limit,
+		Sort:  bson.D{{Key: "role", Value: 1}, {Key: "join_time", Value: 1}, {Key: consts.CreateTime, Value: 1}},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := m.conn.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, total, nil
+}
+
+// Transit 仅当成员记录仍处于 from 状态时写入 set，返回是否写入成功，避免重复审批
+func (m *MongoMapper) Transit(ctx context.Context, id primitive.ObjectID, from string, set bson.M) (bool, error) {
+	update := bson.M{consts.UpdateTime: time.Now()}
+	for k, v := range set {
+		update[k] = v
+	}
+	result, err := m.conn.UpdateOneNoCache(ctx, bson.M{
+		consts.ID:     id,
+		consts.Status: from,
+	}, bson.M{"$set": update})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (m *MongoMapper) Delete(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	deleted, err := m.conn.DeleteOneNoCache(ctx, bson.M{consts.ID: id})
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+// DeleteByUser 删除用户在所有群组中的成员记录，用于注销账号
+func (m *MongoMapper) DeleteByUser(ctx context.Context, userId string) (int64, error) {
+	return m.conn.DeleteMany(ctx, bson.M{consts.UserID: userId})
+}
+
+// CountActive 统计各群组的正式成员数量，没有成员的群组不出现在结果中
+func (m *MongoMapper) CountActive(ctx context.Context, groupIds []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(groupIds))
+	if len(groupIds) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		GroupId string `bson:"_id"`
+		Count   int64  `bson:"count"`
+	}
+	err := m.conn.Aggregate(ctx, &rows, []bson.M{
+		{"$match": bson.M{"group_id": bson.M{"$in": groupIds}, consts.Status: StatusActive}},
+		{"$group": bson.M{"_id": "$group_id", "count": bson.M{"$sum": 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.GroupId] = row.Count
+	}
+	return counts, nil
+}
+
+func (m *MongoMapper) CountAdmins(ctx context.Context, groupId string) (int64, error) {
+	return m.conn.CountDocuments(ctx, bson.M{"group_id": groupId, "role": RoleAdmin, consts.Status: StatusActive})
+}
+
+// FindGroupIds 返回用户作为正式成员加入的群组
+func (m *MongoMapper) FindGroupIds(ctx context.Context, userId string) ([]string, error) {
+	data := make([]*Member, 0)
+	err := m.conn.Find(ctx, &data, bson.M{consts.UserID: userId, consts.Status: StatusActive}, &options.FindOptions{
+		Projection: bson.M{"group_id": 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(data))
+	for _, item := range data {
+		ids = append(ids, item.GroupId)
+	}
+	return ids, nil
+}
+
+// ReassignUser 将 from 用户的成员记录转移给 to 用户，用于账号合并；两人在同一群组都有记录时保留 to 用户的记录，
+// 并取两者中较高的身份，先写入 to 用户的记录再删除 from 用户的记录，保证群组不会因此失去管理员
+func (m *MongoMapper) ReassignUser(ctx context.Context, from, to string) (int64, error) {
+	sources := make([]*Member, 0)
+	if err := m.conn.Find(ctx, &sources, bson.M{consts.UserID: from}); err != nil {
+		return 0, err
+	}
+	var moved int64
+	for _, src := range sources {
+		dst, err := m.FindOne(ctx, src.GroupId, to)
+		switch {
+		case errors.Is(err, consts.ErrNotFound):
+			if _, err = m.conn.UpdateOneNoCache(ctx, bson.M{consts.ID: src.ID}, bson.M{
+				"$set": bson.M{consts.UserID: to, consts.UpdateTime: time.Now()},
+			}); err != nil {
+				return 0, err
+			}
+			moved++
+			continue
+		case err != nil:
+			return 0, err
+		}
+		if memberRank(src) > memberRank(dst) {
+			if _, err = m.conn.UpdateOneNoCache(ctx, bson.M{consts.ID: dst.ID}, bson.M{"$set": bson.M{
+				"role":            src.Role,
+				consts.Status:     src.Status,
+				"inviter_id":      src.InviterId,
+				"join_time":       src.JoinTime,
+				consts.UpdateTime: time.Now(),
+			}}); err != nil {
+				return 0, err
+			}
+		}
+		if _, err = m.Delete(ctx, src.ID); err != nil {
+			return 0, err
+		}
+		moved++
+	}
+	return moved, nil
+}
+
+// memberRank 成员记录的身份高低：正式管理员高于正式成员，正式成员高于待审批或待接受邀请
+func memberRank(member *Member) int {
+	switch {
+	case member.Status != StatusActive:
+		return 0
+	case member.Role == RoleAdmin:
+		return 2
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,35 @@
+package job
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// 招聘信息状态：发布后待审核，审核通过后公开展示，发布人可随时关闭
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+	StatusClosed   = "closed"
+)
+
+// Job 认证校友发布的招聘信息，薪资单位为元/月，均为 0 时表示面议
+type Job struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PosterId     string             `bson:"poster_id" json:"posterId"`
+	Organization string             `bson:"organization" json:"organization"`
+	Position     string             `bson:"position" json:"position"`
+	Industry     string             `bson:"industry" json:"industry"`
+	City         string             `bson:"city" json:"city"`
+	SalaryMin    int64              `bson:"salary_min" json:"salaryMin"`
+	SalaryMax    int64              `bson:"salary_max" json:"salaryMax"`
+	Description  string             `bson:"description" json:"description"`
+	ExpireTime   time.Time          `bson:"expire_time" json:"expireTime"`
+	Status       string             `bson:"status" json:"status"`
+	Reason       string             `bson:"reason,omitempty" json:"reason"` // 驳回理由
+	ReviewerId   string             `bson:"reviewer_id,omitempty" json:"reviewerId"`
+	ReviewTime   time.Time          `bson:"review_time,omitempty" json:"reviewTime"`
+	CreateTime   time.Time          `bson:"create_time,omitempty" json:"createTime"`
+	UpdateTime   time.Time          `bson:"update_time,omitempty" json:"updateTime"`
+}
@@ -0,0 +1,135 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/zeromicro/go-zero/core/stores/monc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	prefixKeyCacheKey = "cache:job"
+	CollectionName    = "job"
+)
+
+type IMongoMapper interface {
+	Insert(ctx context.Context, j *Job) error
+	FindByID(ctx context.Context, id string) (*Job, error)
+	FindMany(ctx context.Context, filter bson.M, skip, limit int64) ([]*Job, int64, error)
+	Review(ctx context.Context, id primitive.ObjectID, to, reason, reviewerId string) (bool, error)
+	Close(ctx context.Context, id primitive.ObjectID) (bool, error)
+	ReassignPoster(ctx context.Context, from, to string) (int64, error)
+	CloseByPoster(ctx context.Context, posterId string) error
+}
+
+type MongoMapper struct {
+	conn *monc.Model
+}
+
+func NewMongoMapper(config *config.Config) *MongoMapper {
+	conn := monc.MustNewModel(config.Mongo.URL, config.Mongo.DB, CollectionName, config.Cache)
+	return &MongoMapper{conn: conn}
+}
+
+func (m *MongoMapper) Insert(ctx context.Context, j *Job) error {
+	if j.ID.IsZero() {
+		j.ID = primitive.NewObjectID()
+	}
+	j.CreateTime = time.Now()
+	j.UpdateTime = j.CreateTime
+	key := prefixKeyCacheKey + j.ID.Hex()
+	_, err := m.conn.InsertOne(ctx, key, j)
+	return err
+}
+
+func (m *MongoMapper) FindByID(ctx context.Context, id string) (*Job, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, consts.ErrInvalidObjectId
+	}
+	var j Job
+	err = m.conn.FindOneNoCache(ctx, &j, bson.M{consts.ID: oid})
+	if err != nil {
+		return nil, consts.ErrNotFound
+	}
+	return &j, nil
+}
+
+func (m *MongoMapper) FindMany(ctx context.Context, filter bson.M, skip, limit int64) ([]*Job, int64, error) {
+	jobs := make([]*Job, 0, limit)
+	err := m.conn.Find(ctx, &jobs, filter, &options.FindOptions{
+		Skip:  &skip,
+		Limit: &limit,
+		Sort:  bson.M{consts.CreateTime: -1},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := m.conn.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
+
+// Review 仅当招聘信息仍待审核时写入审核结果，返回是否写入成功，避免重复审核
+func (m *MongoMapper) Review(ctx context.Context, id primitive.ObjectID, to, reason, reviewerId string) (bool, error) {
+	now := time.Now()
+	result, err := m.conn.UpdateOneNoCache(ctx, bson.M{
+		consts.ID:     id,
+		consts.Status: StatusPending,
+	}, bson.M{"$set": bson.M{
+		consts.Status:     to,
+		"reason":          reason,
+		"reviewer_id":     reviewerId,
+		"review_time":     now,
+		consts.UpdateTime: now,
+	}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// Close 关闭待审核或已通过的招聘信息，返回是否关闭成功
+func (m *MongoMapper) Close(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	result, err := m.conn.UpdateOneNoCache(ctx, bson.M{
+		consts.ID:     id,
+		consts.Status: bson.M{"$in": []string{StatusPending, StatusApproved}},
+	}, bson.M{"$set": bson.M{
+		consts.Status:     StatusClosed,
+		consts.UpdateTime: time.Now(),
+	}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// ReassignPoster 将 from 用户发布的招聘信息转移给 to 用户，用于账号合并
+func (m *MongoMapper) ReassignPoster(ctx context.Context, from, to string) (int64, error) {
+	result, err := m.conn.UpdateManyNoCache(ctx, bson.M{"poster_id": from}, bson.M{
+		"$set": bson.M{"poster_id": to, consts.UpdateTime: time.Now()},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// CloseByPoster 关闭用户发布的全部待审核或已通过的招聘信息，用于账号注销
+func (m *MongoMapper) CloseByPoster(ctx context.Context, posterId string) error {
+	_, err := m.conn.UpdateManyNoCache(ctx, bson.M{
+		"poster_id":   posterId,
+		consts.Status: bson.M{"$in": []string{StatusPending, StatusApproved}},
+	}, bson.M{"$set": bson.M{
+		consts.Status:     StatusClosed,
+		consts.UpdateTime: time.Now(),
+	}})
+	return err
+}
@@ -0,0 +1,16 @@
+package job_application
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// JobApplication 投递记录，投递即同意向发布人展示申请人的完整资料
+type JobApplication struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	JobId      string             `bson:"job_id" json:"jobId"`
+	UserId     string             `bson:"user_id" json:"userId"`
+	Message    string             `bson:"message" json:"message"`
+	CreateTime time.Time          `bson:"create_time,omitempty" json:"createTime"`
+}
@@ -0,0 +1,150 @@
+package job_application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/zeromicro/go-zero/core/stores/monc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	prefixKeyCacheKey = "cache:job_application"
+	CollectionName    = "job_application"
+)
+
+type IMongoMapper interface {
+	Insert(ctx context.Context, a *JobApplication) error
+	FindOne(ctx context.Context, jobId, userId string) (*JobApplication, error)
+	FindByJob(ctx context.Context, jobId string, skip, limit int64) ([]*JobApplication, int64, error)
+	FindByUser(ctx context.Context, userId string, skip, limit int64) ([]*JobApplication, int64, error)
+	CountByJobs(ctx context.Context, jobIds []string) (map[string]int64, error)
+	FindApplied(ctx context.Context, userId string, jobIds []string) (map[string]bool, error)
+	ReassignUser(ctx context.Context, from, to string) (int64, error)
+	ClearUser(ctx context.Context, userId string) error
+}
+
+type MongoMapper struct {
+	conn *monc.Model
+}
+
+func NewMongoMapper(config *config.Config) *MongoMapper {
+	conn := monc.MustNewModel(config.Mongo.URL, config.Mongo.DB, CollectionName, config.Cache)
+	return &MongoMapper{conn: conn}
+}
+
+func (m *MongoMapper) Insert(ctx context.Context, a *JobApplication) error {
+	if a.ID.IsZero() {
+		a.ID = primitive.NewObjectID()
+	}
+	a.CreateTime = time.Now()
+	key := prefixKeyCacheKey + a.ID.Hex()
+	_, err := m.conn.InsertOne(ctx, key, a)
+	return err
+}
+
+// FindOne 查询用户对某个职位的投递记录
+func (m *MongoMapper) FindOne(ctx context.Context, jobId, userId string) (*JobApplication, error) {
+	var a JobApplication
+	err := m.conn.FindOneNoCache(ctx, &a, bson.M{"job_id": jobId, consts.UserID: userId})
+	switch {
+	case err == nil:
+		return &a, nil
+	case errors.Is(err, monc.ErrNotFound):
+		return nil, consts.ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *MongoMapper) FindByJob(ctx context.Context, jobId string, skip, limit int64) ([]*JobApplication, int64, error) {
+	return m.find(ctx, bson.M{"job_id": jobId}, skip, limit)
+}
+
+func (m *MongoMapper) FindByUser(ctx context.Context, userId string, skip, limit int64) ([]*JobApplication, int64, error) {
+	return m.find(ctx, bson.M{consts.UserID: userId}, skip, limit)
+}
+
+func (m *MongoMapper) find(ctx context.Context, filter bson.M, skip, limit int64) ([]*JobApplication, int64, error) {
+	data := make([]*JobApplication, 0, limit)
+	err := m.conn.Find(ctx, &data, filter, &options.FindOptions{
+		Skip:  &skip,
+		Limit: &limit,
+		Sort:  bson.M{consts.CreateTime: -1},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := m.conn.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, total, nil
+}
+
+// CountByJobs 统计各职位的投递数量，没有投递的职位不出现在结果中
+func (m *MongoMapper) CountByJobs(ctx context.Context, jobIds []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(jobIds))
+	if len(jobIds) == 0 {
+		return counts, nil
+	}
+	data := make([]*JobApplication, 0)
+	err := m.conn.Find(ctx, &data, bson.M{"job_id": bson.M{"$in": jobIds}}, &options.FindOptions{
+		Projection: bson.M{"job_id": 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range data {
+		counts[item.JobId]++
+	}
+	return counts, nil
+}
+
+// FindApplied 返回用户已投递过的职位
+func (m *MongoMapper) FindApplied(ctx context.Context, userId string, jobIds []string) (map[string]bool, error) {
+	applied := make(map[string]bool, len(jobIds))
+	if userId == "" || len(jobIds) == 0 {
+		return applied, nil
+	}
+	data := make([]*JobApplication, 0, len(jobIds))
+	err := m.conn.Find(ctx, &data, bson.M{consts.UserID: userId, "job_id": bson.M{"$in": jobIds}}, &options.FindOptions{
+		Projection: bson.M{"job_id": 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range data {
+		applied[item.JobId] = true
+	}
+	return applied, nil
+}
+
+// ReassignUser 将 from 用户的投递记录转移给 to 用户，用于账号合并；两人投递过同一职位时保留 to 用户的记录
+func (m *MongoMapper) ReassignUser(ctx context.Context, from, to string) (int64, error) {
+	applied, err := m.conn.Distinct(ctx, "job_id", bson.M{consts.UserID: to})
+	if err != nil {
+		return 0, err
+	}
+	if len(applied) > 0 {
+		if _, err = m.conn.DeleteMany(ctx, bson.M{consts.UserID: from, "job_id": bson.M{"$in": applied}}); err != nil {
+			return 0, err
+		}
+	}
+	result, err := m.conn.UpdateManyNoCache(ctx, bson.M{consts.UserID: from}, bson.M{"$set": bson.M{consts.UserID: to}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// ClearUser 抹去用户投递时填写的留言，用于账号注销
+func (m *MongoMapper) ClearUser(ctx context.Context, userId string) error {
+	_, err := m.conn.UpdateManyNoCache(ctx, bson.M{consts.UserID: userId}, bson.M{"$set": bson.M{"message": ""}})
+	return err
+}
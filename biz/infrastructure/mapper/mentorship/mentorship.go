@@ -0,0 +1,28 @@
+package mentorship
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// 申请状态：待处理 -> 已接受/已拒绝，已接受 -> 已完成
+const (
+	StatusPending   = "pending"
+	StatusAccepted  = "accepted"
+	StatusDeclined  = "declined"
+	StatusCompleted = "completed"
+)
+
+// Mentorship 校友向导师发起的指导申请，一对导师与学员同时只能有一条未结束的申请
+type Mentorship struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	MentorId   string             `bson:"mentor_id" json:"mentorId"`
+	MenteeId   string             `bson:"mentee_id" json:"menteeId"`
+	Topic      string             `bson:"topic" json:"topic"`
+	Message    string             `bson:"message" json:"message"`
+	Reply      string             `bson:"reply,omitempty" json:"reply"` // 导师接受或拒绝时的回复
+	Status     string             `bson:"status" json:"status"`
+	CreateTime time.Time          `bson:"create_time,omitempty" json:"createTime"`
+	UpdateTime time.Time          `bson:"update_time,omitempty" json:"updateTime"`
+}
@@ -0,0 +1,174 @@
+package mentorship
+
+import (
+	"context"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/zeromicro/go-zero/core/stores/monc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	prefixKeyCacheKey = "cache:mentorship"
+	CollectionName    = "mentorship"
+)
+
+type IMongoMapper interface {
+	Insert(ctx context.Context, m *Mentorship) error
+	FindByID(ctx context.Context, id string) (*Mentorship, error)
+	FindMany(ctx context.Context, filter bson.M, skip, limit int64) ([]*Mentorship, int64, error)
+	FindOpen(ctx context.Context, mentorId, menteeId string) (*Mentorship, error)
+	CountAccepted(ctx context.Context, mentorIds []string) (map[string]int64, error)
+	Transit(ctx context.Context, id primitive.ObjectID, from, to, reply string) (bool, error)
+	ReassignUser(ctx context.Context, from, to string) (int64, error)
+	ClearUser(ctx context.Context, userId string) error
+}
+
+type MongoMapper struct {
+	conn *monc.Model
+}
+
+func NewMongoMapper(config *config.Config) *MongoMapper {
+	conn := monc.MustNewModel(config.Mongo.URL, config.Mongo.DB, CollectionName, config.Cache)
+	return &MongoMapper{conn: conn}
+}
+
+func (m *MongoMapper) Insert(ctx context.Context, ms *Mentorship) error {
+	if ms.ID.IsZero() {
+		ms.ID = primitive.NewObjectID()
+	}
+	ms.CreateTime = time.Now()
+	ms.UpdateTime = ms.CreateTime
+	key := prefixKeyCacheKey + ms.ID.Hex()
+	_, err := m.conn.InsertOne(ctx, key, ms)
+	return err
+}
+
+func (m *MongoMapper) FindByID(ctx context.Context, id string) (*Mentorship, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, consts.ErrInvalidObjectId
+	}
+	var ms Mentorship
+	err = m.conn.FindOneNoCache(ctx, &ms, bson.M{consts.ID: oid})
+	if err != nil {
+		return nil, consts.ErrNotFound
+	}
+	return &ms, nil
+}
+
+func (m *MongoMapper) FindMany(ctx context.Context, filter bson.M, skip, limit int64) ([]*Mentorship, int64, error) {
+	data := make([]*Mentorship, 0, limit)
+	err := m.conn.Find(ctx, &data, filter, &options.FindOptions{
+		Skip:  &skip,
+		Limit: &limit,
+		Sort:  bson.M{consts.UpdateTime: -1},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := m.conn.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, total, nil
+}
+
+// FindOpen 查询导师与学员之间待处理或进行中的申请
+func (m *MongoMapper) FindOpen(ctx context.Context, mentorId, menteeId string) (*Mentorship, error) {
+	var ms Mentorship
+	err := m.conn.FindOneNoCache(ctx, &ms, bson.M{
+		"mentor_id":   mentorId,
+		"mentee_id":   menteeId,
+		consts.Status: bson.M{"$in": []string{StatusPending, StatusAccepted}},
+	})
+	if err != nil {
+		return nil, consts.ErrNotFound
+	}
+	return &ms, nil
+}
+
+// CountAccepted 统计各导师进行中的指导数量，没有进行中指导的导师不出现在结果中
+func (m *MongoMapper) CountAccepted(ctx context.Context, mentorIds []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(mentorIds))
+	if len(mentorIds) == 0 {
+		return counts, nil
+	}
+	data := make([]*Mentorship, 0)
+	err := m.conn.Find(ctx, &data, bson.M{
+		"mentor_id":   bson.M{"$in": mentorIds},
+		consts.Status: StatusAccepted,
+	}, &options.FindOptions{Projection: bson.M{"mentor_id": 1}})
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range data {
+		counts[item.MentorId]++
+	}
+	return counts, nil
+}
+
+// Transit 仅当申请仍处于 from 状态时切换到 to，返回是否切换成功，避免并发操作重复处理
+func (m *MongoMapper) Transit(ctx context.Context, id primitive.ObjectID, from, to, reply string) (bool, error) {
+	set := bson.M{
+		consts.Status:     to,
+		consts.UpdateTime: time.Now(),
+	}
+	if reply != "" {
+		set["reply"] = reply
+	}
+	result, err := m.conn.UpdateOneNoCache(ctx, bson.M{
+		consts.ID:     id,
+		consts.Status: from,
+	}, bson.M{"$set": set})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// ReassignUser 将 from 用户作为导师或学员的申请转移给 to 用户，用于账号合并；双方之间的申请合并后没有意义，直接删除
+func (m *MongoMapper) ReassignUser(ctx context.Context, from, to string) (int64, error) {
+	if _, err := m.conn.DeleteMany(ctx, bson.M{"$or": []bson.M{
+		{"mentor_id": from, "mentee_id": to},
+		{"mentor_id": to, "mentee_id": from},
+	}}); err != nil {
+		return 0, err
+	}
+	var moved int64
+	for _, field := range []string{"mentor_id", "mentee_id"} {
+		result, err := m.conn.UpdateManyNoCache(ctx, bson.M{field: from}, bson.M{
+			"$set": bson.M{field: to, consts.UpdateTime: time.Now()},
+		})
+		if err != nil {
+			return 0, err
+		}
+		moved += result.ModifiedCount
+	}
+	return moved, nil
+}
+
+// ClearUser 抹去用户作为学员填写的申请内容和作为导师的回复，并结束其未完成的申请，用于账号注销
+func (m *MongoMapper) ClearUser(ctx context.Context, userId string) error {
+	now := time.Now()
+	updates := []struct {
+		filter bson.M
+		set    bson.M
+	}{
+		{bson.M{"mentee_id": userId}, bson.M{"message": ""}},
+		{bson.M{"mentor_id": userId}, bson.M{"reply": ""}},
+		{bson.M{"$or": []bson.M{{"mentor_id": userId}, {"mentee_id": userId}}, consts.Status: StatusPending}, bson.M{consts.Status: StatusDeclined}},
+		{bson.M{"$or": []bson.M{{"mentor_id": userId}, {"mentee_id": userId}}, consts.Status: StatusAccepted}, bson.M{consts.Status: StatusCompleted}},
+	}
+	for _, u := range updates {
+		u.set[consts.UpdateTime] = now
+		if _, err := m.conn.UpdateManyNoCache(ctx, u.filter, bson.M{"$set": u.set}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/zeromicro/go-zero/core/stores/monc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const CollectionName = "notification"
+
+type IMongoMapper interface {
+	InsertMany(ctx context.Context, items []*Notification) error
+	FindByUser(ctx context.Context, userId string, skip, limit int64) ([]*Notification, int64, error)
+	CountUnread(ctx context.Context, userId string) (int64, error)
+	MarkRead(ctx context.Context, userId string, ids []primitive.ObjectID) (int64, error)
+	FindNotified(ctx context.Context, typ string, userIds []string, since time.Time) (map[string]bool, error)
+}
+
+type MongoMapper struct {
+	conn *monc.Model
+}
+
+func NewMongoMapper(config *config.Config) *MongoMapper {
+	conn := monc.MustNewModel(config.Mongo.URL, config.Mongo.DB, CollectionName, config.Cache)
+	return &MongoMapper{conn: conn}
+}
+
+func (m *MongoMapper) InsertMany(ctx context.Context, items []*Notification) error {
+	if len(items) == 0 {
+		return nil
+	}
+	now := time.Now()
+	docs := make([]any, 0, len(items))
+	for _, item := range items {
+		if item.ID.IsZero() {
+			item.ID = primitive.NewObjectID()
+		}
+		item.CreateTime = now
+		docs = append(docs, item)
+	}
+	_, err := m.conn.InsertMany(ctx, docs)
+	return err
+}
+
+func (m *MongoMapper) FindByUser(ctx context.Context, userId string, skip, limit int64) ([]*Notification, int64, error) {
+	filter := bson.M{consts.UserID: userId}
+	data := make([]*Notification, 0, limit)
+	err := m.conn.Find(ctx, &data, filter, &options.FindOptions{
+		Skip:  &skip,
+		Limit: &limit,
+		Sort:  bson.M{consts.CreateTime: -1},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := m.conn.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, total, nil
+}
+
+func (m *MongoMapper) CountUnread(ctx context.Context, userId string) (int64, error) {
+	return m.conn.CountDocuments(ctx, bson.M{consts.UserID: userId, "read_time": bson.M{"$exists": false}})
+}
+
+// MarkRead 将用户的通知标记为已读，ids 为空时标记全部
+func (m *MongoMapper) MarkRead(ctx context.Context, userId string, ids []primitive.ObjectID) (int64, error) {
+	filter := bson.M{consts.UserID: userId, "read_time": bson.M{"$exists": false}}
+	if len(ids) > 0 {
+		filter[consts.ID] = bson.M{"$in": ids}
+	}
+	result, err := m.conn.UpdateManyNoCache(ctx, filter, bson.M{"$set": bson.M{"read_time": time.Now()}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// FindNotified 返回 since 之后已收到过该类通知的用户
+func (m *MongoMapper) FindNotified(ctx context.Context, typ string, userIds []string, since time.Time) (map[string]bool, error) {
+	notified := make(map[string]bool, len(userIds))
+	if len(userIds) == 0 {
+		return notified, nil
+	}
+	data := make([]*Notification, 0, len(userIds))
+	err := m.conn.Find(ctx, &data, bson.M{
+		"type":            typ,
+		consts.UserID:     bson.M{"$in": userIds},
+		consts.CreateTime: bson.M{"$gte": since},
+	}, &options.FindOptions{
+		Projection: bson.M{consts.UserID: 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range data {
+		notified[item.UserId] = true
+	}
+	return notified, nil
+}
@@ -0,0 +1,25 @@
+package notification
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// 通知类型
+const (
+	TypeProfileIncomplete = "profile_incomplete" // 提醒用户完善资料
+	TypeGroupInvite       = "group_invite"
+	TypeGroupAnnouncement = "group_announcement"
+)
+
+// Notification 站内通知，ReadTime 为零值表示未读
+type Notification struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserId     string             `bson:"user_id" json:"userId"`
+	Type       string             `bson:"type" json:"type"`
+	Title      string             `bson:"title" json:"title"`
+	Content    string             `bson:"content" json:"content"`
+	ReadTime   time.Time          `bson:"read_time,omitempty" json:"readTime"`
+	CreateTime time.Time          `bson:"create_time,omitempty" json:"createTime"`
+}
@@ -0,0 +1,149 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/zeromicro/go-zero/core/stores/monc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	prefixKeyCacheKey = "cache:order"
+	CollectionName    = "order"
+)
+
+type IMongoMapper interface {
+	Insert(ctx context.Context, o *Order) error
+	FindByID(ctx context.Context, id string) (*Order, error)
+	FindManyByFilter(ctx context.Context, filter bson.M, skip, limit int64) (orders []*Order, total int64, err error)
+	FindOverdue(ctx context.Context, activityId string, now time.Time) (orders []*Order, err error)
+	Transit(ctx context.Context, id primitive.ObjectID, from []string, to string, fields bson.M) (bool, error)
+	ReassignUser(ctx context.Context, from, to string) (int64, error)
+	FindActiveTicketIds(ctx context.Context, activityId string, now time.Time) (map[string]bool, error)
+}
+
+type MongoMapper struct {
+	conn *monc.Model
+}
+
+func NewMongoMapper(config *config.Config) *MongoMapper {
+	conn := monc.MustNewModel(config.Mongo.URL, config.Mongo.DB, CollectionName, config.Cache)
+	return &MongoMapper{conn: conn}
+}
+
+func (m *MongoMapper) Insert(ctx context.Context, o *Order) error {
+	if o.ID.IsZero() {
+		o.ID = primitive.NewObjectID()
+	}
+	o.CreateTime = time.Now()
+	o.UpdateTime = o.CreateTime
+	key := prefixKeyCacheKey + o.ID.Hex()
+	_, err := m.conn.InsertOne(ctx, key, o)
+	return err
+}
+
+func (m *MongoMapper) FindByID(ctx context.Context, id string) (*Order, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, consts.ErrInvalidObjectId
+	}
+	var o Order
+	err = m.conn.FindOneNoCache(ctx, &o, bson.M{consts.ID: oid})
+	if err != nil {
+		return nil, consts.ErrNotFound
+	}
+	return &o, nil
+}
+
+func (m *MongoMapper) FindManyByFilter(ctx context.Context, filter bson.M, skip, limit int64) (orders []*Order, total int64, err error) {
+	orders = make([]*Order, 0, limit)
+	err = m.conn.Find(ctx, &orders, filter, &options.FindOptions{
+		Skip:  &skip,
+		Limit: &limit,
+		Sort:  bson.M{consts.CreateTime: -1},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err = m.conn.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+// FindOverdue 查找已超过支付期限但仍处于待支付状态的订单，activityId 为空时不限活动
+func (m *MongoMapper) FindOverdue(ctx context.Context, activityId string, now time.Time) (orders []*Order, err error) {
+	filter := bson.M{
+		consts.Status:     StatusPending,
+		consts.ExpireTime: bson.M{"$lte": now},
+	}
+	if activityId != "" {
+		filter[consts.ActivityId] = activityId
+	}
+	orders = make([]*Order, 0)
+	err = m.conn.Find(ctx, &orders, filter)
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// Transit 仅当订单当前状态属于 from 时才切换为 to，返回是否切换成功，用于避免支付回调与超时关闭的并发冲突
+func (m *MongoMapper) Transit(ctx context.Context, id primitive.ObjectID, from []string, to string, fields bson.M) (bool, error) {
+	set := bson.M{
+		consts.Status:     to,
+		consts.UpdateTime: time.Now(),
+	}
+	for k, v := range fields {
+		set[k] = v
+	}
+	result, err := m.conn.UpdateOneNoCache(ctx, bson.M{
+		consts.ID:     id,
+		consts.Status: bson.M{"$in": from},
+	}, bson.M{"$set": set})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// ReassignUser 将归属于 from 用户的订单全部转移给 to 用户，用于账号合并
+func (m *MongoMapper) ReassignUser(ctx context.Context, from, to string) (int64, error) {
+	result, err := m.conn.UpdateManyNoCache(ctx, bson.M{consts.UserID: from}, bson.M{
+		"$set": bson.M{
+			consts.UserID:     to,
+			consts.UpdateTime: time.Now(),
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// FindActiveTicketIds 返回活动中存在已支付或未超时的待支付订单的票种 id
+func (m *MongoMapper) FindActiveTicketIds(ctx context.Context, activityId string, now time.Time) (map[string]bool, error) {
+	values, err := m.conn.Distinct(ctx, "ticket_id", bson.M{
+		consts.ActivityId: activityId,
+		"$or": []bson.M{
+			{consts.Status: StatusPaid},
+			{consts.Status: StatusPending, consts.ExpireTime: bson.M{"$gt": now}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool, len(values))
+	for _, v := range values {
+		if id, ok := v.(string); ok {
+			ids[id] = true
+		}
+	}
+	return ids, nil
+}
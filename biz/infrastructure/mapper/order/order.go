@@ -0,0 +1,35 @@
+package order
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	StatusPending  = "pending"
+	StatusPaid     = "paid"
+	StatusRefunded = "refunded"
+	StatusExpired  = "expired"
+)
+
+// Order 活动报名订单，一个订单对应一次报名提交中的全部报名记录
+type Order struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ActivityId    string             `bson:"activity_id" json:"activityId"`
+	UserId        string             `bson:"user_id" json:"userId"`
+	TicketId      string             `bson:"ticket_id" json:"ticketId"`
+	TicketName    string             `bson:"ticket_name" json:"ticketName"`
+	UnitPrice     int64              `bson:"unit_price" json:"unitPrice"`
+	Quantity      int64              `bson:"quantity" json:"quantity"`
+	Amount        int64              `bson:"amount" json:"amount"`
+	Status        string             `bson:"status" json:"status"`
+	RegisterIds   []string           `bson:"register_ids" json:"registerIds"`
+	TransactionId string             `bson:"transaction_id,omitempty" json:"transactionId"`
+	RefundReason  string             `bson:"refund_reason,omitempty" json:"refundReason"`
+	ExpireTime    time.Time          `bson:"expire_time" json:"expireTime"`
+	PaidTime      time.Time          `bson:"paid_time,omitempty" json:"paidTime"`
+	RefundTime    time.Time          `bson:"refund_time,omitempty" json:"refundTime"`
+	CreateTime    time.Time          `bson:"create_time,omitempty" json:"createTime"`
+	UpdateTime    time.Time          `bson:"update_time,omitempty" json:"updateTime"`
+}
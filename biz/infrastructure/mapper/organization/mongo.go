@@ -0,0 +1,132 @@
+package organization
+
+import (
+	"context"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/zeromicro/go-zero/core/stores/monc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	prefixKeyCacheKey = "cache:organization"
+	CollectionName    = "organization"
+)
+
+type IMongoMapper interface {
+	Insert(ctx context.Context, o *Organization) error
+	Update(ctx context.Context, o *Organization) error
+	FindByID(ctx context.Context, id string) (*Organization, error)
+	FindByIDs(ctx context.Context, ids []string) ([]*Organization, error)
+	FindMany(ctx context.Context, filter bson.M, skip, limit int64) ([]*Organization, int64, error)
+	FindManagedBy(ctx context.Context, userId string) ([]*Organization, error)
+	FindByMember(ctx context.Context, userId string) ([]*Organization, error)
+}
+
+type MongoMapper struct {
+	conn *monc.Model
+}
+
+func NewMongoMapper(config *config.Config) *MongoMapper {
+	conn := monc.MustNewModel(config.Mongo.URL, config.Mongo.DB, CollectionName, config.Cache)
+	return &MongoMapper{conn: conn}
+}
+
+func (m *MongoMapper) Insert(ctx context.Context, o *Organization) error {
+	if o.ID.IsZero() {
+		o.ID = primitive.NewObjectID()
+	}
+	o.CreateTime = time.Now()
+	o.UpdateTime = o.CreateTime
+	key := prefixKeyCacheKey + o.ID.Hex()
+	_, err := m.conn.InsertOne(ctx, key, o)
+	return err
+}
+
+func (m *MongoMapper) Update(ctx context.Context, o *Organization) error {
+	o.UpdateTime = time.Now()
+	_, err := m.conn.UpdateByIDNoCache(ctx, o.ID, bson.M{"$set": o})
+	return err
+}
+
+func (m *MongoMapper) FindByID(ctx context.Context, id string) (*Organization, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, consts.ErrInvalidObjectId
+	}
+	var o Organization
+	err = m.conn.FindOneNoCache(ctx, &o, bson.M{consts.ID: oid})
+	if err != nil {
+		return nil, consts.ErrNotFound
+	}
+	return &o, nil
+}
+
+// FindByIDs 按 id 批量查询未删除的组织，非法 id 直接忽略
+func (m *MongoMapper) FindByIDs(ctx context.Context, ids []string) ([]*Organization, error) {
+	oids := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+	orgs := make([]*Organization, 0, len(oids))
+	if len(oids) == 0 {
+		return orgs, nil
+	}
+	err := m.conn.Find(ctx, &orgs, bson.M{
+		consts.ID:     bson.M{"$in": oids},
+		consts.Status: bson.M{"$ne": int64(consts.DeleteStatus)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+func (m *MongoMapper) FindMany(ctx context.Context, filter bson.M, skip, limit int64) ([]*Organization, int64, error) {
+	orgs := make([]*Organization, 0, limit)
+	err := m.conn.Find(ctx, &orgs, filter, &options.FindOptions{
+		Skip:  &skip,
+		Limit: &limit,
+		Sort:  bson.M{consts.CreateTime: -1},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := m.conn.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return orgs, total, nil
+}
+
+// FindManagedBy 查询用户担任管理者的未删除组织
+func (m *MongoMapper) FindManagedBy(ctx context.Context, userId string) ([]*Organization, error) {
+	orgs := make([]*Organization, 0)
+	err := m.conn.Find(ctx, &orgs, bson.M{
+		consts.Status: bson.M{"$ne": int64(consts.DeleteStatus)},
+		"members": bson.M{"$elemMatch": bson.M{
+			consts.UserID: userId,
+			"role":        RoleManager,
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+// FindByMember 查询用户所在的全部组织，包括已删除的组织
+func (m *MongoMapper) FindByMember(ctx context.Context, userId string) ([]*Organization, error) {
+	orgs := make([]*Organization, 0)
+	err := m.conn.Find(ctx, &orgs, bson.M{"members." + consts.UserID: userId})
+	if err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
@@ -0,0 +1,32 @@
+package organization
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	RoleManager = "manager"
+	RoleMember  = "member"
+)
+
+// Organization 主办/协办组织，如地区分会、行业协会
+type Organization struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"`
+	Logo        string             `bson:"logo" json:"logo"`
+	Description string             `bson:"description" json:"description"`
+	Contact     string             `bson:"contact" json:"contact"`
+	Members     []Member           `bson:"members" json:"members"`
+	Status      int64              `bson:"status" json:"status"`
+	CreateTime  time.Time          `bson:"create_time,omitempty" json:"createTime"`
+	UpdateTime  time.Time          `bson:"update_time,omitempty" json:"updateTime"`
+	DeleteTime  time.Time          `bson:"delete_time,omitempty" json:"deleteTime"`
+}
+
+type Member struct {
+	UserId   string    `bson:"user_id" json:"userId"`
+	Role     string    `bson:"role" json:"role"`
+	JoinTime time.Time `bson:"join_time" json:"joinTime"`
+}
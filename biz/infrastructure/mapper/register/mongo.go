@@ -27,8 +27,12 @@ type IMongoMapper interface {
 	FindMany(ctx context.Context, activityId string, p *basic.PaginationOptions) (registers []*Register, total int64, err error)
 	FindManyByFilter(ctx context.Context, filter bson.M, skip, limit int64) (registers []*Register, total int64, err error)
 	Count(ctx context.Context, activityId string) (count int64, err error)
+	CountByFilter(ctx context.Context, filter bson.M) (count int64, err error)
+	UpdatePayStatusByOrder(ctx context.Context, orderId string, payStatus string) error
 	FindAll(ctx context.Context, activityId string) (registers []*Register, total int64, err error)
 	FindByAidAndUid(ctx context.Context, activityId, uid string) (registers []*Register, total int64, err error)
+	ReassignUser(ctx context.Context, from, to string) (int64, error)
+	DetachUser(ctx context.Context, userId, name string) (int64, error)
 }
 
 type MongoMapper struct {
@@ -161,6 +165,22 @@ func (m *MongoMapper) Count(ctx context.Context, activityId string) (count int64
 	return count, err
 }
 
+func (m *MongoMapper) CountByFilter(ctx context.Context, filter bson.M) (count int64, err error) {
+	return m.conn.CountDocuments(ctx, filter)
+}
+
+func (m *MongoMapper) UpdatePayStatusByOrder(ctx context.Context, orderId string, payStatus string) error {
+	_, err := m.conn.UpdateManyNoCache(ctx, bson.M{
+		consts.OrderId: orderId,
+	}, bson.M{
+		"$set": bson.M{
+			consts.PayStatus:  payStatus,
+			consts.UpdateTime: time.Now(),
+		},
+	})
+	return err
+}
+
 func (m *MongoMapper) FindByAidAndUid(ctx context.Context, activityId, uid string) (registers []*Register, total int64, err error) {
 	registers = make([]*Register, 0)
 	err = m.conn.Find(ctx, &registers,
@@ -182,3 +202,33 @@ func (m *MongoMapper) FindByAidAndUid(ctx context.Context, activityId, uid strin
 	}
 	return registers, total, nil
 }
+
+// ReassignUser 将归属于 from 用户的报名记录全部转移给 to 用户，用于账号合并
+func (m *MongoMapper) ReassignUser(ctx context.Context, from, to string) (int64, error) {
+	result, err := m.conn.UpdateManyNoCache(ctx, bson.M{consts.UserID: from}, bson.M{
+		"$set": bson.M{
+			consts.UserID:     to,
+			consts.UpdateTime: time.Now(),
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// DetachUser 解除报名记录与用户的关联并抹去报名人姓名和手机号，报名人数统计不受影响，用于注销账号
+func (m *MongoMapper) DetachUser(ctx context.Context, userId, name string) (int64, error) {
+	result, err := m.conn.UpdateManyNoCache(ctx, bson.M{consts.UserID: userId}, bson.M{
+		"$set": bson.M{
+			consts.UserID:     "",
+			consts.Name:       name,
+			consts.Phone:      "",
+			consts.UpdateTime: time.Now(),
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
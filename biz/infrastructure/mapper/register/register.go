@@ -14,6 +14,8 @@ type Register struct {
 	CheckIn     bool               `bson:"check_in" json:"checkIn" `
 	CheckInTime time.Time          `bson:"check_in_time,omitempty" json:"checkInTime"`
 	Status      int64              `bson:"status" json:"status"`
+	OrderId     string             `bson:"order_id,omitempty" json:"orderId"`
+	PayStatus   string             `bson:"pay_status,omitempty" json:"payStatus"`
 	CreateTime  time.Time          `bson:"create_time" json:"createTime" `
 	UpdateTime  time.Time          `bson:"update_time" json:"updateTime" `
 	DeleteTime  time.Time          `bson:"delete_time,omitempty" json:"deleteTime"`
@@ -0,0 +1,77 @@
+package role
+
+import (
+	"context"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/zeromicro/go-zero/core/stores/monc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	prefixKeyCacheKey = "cache:role"
+	CollectionName    = "role"
+	key               = "key"
+)
+
+type IMongoMapper interface {
+	FindAll(ctx context.Context) ([]*Role, error)
+	FindByKeys(ctx context.Context, keys []string) ([]*Role, error)
+	Upsert(ctx context.Context, r *Role) error
+	DeleteByKey(ctx context.Context, key string) (bool, error)
+}
+
+type MongoMapper struct {
+	conn *monc.Model
+}
+
+func NewMongoMapper(config *config.Config) *MongoMapper {
+	conn := monc.MustNewModel(config.Mongo.URL, config.Mongo.DB, CollectionName, config.Cache)
+	return &MongoMapper{conn: conn}
+}
+
+func (m *MongoMapper) FindAll(ctx context.Context) ([]*Role, error) {
+	roles := make([]*Role, 0)
+	err := m.conn.Find(ctx, &roles, bson.M{}, &options.FindOptions{Sort: bson.M{consts.CreateTime: 1}})
+	if err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (m *MongoMapper) FindByKeys(ctx context.Context, keys []string) ([]*Role, error) {
+	roles := make([]*Role, 0, len(keys))
+	if len(keys) == 0 {
+		return roles, nil
+	}
+	err := m.conn.Find(ctx, &roles, bson.M{key: bson.M{"$in": keys}})
+	if err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// Upsert 按 Key 新建或覆盖角色定义
+func (m *MongoMapper) Upsert(ctx context.Context, r *Role) error {
+	now := time.Now()
+	_, err := m.conn.UpdateOneNoCache(ctx, bson.M{key: r.Key}, bson.M{
+		"$set": bson.M{
+			"name":            r.Name,
+			"permissions":     r.Permissions,
+			consts.UpdateTime: now,
+		},
+		"$setOnInsert": bson.M{consts.CreateTime: now},
+	}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (m *MongoMapper) DeleteByKey(ctx context.Context, k string) (bool, error) {
+	deleted, err := m.conn.DeleteOneNoCache(ctx, bson.M{key: k})
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
@@ -0,0 +1,17 @@
+package role
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Role 后台角色，即一组权限的命名集合，Key 在全部角色中唯一
+type Role struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Key         string             `bson:"key" json:"key"`
+	Name        string             `bson:"name" json:"name"`
+	Permissions []string           `bson:"permissions" json:"permissions"`
+	CreateTime  time.Time          `bson:"create_time,omitempty" json:"createTime"`
+	UpdateTime  time.Time          `bson:"update_time,omitempty" json:"updateTime"`
+}
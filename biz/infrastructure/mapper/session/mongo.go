@@ -0,0 +1,123 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/zeromicro/go-zero/core/stores/monc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	prefixKeyCacheKey = "cache:session"
+	CollectionName    = "session"
+)
+
+type IMongoMapper interface {
+	Insert(ctx context.Context, s *Session) error
+	FindByID(ctx context.Context, id string) (*Session, error)
+	FindByRefreshHash(ctx context.Context, hash string) (*Session, error)
+	Rotate(ctx context.Context, id primitive.ObjectID, from, to string, expire time.Time) (bool, error)
+	Revoke(ctx context.Context, id primitive.ObjectID) error
+	RevokeByUser(ctx context.Context, userId string) (int64, error)
+}
+
+type MongoMapper struct {
+	conn *monc.Model
+}
+
+func NewMongoMapper(config *config.Config) *MongoMapper {
+	conn := monc.MustNewModel(config.Mongo.URL, config.Mongo.DB, CollectionName, config.Cache)
+	return &MongoMapper{conn: conn}
+}
+
+func (m *MongoMapper) Insert(ctx context.Context, s *Session) error {
+	if s.ID.IsZero() {
+		s.ID = primitive.NewObjectID()
+	}
+	s.CreateTime = time.Now()
+	s.UpdateTime = s.CreateTime
+	key := prefixKeyCacheKey + s.ID.Hex()
+	_, err := m.conn.InsertOne(ctx, key, s)
+	return err
+}
+
+func (m *MongoMapper) FindByID(ctx context.Context, id string) (*Session, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, consts.ErrInvalidObjectId
+	}
+	var s Session
+	err = m.conn.FindOneNoCache(ctx, &s, bson.M{consts.ID: oid})
+	switch {
+	case err == nil:
+		return &s, nil
+	case errors.Is(err, monc.ErrNotFound):
+		return nil, consts.ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindByRefreshHash 按当前或上一个刷新令牌的哈希查找会话
+func (m *MongoMapper) FindByRefreshHash(ctx context.Context, hash string) (*Session, error) {
+	var s Session
+	err := m.conn.FindOneNoCache(ctx, &s, bson.M{"$or": []bson.M{
+		{"refresh_hash": hash},
+		{"prev_refresh_hash": hash},
+	}})
+	if err != nil {
+		return nil, consts.ErrNotFound
+	}
+	return &s, nil
+}
+
+// Rotate 仅当刷新令牌仍为 from 且会话未吊销时替换为 to，返回是否替换成功，避免并发刷新签发两套令牌
+func (m *MongoMapper) Rotate(ctx context.Context, id primitive.ObjectID, from, to string, expire time.Time) (bool, error) {
+	result, err := m.conn.UpdateOneNoCache(ctx, bson.M{
+		consts.ID:      id,
+		"refresh_hash": from,
+		"revoke_time":  bson.M{"$exists": false},
+	}, bson.M{"$set": bson.M{
+		"refresh_hash":      to,
+		"prev_refresh_hash": from,
+		"expire_time":       expire,
+		consts.UpdateTime:   time.Now(),
+	}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (m *MongoMapper) Revoke(ctx context.Context, id primitive.ObjectID) error {
+	now := time.Now()
+	_, err := m.conn.UpdateOneNoCache(ctx, bson.M{
+		consts.ID:     id,
+		"revoke_time": bson.M{"$exists": false},
+	}, bson.M{"$set": bson.M{
+		"revoke_time":     now,
+		consts.UpdateTime: now,
+	}})
+	return err
+}
+
+// RevokeByUser 吊销用户全部未吊销的会话，返回吊销的数量
+func (m *MongoMapper) RevokeByUser(ctx context.Context, userId string) (int64, error) {
+	now := time.Now()
+	result, err := m.conn.UpdateManyNoCache(ctx, bson.M{
+		consts.UserID: userId,
+		"revoke_time": bson.M{"$exists": false},
+	}, bson.M{"$set": bson.M{
+		"revoke_time":     now,
+		consts.UpdateTime: now,
+	}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
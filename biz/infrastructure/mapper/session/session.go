@@ -0,0 +1,24 @@
+package session
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Session 登录会话，ID 即 access token 中的 SessionDeviceId；刷新令牌只保存哈希，每次刷新后轮换
+type Session struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserId          string             `bson:"user_id" json:"userId"`
+	RefreshHash     string             `bson:"refresh_hash" json:"-"`
+	PrevRefreshHash string             `bson:"prev_refresh_hash,omitempty" json:"-"` // 上一个刷新令牌，被再次使用时视为泄露并吊销会话
+	ClientIP        string             `bson:"client_ip" json:"clientIp"`
+	ExpireTime      time.Time          `bson:"expire_time" json:"expireTime"` // 刷新令牌过期时间
+	RevokeTime      time.Time          `bson:"revoke_time,omitempty" json:"revokeTime"`
+	CreateTime      time.Time          `bson:"create_time,omitempty" json:"createTime"`
+	UpdateTime      time.Time          `bson:"update_time,omitempty" json:"updateTime"`
+}
+
+func (s *Session) Revoked() bool {
+	return !s.RevokeTime.IsZero()
+}
@@ -23,10 +23,38 @@ type IMongoMapper interface {
 	Update(ctx context.Context, user *User) error
 	FindOne(ctx context.Context, id string) (*User, error)
 	FindOneByPhone(ctx context.Context, phone string) (*User, error)
+	FindOneByWx(ctx context.Context, openId, unionId string) (*User, error)
+	FindOneByPlatformId(ctx context.Context, platformId string) (*User, error)
+	FindLiveByPhone(ctx context.Context, phone string, exclude primitive.ObjectID) (*User, error)
 	FindMany(ctx context.Context, filter bson.M, skip, limit int64) ([]*User, int64, error)
 	SoftDeleteByID(ctx context.Context, id primitive.ObjectID) error
+	FindDueDeletions(ctx context.Context, now time.Time, limit int64) ([]*User, error)
+	UpdateSignInGuard(ctx context.Context, id primitive.ObjectID, guard SignInGuard) error
+	UpdatePhone(ctx context.Context, id primitive.ObjectID, phone, platformId string) error
+	UpdateWx(ctx context.Context, id primitive.ObjectID, openId, unionId string) error
+	MarkMerged(ctx context.Context, id primitive.ObjectID, into string) error
+	MovePlatformIds(ctx context.Context, from, to primitive.ObjectID, platformIds []string) error
+	FillEmpty(ctx context.Context, id primitive.ObjectID, values map[string]string) error
+	UpdateProfile(ctx context.Context, u *User) error
+	UpdateMergedProfile(ctx context.Context, u *User) error
+	UpdatePrivacy(ctx context.Context, id primitive.ObjectID, privacy map[string]string) error
+	UpdateDirectory(ctx context.Context, id primitive.ObjectID, directory Directory) error
+	UpdateMentor(ctx context.Context, id primitive.ObjectID, mentor Mentor) error
+	UpdateRole(ctx context.Context, id primitive.ObjectID, role string) error
+	UpdateAdminRoles(ctx context.Context, id primitive.ObjectID, roles []string) error
+	UpdateStatus(ctx context.Context, id primitive.ObjectID, status int64) error
+	Restore(ctx context.Context, id primitive.ObjectID) error
+	UpdateDeletion(ctx context.Context, id primitive.ObjectID, deletion Deletion) error
+	UpdateTokenValidAfter(ctx context.Context, id primitive.ObjectID, t time.Time) error
+	DistinctEmployments(ctx context.Context, field string) ([]string, error)
+	FindAfter(ctx context.Context, filter bson.M, after primitive.ObjectID, limit int64) ([]*User, error)
+	UpdateEducations(ctx context.Context, id primitive.ObjectID, home, shanghai []Education) error
+	UpdateEmployments(ctx context.Context, id primitive.ObjectID, employments []Employment) error
+	UpdateHometown(ctx context.Context, id primitive.ObjectID, hometown, code string) error
+	CountByHometown(ctx context.Context, filter bson.M) (map[string]int64, error)
 }
 
+// MongoMapper 中 Update 以外的更新方法只写入各自负责的字段，避免覆盖并发修改的其他字段
 type MongoMapper struct {
 	conn *monc.Model
 }
@@ -66,11 +94,14 @@ func (m *MongoMapper) FindOne(ctx context.Context, id string) (*User, error) {
 	err = m.conn.FindOneNoCache(ctx, &u, bson.M{
 		consts.ID: oid,
 	})
-	if err != nil {
+	switch {
+	case err == nil:
+		return &u, nil
+	case errors.Is(err, monc.ErrNotFound):
 		return nil, consts.ErrNotFound
+	default:
+		return nil, err
 	}
-	return &u, nil
-
 }
 
 func (m *MongoMapper) FindOneByPhone(ctx context.Context, phone string) (*User, error) {
@@ -88,6 +119,63 @@ func (m *MongoMapper) FindOneByPhone(ctx context.Context, phone string) (*User,
 	}
 }
 
+// FindLiveByPhone 查找绑定该手机号的正常状态用户，exclude 非空时排除该用户
+func (m *MongoMapper) FindLiveByPhone(ctx context.Context, phone string, exclude primitive.ObjectID) (*User, error) {
+	filter := bson.M{
+		consts.Phone: phone,
+		"$and": []bson.M{
+			{"$or": []bson.M{{consts.Status: int64(0)}, {consts.Status: bson.M{"$exists": false}}}},
+			{"$or": []bson.M{{consts.DeleteTime: bson.M{"$exists": false}}, {consts.DeleteTime: time.Time{}}}},
+		},
+	}
+	if !exclude.IsZero() {
+		filter[consts.ID] = bson.M{"$ne": exclude}
+	}
+	var u User
+	err := m.conn.FindOneNoCache(ctx, &u, filter)
+	switch {
+	case err == nil:
+		return &u, nil
+	case errors.Is(err, monc.ErrNotFound):
+		return nil, consts.ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindOneByPlatformId 按 PlatformIds 查找用户
+func (m *MongoMapper) FindOneByPlatformId(ctx context.Context, platformId string) (*User, error) {
+	var u User
+	err := m.conn.FindOneNoCache(ctx, &u, bson.M{"platform_ids": platformId})
+	switch {
+	case err == nil:
+		return &u, nil
+	case errors.Is(err, monc.ErrNotFound):
+		return nil, consts.ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindOneByWx 按 openid 查找用户，未找到且 unionId 非空时按 unionid 查找
+func (m *MongoMapper) FindOneByWx(ctx context.Context, openId, unionId string) (*User, error) {
+	filters := []bson.M{{"wx_open_id": openId}}
+	if unionId != "" {
+		filters = append(filters, bson.M{"wx_union_id": unionId})
+	}
+	for _, filter := range filters {
+		var u User
+		err := m.conn.FindOneNoCache(ctx, &u, filter)
+		switch {
+		case err == nil:
+			return &u, nil
+		case !errors.Is(err, monc.ErrNotFound):
+			return nil, err
+		}
+	}
+	return nil, consts.ErrNotFound
+}
+
 func (m *MongoMapper) SoftDeleteByID(ctx context.Context, id primitive.ObjectID) error {
 	now := time.Now()
 	_, err := m.conn.UpdateByIDNoCache(ctx, id, bson.M{"$set": bson.M{
@@ -114,3 +202,254 @@ func (m *MongoMapper) FindMany(ctx context.Context, filter bson.M, skip, limit i
 	}
 	return users, total, nil
 }
+
+// FindDueDeletions 查询注销冷静期已结束、尚未匿名化的用户
+func (m *MongoMapper) FindDueDeletions(ctx context.Context, now time.Time, limit int64) ([]*User, error) {
+	users := make([]*User, 0, limit)
+	err := m.conn.Find(ctx, &users, bson.M{
+		"deletion.due_time": bson.M{"$gt": time.Time{}, "$lte": now},
+		"deletion.purged":   bson.M{"$ne": true},
+	}, &options.FindOptions{
+		Limit: &limit,
+		Sort:  bson.M{"deletion.due_time": 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// UpdateSignInGuard 只更新登录失败记录
+func (m *MongoMapper) UpdateSignInGuard(ctx context.Context, id primitive.ObjectID, guard SignInGuard) error {
+	_, err := m.conn.UpdateByIDNoCache(ctx, id, bson.M{"$set": bson.M{"sign_in_guard": guard}})
+	return err
+}
+
+// UpdateTokenValidAfter 使早于 t 签发的令牌全部失效
+func (m *MongoMapper) UpdateTokenValidAfter(ctx context.Context, id primitive.ObjectID, t time.Time) error {
+	_, err := m.conn.UpdateByIDNoCache(ctx, id, bson.M{"$set": bson.M{"token_valid_after": t}})
+	return err
+}
+
+// UpdatePhone 修改手机号，platformId 非空时记入 PlatformIds
+func (m *MongoMapper) UpdatePhone(ctx context.Context, id primitive.ObjectID, phone, platformId string) error {
+	update := bson.M{"$set": bson.M{consts.Phone: phone, consts.UpdateTime: time.Now()}}
+	if platformId != "" {
+		update["$addToSet"] = bson.M{"platform_ids": platformId}
+	}
+	_, err := m.conn.UpdateByIDNoCache(ctx, id, update)
+	return err
+}
+
+// UpdateWx 绑定微信 openid 与 unionid
+func (m *MongoMapper) UpdateWx(ctx context.Context, id primitive.ObjectID, openId, unionId string) error {
+	_, err := m.conn.UpdateByIDNoCache(ctx, id, bson.M{"$set": bson.M{
+		"wx_open_id":      openId,
+		"wx_union_id":     unionId,
+		consts.UpdateTime: time.Now(),
+	}})
+	return err
+}
+
+// MarkMerged 将账号软删除并指向保留的 into 账号，之后以该账号登录时转到保留的账号
+func (m *MongoMapper) MarkMerged(ctx context.Context, id primitive.ObjectID, into string) error {
+	now := time.Now()
+	_, err := m.conn.UpdateByIDNoCache(ctx, id, bson.M{"$set": bson.M{
+		"merged_into":     into,
+		consts.Status:     int64(1),
+		consts.DeleteTime: now,
+		consts.UpdateTime: now,
+	}})
+	return err
+}
+
+// MovePlatformIds 将 from 账号记录的中台账号转给 to 账号，用于账号合并
+func (m *MongoMapper) MovePlatformIds(ctx context.Context, from, to primitive.ObjectID, platformIds []string) error {
+	if len(platformIds) == 0 {
+		return nil
+	}
+	if _, err := m.conn.UpdateByIDNoCache(ctx, to, bson.M{
+		"$addToSet": bson.M{"platform_ids": bson.M{"$each": platformIds}},
+		"$set":      bson.M{consts.UpdateTime: time.Now()},
+	}); err != nil {
+		return err
+	}
+	_, err := m.conn.UpdateByIDNoCache(ctx, from, bson.M{"$pull": bson.M{"platform_ids": bson.M{"$in": platformIds}}})
+	return err
+}
+
+// FillEmpty 逐个写入 values 中的字段，只写入当前为空的字段，不覆盖已有的取值
+func (m *MongoMapper) FillEmpty(ctx context.Context, id primitive.ObjectID, values map[string]string) error {
+	for field, value := range values {
+		_, err := m.conn.UpdateOneNoCache(ctx, bson.M{
+			consts.ID: id,
+			field:     bson.M{"$in": []any{"", nil}},
+		}, bson.M{"$set": bson.M{field: value, consts.UpdateTime: time.Now()}})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateProfile 更新头像、姓名、性别、生日、微信号与家乡
+func (m *MongoMapper) UpdateProfile(ctx context.Context, u *User) error {
+	_, err := m.conn.UpdateByIDNoCache(ctx, u.ID, bson.M{"$set": bson.M{
+		"avatar":          u.Avatar,
+		consts.Name:       u.Name,
+		"gender":          u.Gender,
+		"birthday":        u.Birthday,
+		"wx_id":           u.WxId,
+		"hometown":        u.Hometown,
+		"hometown_code":   u.HometownCode,
+		consts.UpdateTime: time.Now(),
+	}})
+	return err
+}
+
+// UpdateMergedProfile 写入账号合并时可能变化的全部字段：资料、手机号、教育与工作经历、角色
+func (m *MongoMapper) UpdateMergedProfile(ctx context.Context, u *User) error {
+	_, err := m.conn.UpdateByIDNoCache(ctx, u.ID, bson.M{"$set": bson.M{
+		"avatar":              u.Avatar,
+		consts.Name:           u.Name,
+		"gender":              u.Gender,
+		"birthday":            u.Birthday,
+		consts.Phone:          u.Phone,
+		"wx_id":               u.WxId,
+		"hometown":            u.Hometown,
+		"hometown_code":       u.HometownCode,
+		"home_educations":     u.HomeEducations,
+		"shanghai_educations": u.ShanghaiEducations,
+		"employments":         u.Employments,
+		"role":                u.Role,
+		"admin_roles":         u.AdminRoles,
+		consts.UpdateTime:     time.Now(),
+	}})
+	return err
+}
+
+func (m *MongoMapper) UpdatePrivacy(ctx context.Context, id primitive.ObjectID, privacy map[string]string) error {
+	return m.set(ctx, id, "privacy", privacy)
+}
+
+func (m *MongoMapper) UpdateDirectory(ctx context.Context, id primitive.ObjectID, directory Directory) error {
+	return m.set(ctx, id, "directory", directory)
+}
+
+func (m *MongoMapper) UpdateMentor(ctx context.Context, id primitive.ObjectID, mentor Mentor) error {
+	return m.set(ctx, id, "mentor", mentor)
+}
+
+func (m *MongoMapper) UpdateRole(ctx context.Context, id primitive.ObjectID, role string) error {
+	return m.set(ctx, id, "role", role)
+}
+
+func (m *MongoMapper) UpdateAdminRoles(ctx context.Context, id primitive.ObjectID, roles []string) error {
+	return m.set(ctx, id, "admin_roles", roles)
+}
+
+func (m *MongoMapper) UpdateStatus(ctx context.Context, id primitive.ObjectID, status int64) error {
+	return m.set(ctx, id, consts.Status, status)
+}
+
+func (m *MongoMapper) UpdateDeletion(ctx context.Context, id primitive.ObjectID, deletion Deletion) error {
+	return m.set(ctx, id, "deletion", deletion)
+}
+
+// Restore 恢复被停用或软删除的账号
+func (m *MongoMapper) Restore(ctx context.Context, id primitive.ObjectID) error {
+	_, err := m.conn.UpdateByIDNoCache(ctx, id, bson.M{
+		"$set":   bson.M{consts.Status: int64(0), consts.UpdateTime: time.Now()},
+		"$unset": bson.M{consts.DeleteTime: ""},
+	})
+	return err
+}
+
+func (m *MongoMapper) set(ctx context.Context, id primitive.ObjectID, field string, value any) error {
+	_, err := m.conn.UpdateByIDNoCache(ctx, id, bson.M{"$set": bson.M{field: value, consts.UpdateTime: time.Now()}})
+	return err
+}
+
+// DistinctEmployments 返回正常用户工作经历中某个字段（organization、industry）的全部取值
+func (m *MongoMapper) DistinctEmployments(ctx context.Context, field string) ([]string, error) {
+	values, err := m.conn.Distinct(ctx, "employments."+field, bson.M{
+		"$and": []bson.M{
+			{"$or": []bson.M{{consts.Status: int64(0)}, {consts.Status: bson.M{"$exists": false}}}},
+			{"$or": []bson.M{{consts.DeleteTime: bson.M{"$exists": false}}, {consts.DeleteTime: time.Time{}}}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if str, ok := value.(string); ok && str != "" {
+			result = append(result, str)
+		}
+	}
+	return result, nil
+}
+
+// FindAfter 按 _id 升序查询 after 之后的用户，供批量任务分批遍历
+func (m *MongoMapper) FindAfter(ctx context.Context, filter bson.M, after primitive.ObjectID, limit int64) ([]*User, error) {
+	cond := bson.M{}
+	for k, v := range filter {
+		cond[k] = v
+	}
+	if !after.IsZero() {
+		cond[consts.ID] = bson.M{"$gt": after}
+	}
+	users := make([]*User, 0, limit)
+	err := m.conn.Find(ctx, &users, cond, &options.FindOptions{
+		Limit: &limit,
+		Sort:  bson.M{consts.ID: 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// UpdateEducations 只更新教育经历
+func (m *MongoMapper) UpdateEducations(ctx context.Context, id primitive.ObjectID, home, shanghai []Education) error {
+	_, err := m.conn.UpdateByIDNoCache(ctx, id, bson.M{"$set": bson.M{
+		"home_educations":     home,
+		"shanghai_educations": shanghai,
+	}})
+	return err
+}
+
+// UpdateEmployments 只更新工作经历
+func (m *MongoMapper) UpdateEmployments(ctx context.Context, id primitive.ObjectID, employments []Employment) error {
+	_, err := m.conn.UpdateByIDNoCache(ctx, id, bson.M{"$set": bson.M{"employments": employments}})
+	return err
+}
+
+// UpdateHometown 只更新家乡文字和行政区划代码
+func (m *MongoMapper) UpdateHometown(ctx context.Context, id primitive.ObjectID, hometown, code string) error {
+	_, err := m.conn.UpdateByIDNoCache(ctx, id, bson.M{"$set": bson.M{
+		"hometown":      hometown,
+		"hometown_code": code,
+	}})
+	return err
+}
+
+// CountByHometown 按家乡行政区划代码统计用户数量，未填写代码的用户不计入
+func (m *MongoMapper) CountByHometown(ctx context.Context, filter bson.M) (map[string]int64, error) {
+	var rows []struct {
+		Code  string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	err := m.conn.Aggregate(ctx, &rows, []bson.M{
+		{"$match": bson.M{"$and": []bson.M{filter, {"hometown_code": bson.M{"$nin": []any{nil, ""}}}}}},
+		{"$group": bson.M{"_id": "$hometown_code", "count": bson.M{"$sum": 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Code] = row.Count
+	}
+	return counts, nil
+}
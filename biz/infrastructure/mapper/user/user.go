@@ -13,27 +13,112 @@ type User struct {
 	Birthday           time.Time          `bson:"birthday" json:"birthday"`
 	Phone              string             `bson:"phone" json:"phone"`
 	WxId               string             `bson:"wx_id" json:"wxId"`
+	WxOpenId           string             `bson:"wx_open_id,omitempty" json:"wxOpenId"` // 小程序 openid，用于微信登录
+	WxUnionId          string             `bson:"wx_union_id,omitempty" json:"wxUnionId"`
 	Hometown           string             `bson:"hometown" json:"hometown"`
+	HometownCode       string             `bson:"hometown_code,omitempty" json:"hometownCode"` // GB/T 2260 行政区划代码，Hometown 为对应的名称或用户填写的文字
 	HomeEducations     []Education        `bson:"home_educations" json:"homeEducations"`
 	ShanghaiEducations []Education        `bson:"shanghai_educations" json:"shanghaiEducations"`
 	Employments        []Employment       `bson:"employments" json:"employments"`
 	Role               string             `bson:"role" json:"role"`
+	AdminRoles         []string           `bson:"admin_roles" json:"adminRoles"` // 后台角色，Role 为 admin 时视为超级管理员
 	Status             int64              `bson:"status" json:"status"`
+	Directory          Directory          `bson:"directory" json:"directory"`
+	Mentor             Mentor             `bson:"mentor" json:"mentor"`
+	Privacy            map[string]string  `bson:"privacy" json:"privacy"`
+	MergedInto         string             `bson:"merged_into,omitempty" json:"mergedInto"`   // 账号被合并后指向保留的账号
+	PlatformIds        []string           `bson:"platform_ids,omitempty" json:"platformIds"` // 修改手机号后新手机号在中台对应的账号，登录时同样指向本账号
+	Deletion           Deletion           `bson:"deletion" json:"deletion"`
+	TokenValidAfter    time.Time          `bson:"token_valid_after,omitempty" json:"tokenValidAfter"` // 早于该时间签发的令牌一律失效
+	SignInGuard        SignInGuard        `bson:"sign_in_guard" json:"signInGuard"`
 	CreateTime         time.Time          `bson:"create_time,omitempty" json:"createTime"`
 	UpdateTime         time.Time          `bson:"update_time,omitempty" json:"updateTime"`
 	DeleteTime         time.Time          `bson:"delete_time,omitempty" json:"deleteTime"`
 }
 
+// 受隐私设置控制的字段分组
+const (
+	FieldHometown    = "hometown"
+	FieldEducations  = "educations"
+	FieldEmployments = "employments"
+	FieldPhone       = "phone"
+	FieldWxId        = "wxId"
+	FieldBirthday    = "birthday"
+)
+
+// PrivacyFields 全部受隐私设置控制的字段分组
+var PrivacyFields = []string{FieldHometown, FieldEducations, FieldEmployments, FieldPhone, FieldWxId, FieldBirthday}
+
+// 字段可见范围：仅自己、认证校友、所有人
+const (
+	PrivacyPrivate = "private"
+	PrivacyAlumni  = "alumni"
+	PrivacyPublic  = "public"
+)
+
+// PrivacyLevel 返回字段分组的可见范围，未设置时联系方式与生日默认仅自己可见，其余默认认证校友可见
+func (u *User) PrivacyLevel(field string) string {
+	if level, ok := u.Privacy[field]; ok && level != "" {
+		return level
+	}
+	return DefaultPrivacyLevel(field)
+}
+
+func DefaultPrivacyLevel(field string) string {
+	switch field {
+	case FieldPhone, FieldWxId, FieldBirthday:
+		return PrivacyPrivate
+	default:
+		return PrivacyAlumni
+	}
+}
+
+// Directory 校友通讯录设置，Listed 为 false 时不出现在检索结果中
+type Directory struct {
+	Listed bool `bson:"listed" json:"listed"`
+}
+
+// Mentor 导师设置，Enabled 为 true 时出现在导师推荐中，Capacity 为同时进行中的指导数量上限
+type Mentor struct {
+	Enabled  bool     `bson:"enabled" json:"enabled"`
+	Topics   []string `bson:"topics" json:"topics"`
+	Capacity int64    `bson:"capacity" json:"capacity"`
+	Intro    string   `bson:"intro" json:"intro"`
+}
+
+// Deletion 账号注销申请，冷静期内可撤销，期满后个人信息被匿名化，Purged 标记已完成匿名化
+type Deletion struct {
+	RequestTime time.Time `bson:"request_time" json:"requestTime"`
+	DueTime     time.Time `bson:"due_time" json:"dueTime"`
+	Purged      bool      `bson:"purged" json:"purged"`
+}
+
+// SignInGuard 登录失败记录，Failures 为当前连续失败次数，Lockouts 为已锁定的次数，用于计算下一次锁定时长
+type SignInGuard struct {
+	Failures     int64     `bson:"failures" json:"failures"`
+	Lockouts     int64     `bson:"lockouts" json:"lockouts"`
+	LastFailTime time.Time `bson:"last_fail_time" json:"lastFailTime"`
+	LockedUntil  time.Time `bson:"locked_until" json:"lockedUntil"`
+}
+
+func (g SignInGuard) Locked(now time.Time) bool {
+	return g.LockedUntil.After(now)
+}
+
+// Education SchoolId 为匹配到的学校字典条目，School 保留用户填写的文字
 type Education struct {
-	Phase  string `bson:"phase" json:"phase"`
-	School string `bson:"school" json:"school"`
-	Year   int64  `bson:"year" json:"year"`
+	Phase    string `bson:"phase" json:"phase"`
+	School   string `bson:"school" json:"school"`
+	SchoolId string `bson:"school_id,omitempty" json:"schoolId"`
+	Year     int64  `bson:"year" json:"year"`
 }
 
+// Employment IndustryId 为匹配到的行业字典条目，Industry 保留用户填写的文字
 type Employment struct {
 	Organization string `bson:"organization" json:"organization"`
 	Position     string `bson:"position" json:"position"`
 	Industry     string `bson:"industry" json:"industry"`
+	IndustryId   string `bson:"industry_id,omitempty" json:"industryId"`
 	Entry        int64  `bson:"entry" json:"entry"`
 	Departure    int64  `bson:"departure" json:"departure"`
 }
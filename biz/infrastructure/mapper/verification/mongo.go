@@ -0,0 +1,137 @@
+package verification
+
+import (
+	"context"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/zeromicro/go-zero/core/stores/monc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	prefixKeyCacheKey = "cache:verification"
+	CollectionName    = "verification"
+)
+
+type IMongoMapper interface {
+	Insert(ctx context.Context, v *Verification) error
+	FindByID(ctx context.Context, id string) (*Verification, error)
+	FindManyByFilter(ctx context.Context, filter bson.M, skip, limit int64) (verifications []*Verification, total int64, err error)
+	FindPendingByUser(ctx context.Context, userId string) (*Verification, error)
+	Review(ctx context.Context, id primitive.ObjectID, to, reason, reviewerId string) (bool, error)
+	ReassignUser(ctx context.Context, from, to string) (int64, error)
+	ClearProofs(ctx context.Context, userId string) (int64, error)
+}
+
+type MongoMapper struct {
+	conn *monc.Model
+}
+
+func NewMongoMapper(config *config.Config) *MongoMapper {
+	conn := monc.MustNewModel(config.Mongo.URL, config.Mongo.DB, CollectionName, config.Cache)
+	return &MongoMapper{conn: conn}
+}
+
+func (m *MongoMapper) Insert(ctx context.Context, v *Verification) error {
+	if v.ID.IsZero() {
+		v.ID = primitive.NewObjectID()
+	}
+	v.CreateTime = time.Now()
+	v.UpdateTime = v.CreateTime
+	key := prefixKeyCacheKey + v.ID.Hex()
+	_, err := m.conn.InsertOne(ctx, key, v)
+	return err
+}
+
+func (m *MongoMapper) FindByID(ctx context.Context, id string) (*Verification, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, consts.ErrInvalidObjectId
+	}
+	var v Verification
+	err = m.conn.FindOneNoCache(ctx, &v, bson.M{consts.ID: oid})
+	if err != nil {
+		return nil, consts.ErrNotFound
+	}
+	return &v, nil
+}
+
+func (m *MongoMapper) FindManyByFilter(ctx context.Context, filter bson.M, skip, limit int64) (verifications []*Verification, total int64, err error) {
+	verifications = make([]*Verification, 0, limit)
+	err = m.conn.Find(ctx, &verifications, filter, &options.FindOptions{
+		Skip:  &skip,
+		Limit: &limit,
+		Sort:  bson.M{consts.CreateTime: -1},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err = m.conn.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return verifications, total, nil
+}
+
+func (m *MongoMapper) FindPendingByUser(ctx context.Context, userId string) (*Verification, error) {
+	var v Verification
+	err := m.conn.FindOneNoCache(ctx, &v, bson.M{
+		consts.UserID: userId,
+		consts.Status: StatusPending,
+	})
+	if err != nil {
+		return nil, consts.ErrNotFound
+	}
+	return &v, nil
+}
+
+// Review 仅当申请仍待审核时写入审核结果，返回是否写入成功，避免重复审核
+func (m *MongoMapper) Review(ctx context.Context, id primitive.ObjectID, to, reason, reviewerId string) (bool, error) {
+	now := time.Now()
+	result, err := m.conn.UpdateOneNoCache(ctx, bson.M{
+		consts.ID:     id,
+		consts.Status: StatusPending,
+	}, bson.M{"$set": bson.M{
+		consts.Status:     to,
+		"reason":          reason,
+		"reviewer_id":     reviewerId,
+		"review_time":     now,
+		consts.UpdateTime: now,
+	}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// ReassignUser 将归属于 from 用户的认证申请全部转移给 to 用户，用于账号合并
+func (m *MongoMapper) ReassignUser(ctx context.Context, from, to string) (int64, error) {
+	result, err := m.conn.UpdateManyNoCache(ctx, bson.M{consts.UserID: from}, bson.M{
+		"$set": bson.M{
+			consts.UserID:     to,
+			consts.UpdateTime: time.Now(),
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// ClearProofs 清空用户全部认证申请的证明材料，审核结论保留，用于注销账号
+func (m *MongoMapper) ClearProofs(ctx context.Context, userId string) (int64, error) {
+	result, err := m.conn.UpdateManyNoCache(ctx, bson.M{consts.UserID: userId}, bson.M{
+		"$set": bson.M{
+			"proofs":          []string{},
+			consts.UpdateTime: time.Now(),
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
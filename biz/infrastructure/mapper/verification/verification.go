@@ -0,0 +1,28 @@
+package verification
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+// Verification 校友认证申请，审核结果连同审核人和审核时间一并记录，重新申请时新建一条记录
+type Verification struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserId     string             `bson:"user_id" json:"userId"`
+	School     string             `bson:"school" json:"school"`
+	Year       int64              `bson:"year" json:"year"`     // 毕业年份
+	Proofs     []string           `bson:"proofs" json:"proofs"` // 证明材料图片地址，经 STS 上传
+	Status     string             `bson:"status" json:"status"`
+	Reason     string             `bson:"reason,omitempty" json:"reason"` // 驳回理由
+	ReviewerId string             `bson:"reviewer_id,omitempty" json:"reviewerId"`
+	ReviewTime time.Time          `bson:"review_time,omitempty" json:"reviewTime"`
+	CreateTime time.Time          `bson:"create_time,omitempty" json:"createTime"`
+	UpdateTime time.Time          `bson:"update_time,omitempty" json:"updateTime"`
+}
@@ -0,0 +1,62 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+)
+
+// Fake 本地模拟支付，下单直接成功，回调报文为明文 JSON，不做签名校验，禁止在生产环境使用
+type Fake struct {
+	mu      sync.Mutex
+	Refunds []RefundReq
+}
+
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+func (f *Fake) Prepay(_ context.Context, req *PrepayReq) (*PrepayResp, error) {
+	return &PrepayResp{Params: map[string]string{
+		"provider": ProviderFake,
+		"orderNo":  req.OrderNo,
+		"amount":   fmt.Sprint(req.Amount),
+	}}, nil
+}
+
+func (f *Fake) Refund(_ context.Context, req *RefundReq) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Refunds = append(f.Refunds, *req)
+	return nil
+}
+
+// ParseNotify 解析形如 {"orderNo":"...","transactionId":"...","amount":100,"paid":true} 的模拟回调
+func (f *Fake) ParseNotify(_ context.Context, _ http.Header, body []byte) (*Notification, error) {
+	if cfg := config.GetConfig(); cfg == nil || !cfg.FakePayEnabled() {
+		return nil, errors.New("模拟支付未启用")
+	}
+	var n struct {
+		OrderNo       string `json:"orderNo"`
+		TransactionId string `json:"transactionId"`
+		Amount        int64  `json:"amount"`
+		Paid          bool   `json:"paid"`
+	}
+	if err := json.Unmarshal(body, &n); err != nil {
+		return nil, fmt.Errorf("解析模拟支付回调失败: %w", err)
+	}
+	if n.TransactionId == "" {
+		n.TransactionId = "fake-" + n.OrderNo
+	}
+	return &Notification{
+		OrderNo:       n.OrderNo,
+		TransactionId: n.TransactionId,
+		Amount:        n.Amount,
+		Paid:          n.Paid,
+	}, nil
+}
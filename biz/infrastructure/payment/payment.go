@@ -0,0 +1,68 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+)
+
+const (
+	ProviderWechat = "wechat"
+	ProviderFake   = "fake"
+)
+
+// IPaymentProvider 支付渠道抽象，订单流程只依赖该接口，便于替换为本地模拟实现
+type IPaymentProvider interface {
+	// Prepay 下单并返回客户端拉起支付所需的参数
+	Prepay(ctx context.Context, req *PrepayReq) (*PrepayResp, error)
+	// Refund 对已支付订单发起全额退款
+	Refund(ctx context.Context, req *RefundReq) error
+	// ParseNotify 校验并解析支付结果回调
+	ParseNotify(ctx context.Context, header http.Header, body []byte) (*Notification, error)
+}
+
+type PrepayReq struct {
+	OrderNo     string
+	Description string
+	Amount      int64 // 单位：分
+	OpenId      string
+	ExpireTime  time.Time
+}
+
+type PrepayResp struct {
+	Params map[string]string
+}
+
+type RefundReq struct {
+	OrderNo  string
+	RefundNo string
+	Amount   int64
+	Total    int64
+	Reason   string
+}
+
+type Notification struct {
+	OrderNo       string
+	TransactionId string
+	Amount        int64
+	Paid          bool
+}
+
+var PaymentSet = wire.NewSet(
+	NewPaymentProvider,
+)
+
+// NewPaymentProvider 模拟支付的回调不校验签名，生产环境配置为 fake 时拒绝启动
+func NewPaymentProvider(config *config.Config) (IPaymentProvider, error) {
+	if config.Pay.Provider == ProviderFake {
+		if !config.FakePayEnabled() {
+			return nil, fmt.Errorf("模拟支付仅允许在 dev、test、local 环境中使用, state=%s", config.State)
+		}
+		return NewFake(), nil
+	}
+	return NewWxPay(config), nil
+}
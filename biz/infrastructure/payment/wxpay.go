@@ -0,0 +1,282 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+)
+
+const (
+	wxPayHost       = "https://api.mch.weixin.qq.com"
+	wxPayJsapiPath  = "/v3/pay/transactions/jsapi"
+	wxPayRefundPath = "/v3/refund/domestic/refunds"
+	wxPayAuthSchema = "WECHATPAY2-SHA256-RSA2048"
+)
+
+// WxPay 微信支付 APIv3 JSAPI/小程序支付实现
+type WxPay struct {
+	client            *http.Client
+	appId             string
+	mchId             string
+	mchSerialNo       string
+	privateKey        string
+	apiV3Key          string
+	platformPublicKey string
+	notifyUrl         string
+}
+
+func NewWxPay(config *config.Config) *WxPay {
+	return &WxPay{
+		client:            &http.Client{Timeout: 10 * time.Second},
+		appId:             config.Wx.AppId,
+		mchId:             config.Pay.MchId,
+		mchSerialNo:       config.Pay.MchSerialNo,
+		privateKey:        config.Pay.PrivateKey,
+		apiV3Key:          config.Pay.ApiV3Key,
+		platformPublicKey: config.Pay.PlatformPublicKey,
+		notifyUrl:         config.Pay.NotifyUrl,
+	}
+}
+
+func (w *WxPay) Prepay(ctx context.Context, req *PrepayReq) (*PrepayResp, error) {
+	if req.OpenId == "" {
+		return nil, errors.New("微信支付缺少 openid")
+	}
+	body := map[string]any{
+		"appid":        w.appId,
+		"mchid":        w.mchId,
+		"description":  req.Description,
+		"out_trade_no": req.OrderNo,
+		"notify_url":   w.notifyUrl,
+		"amount":       map[string]any{"total": req.Amount, "currency": "CNY"},
+		"payer":        map[string]any{"openid": req.OpenId},
+	}
+	if !req.ExpireTime.IsZero() {
+		body["time_expire"] = req.ExpireTime.Format(time.RFC3339)
+	}
+	var result struct {
+		PrepayId string `json:"prepay_id"`
+	}
+	if err := w.call(ctx, http.MethodPost, wxPayJsapiPath, body, &result); err != nil {
+		return nil, err
+	}
+
+	key, err := w.signKey()
+	if err != nil {
+		return nil, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	nonce := nonceStr()
+	pkg := "prepay_id=" + result.PrepayId
+	paySign, err := sign(key, w.appId+"\n"+timestamp+"\n"+nonce+"\n"+pkg+"\n")
+	if err != nil {
+		return nil, err
+	}
+	return &PrepayResp{Params: map[string]string{
+		"appId":     w.appId,
+		"timeStamp": timestamp,
+		"nonceStr":  nonce,
+		"package":   pkg,
+		"signType":  "RSA",
+		"paySign":   paySign,
+	}}, nil
+}
+
+func (w *WxPay) Refund(ctx context.Context, req *RefundReq) error {
+	body := map[string]any{
+		"out_trade_no":  req.OrderNo,
+		"out_refund_no": req.RefundNo,
+		"reason":        req.Reason,
+		"amount":        map[string]any{"refund": req.Amount, "total": req.Total, "currency": "CNY"},
+	}
+	var result struct {
+		Status string `json:"status"`
+	}
+	if err := w.call(ctx, http.MethodPost, wxPayRefundPath, body, &result); err != nil {
+		return err
+	}
+	if result.Status == "CLOSED" || result.Status == "ABNORMAL" {
+		return fmt.Errorf("微信退款失败: %s", result.Status)
+	}
+	return nil
+}
+
+func (w *WxPay) ParseNotify(_ context.Context, header http.Header, body []byte) (*Notification, error) {
+	if err := w.verify(header, body); err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		EventType string `json:"event_type"`
+		Resource  struct {
+			Ciphertext     string `json:"ciphertext"`
+			AssociatedData string `json:"associated_data"`
+			Nonce          string `json:"nonce"`
+		} `json:"resource"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("解析微信支付回调失败: %w", err)
+	}
+	plain, err := w.decrypt(envelope.Resource.Ciphertext, envelope.Resource.AssociatedData, envelope.Resource.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	var transaction struct {
+		OutTradeNo    string `json:"out_trade_no"`
+		TransactionId string `json:"transaction_id"`
+		TradeState    string `json:"trade_state"`
+		Amount        struct {
+			Total int64 `json:"total"`
+		} `json:"amount"`
+	}
+	if err = json.Unmarshal(plain, &transaction); err != nil {
+		return nil, fmt.Errorf("解析微信支付交易信息失败: %w", err)
+	}
+	return &Notification{
+		OrderNo:       transaction.OutTradeNo,
+		TransactionId: transaction.TransactionId,
+		Amount:        transaction.Amount.Total,
+		Paid:          transaction.TradeState == "SUCCESS",
+	}, nil
+}
+
+func (w *WxPay) call(ctx context.Context, method, path string, body, result any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("请求体序列化失败: %w", err)
+	}
+	key, err := w.signKey()
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	nonce := nonceStr()
+	signature, err := sign(key, method+"\n"+path+"\n"+timestamp+"\n"+nonce+"\n"+string(payload)+"\n")
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, wxPayHost+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("创建微信支付请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf(`%s mchid="%s",nonce_str="%s",signature="%s",timestamp="%s",serial_no="%s"`,
+		wxPayAuthSchema, w.mchId, nonce, signature, timestamp, w.mchSerialNo))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("微信支付请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取微信支付响应失败: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("微信支付返回错误, status=%d, body=%s", resp.StatusCode, respData)
+	}
+	if err = json.Unmarshal(respData, result); err != nil {
+		return fmt.Errorf("解析微信支付响应失败: %w", err)
+	}
+	return nil
+}
+
+// verify 使用微信支付平台公钥校验回调签名，未配置平台公钥时拒绝回调
+func (w *WxPay) verify(header http.Header, body []byte) error {
+	if w.platformPublicKey == "" {
+		return errors.New("未配置微信支付平台公钥")
+	}
+	block, _ := pem.Decode([]byte(w.platformPublicKey))
+	if block == nil {
+		return errors.New("微信支付平台公钥格式错误")
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("解析微信支付平台公钥失败: %w", err)
+	}
+	publicKey, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("微信支付平台公钥不是 RSA 公钥")
+	}
+	signature, err := base64.StdEncoding.DecodeString(header.Get("Wechatpay-Signature"))
+	if err != nil {
+		return fmt.Errorf("微信支付回调签名格式错误: %w", err)
+	}
+	message := header.Get("Wechatpay-Timestamp") + "\n" + header.Get("Wechatpay-Nonce") + "\n" + string(body) + "\n"
+	hashed := sha256.Sum256([]byte(message))
+	if err = rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hashed[:], signature); err != nil {
+		return fmt.Errorf("微信支付回调验签失败: %w", err)
+	}
+	return nil
+}
+
+func (w *WxPay) decrypt(ciphertext, associatedData, nonce string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("微信支付回调密文格式错误: %w", err)
+	}
+	block, err := aes.NewCipher([]byte(w.apiV3Key))
+	if err != nil {
+		return nil, fmt.Errorf("微信支付 APIv3 密钥错误: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, []byte(nonce), data, []byte(associatedData))
+	if err != nil {
+		return nil, fmt.Errorf("微信支付回调解密失败: %w", err)
+	}
+	return plain, nil
+}
+
+func (w *WxPay) signKey() (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(w.privateKey))
+	if block == nil {
+		return nil, errors.New("微信支付商户私钥格式错误")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("解析微信支付商户私钥失败: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("微信支付商户私钥不是 RSA 私钥")
+	}
+	return key, nil
+}
+
+func sign(key *rsa.PrivateKey, message string) (string, error) {
+	hashed := sha256.Sum256([]byte(message))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", fmt.Errorf("微信支付签名失败: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+func nonceStr() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,239 @@
+package platform
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const defaultAccessExpire = int64(7 * 24 * 3600)
+
+// Fake 模拟中台的登录、设置密码与发送验证码接口，数据仅保存在内存中，验证码打印在日志中，禁止在生产环境使用
+type Fake struct {
+	mu       sync.Mutex
+	key      *ecdsa.PrivateKey
+	expire   int64
+	accounts map[string]*fakeAccount // authId -> 账号
+	codes    map[string]string       // authId -> 最近一次发送的验证码
+}
+
+type fakeAccount struct {
+	userId       string
+	passwordHash string
+}
+
+func NewFake(key *ecdsa.PrivateKey, accessExpire int64) *Fake {
+	if accessExpire <= 0 {
+		accessExpire = defaultAccessExpire
+	}
+	return &Fake{
+		key:      key,
+		expire:   accessExpire,
+		accounts: map[string]*fakeAccount{},
+		codes:    map[string]string{},
+	}
+}
+
+// StartLocal 在本机随机端口启动模拟中台，并将 Platform.BaseUrl 指向它；未配置 Auth 密钥时生成一对临时密钥，
+// 使模拟中台签发的令牌与本服务签发的令牌都能通过 ExtractUserMeta 校验
+func StartLocal(cfg *config.Config) (*Fake, error) {
+	key, err := loadOrGenerateKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	fake := NewFake(key, cfg.Auth.AccessExpire)
+	go func() {
+		if err := http.Serve(listener, fake); err != nil {
+			log.Error("[platform.Fake] serve failed, err=%v", err)
+		}
+	}()
+	cfg.Platform.BaseUrl = "http://" + listener.Addr().String()
+	return fake, nil
+}
+
+func loadOrGenerateKey(cfg *config.Config) (*ecdsa.PrivateKey, error) {
+	if cfg.Auth.SecretKey != "" {
+		return jwt.ParseECPrivateKeyFromPEM([]byte(cfg.Auth.SecretKey))
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	privateDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	publicDer, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Auth.SecretKey = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privateDer}))
+	cfg.Auth.PublicKey = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer}))
+	return key, nil
+}
+
+// Code 返回最近一次发送给 authId 的验证码，供测试使用
+func (f *Fake) Code(authId string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.codes[authId]
+}
+
+func (f *Fake) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"msg": "method not allowed"})
+		return
+	}
+	var body struct {
+		AuthType   string  `json:"authType"`
+		AuthId     string  `json:"authId"`
+		VerifyCode *string `json:"verifyCode"`
+		Password   *string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"msg": "invalid body"})
+		return
+	}
+
+	switch r.URL.Path {
+	case consts.PlatformSendVerifyCodePath:
+		f.sendVerifyCode(w, body.AuthId)
+	case consts.PlatformSignInPath:
+		f.signIn(w, body.AuthId, body.VerifyCode, body.Password)
+	case consts.PlatformSetPasswordPath:
+		f.setPassword(w, r.Header.Get("Authorization"), body.Password)
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]any{"msg": "not found"})
+	}
+}
+
+func (f *Fake) sendVerifyCode(w http.ResponseWriter, authId string) {
+	if authId == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"msg": "authId is required"})
+		return
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"msg": err.Error()})
+		return
+	}
+	code := fmt.Sprintf("%06d", n.Int64())
+	f.mu.Lock()
+	f.codes[authId] = code
+	f.mu.Unlock()
+	log.Info("[platform.Fake] verify code for %s: %s", authId, code)
+	writeJSON(w, http.StatusOK, map[string]any{"msg": "ok"})
+}
+
+// signIn 验证码登录时自动注册新账号，与中台行为一致；验证码使用一次后失效
+func (f *Fake) signIn(w http.ResponseWriter, authId string, verifyCode, password *string) {
+	f.mu.Lock()
+	account := f.accounts[authId]
+	switch {
+	case verifyCode != nil && *verifyCode != "":
+		if f.codes[authId] == "" || f.codes[authId] != *verifyCode {
+			f.mu.Unlock()
+			writeJSON(w, http.StatusUnauthorized, map[string]any{"msg": "invalid verify code"})
+			return
+		}
+		delete(f.codes, authId)
+		if account == nil {
+			account = &fakeAccount{userId: primitive.NewObjectID().Hex()}
+			f.accounts[authId] = account
+		}
+	case password != nil && *password != "":
+		if account == nil || account.passwordHash == "" || account.passwordHash != hashPassword(account.userId, *password) {
+			f.mu.Unlock()
+			writeJSON(w, http.StatusUnauthorized, map[string]any{"msg": "invalid password"})
+			return
+		}
+	default:
+		f.mu.Unlock()
+		writeJSON(w, http.StatusBadRequest, map[string]any{"msg": "verifyCode or password is required"})
+		return
+	}
+	f.mu.Unlock()
+
+	token, expire, err := f.sign(account.userId)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"msg": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"userId":       account.userId,
+		"accessToken":  token,
+		"accessExpire": expire,
+	})
+}
+
+func (f *Fake) setPassword(w http.ResponseWriter, authorization string, password *string) {
+	if password == nil || *password == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"msg": "password is required"})
+		return
+	}
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(authorization, claims, func(_ *jwt.Token) (interface{}, error) {
+		return &f.key.PublicKey, nil
+	})
+	userId, _ := claims["userId"].(string)
+	if err != nil || userId == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"msg": "invalid token"})
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, account := range f.accounts {
+		if account.userId == userId {
+			account.passwordHash = hashPassword(userId, *password)
+			writeJSON(w, http.StatusOK, map[string]any{"msg": "ok"})
+			return
+		}
+	}
+	writeJSON(w, http.StatusNotFound, map[string]any{"msg": "account not found"})
+}
+
+func (f *Fake) sign(userId string) (string, int64, error) {
+	now := time.Now()
+	expire := now.Add(time.Duration(f.expire) * time.Second).Unix()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"userId":  userId,
+		"appId":   consts.AppId,
+		"isLogin": true,
+		"iat":     now.Unix(),
+		"exp":     expire,
+	}).SignedString(f.key)
+	return token, expire, err
+}
+
+func hashPassword(salt, password string) string {
+	sum := sha256.Sum256([]byte(salt + ":" + password))
+	return hex.EncodeToString(sum[:])
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", consts.ContentTypeJson)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,262 @@
+package region
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+)
+
+// 行政区划级别
+const (
+	LevelProvince = 1
+	LevelCity     = 2
+	LevelCounty   = 3
+)
+
+// regionData 内置的省级、地级行政区划及省直辖的县级行政区划，区县级数据通过 Region.DataFile 补充
+//
+//go:embed regions.json
+var regionData []byte
+
+// suffixes 匹配文字时可省略的行政区划后缀，按长度降序排列
+var suffixes = []string{"特别行政区", "维吾尔自治区", "壮族自治区", "回族自治区", "自治区", "自治州", "自治县", "林区", "地区", "省", "市", "盟", "县", "区"}
+
+// Region 行政区划，Parent 为上级区划代码，省级区划为空
+type Region struct {
+	Code   string
+	Name   string
+	Level  int
+	Parent string
+}
+
+// Dataset 只读的行政区划数据，启动时加载
+type Dataset struct {
+	regions  map[string]*Region
+	children map[string][]*Region
+	ordered  []*Region // 按层级深度优先排列，与行政区划表的顺序一致
+	// texts 名称或路径（含省略后缀的写法）到区划代码，同一写法对应多个区划时为空
+	texts map[string]string
+}
+
+var DatasetSet = wire.NewSet(NewDataset)
+
+func NewDataset(config *config.Config) (*Dataset, error) {
+	names := map[string]string{}
+	if err := json.Unmarshal(regionData, &names); err != nil {
+		return nil, err
+	}
+	if file := config.Region.DataFile; file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		extra := map[string]string{}
+		if err = json.Unmarshal(data, &extra); err != nil {
+			return nil, fmt.Errorf("解析行政区划数据失败: %w", err)
+		}
+		for code, name := range extra {
+			names[code] = name
+		}
+	}
+	return newDataset(names)
+}
+
+func newDataset(names map[string]string) (*Dataset, error) {
+	d := &Dataset{
+		regions:  make(map[string]*Region, len(names)),
+		children: map[string][]*Region{},
+		texts:    map[string]string{},
+	}
+	for code, name := range names {
+		if !validCode(code) || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("无效的行政区划: %s %s", code, name)
+		}
+		d.regions[code] = &Region{Code: code, Name: strings.TrimSpace(name), Level: levelOf(code)}
+	}
+	codes := make([]string, 0, len(d.regions))
+	for code, r := range d.regions {
+		// 省直辖的县级区划没有地级上级，直接挂在省级区划下
+		switch r.Level {
+		case LevelCounty:
+			if _, ok := d.regions[code[:4]+"00"]; ok {
+				r.Parent = code[:4] + "00"
+			} else {
+				r.Parent = code[:2] + "0000"
+			}
+		case LevelCity:
+			r.Parent = code[:2] + "0000"
+		}
+		if _, ok := d.regions[r.Parent]; r.Parent != "" && !ok {
+			return nil, fmt.Errorf("行政区划 %s 缺少上级 %s", code, r.Parent)
+		}
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		r := d.regions[code]
+		d.children[r.Parent] = append(d.children[r.Parent], r)
+		d.index(r)
+	}
+	var walk func(parent string)
+	walk = func(parent string) {
+		for _, r := range d.children[parent] {
+			d.ordered = append(d.ordered, r)
+			walk(r.Code)
+		}
+	}
+	walk("")
+	return d, nil
+}
+
+// index 为区划登记可匹配的写法：名称、全路径以及省略后缀的写法
+func (d *Dataset) index(r *Region) {
+	path := d.Path(r.Code)
+	full, short := "", ""
+	for _, p := range path {
+		full += p.Name
+		short += trimSuffix(p.Name)
+	}
+	for _, text := range []string{r.Name, trimSuffix(r.Name), full, short} {
+		if text == "" {
+			continue
+		}
+		if existing, ok := d.texts[text]; ok && existing != r.Code {
+			d.texts[text] = ""
+			continue
+		}
+		d.texts[text] = r.Code
+	}
+}
+
+// Get 查询区划，代码不存在时返回 nil
+func (d *Dataset) Get(code string) *Region {
+	return d.regions[code]
+}
+
+// Children 返回下级区划，code 为空时返回全部省级区划
+func (d *Dataset) Children(code string) []*Region {
+	return d.children[code]
+}
+
+// Nearest 返回代码对应的区划，代码不在数据中时依次返回其地级、省级上级，均不存在时返回 nil
+func (d *Dataset) Nearest(code string) *Region {
+	if !validCode(code) {
+		return nil
+	}
+	for _, c := range []string{code, code[:4] + "00", code[:2] + "0000"} {
+		if r := d.regions[c]; r != nil {
+			return r
+		}
+	}
+	return nil
+}
+
+// Prefix 代码前缀，下级区划的代码均以此开头
+func (r *Region) Prefix() string {
+	switch r.Level {
+	case LevelProvince:
+		return r.Code[:2]
+	case LevelCity:
+		return r.Code[:4]
+	default:
+		return r.Code
+	}
+}
+
+// Path 返回从省级区划到自身的路径，代码不存在时返回空
+func (d *Dataset) Path(code string) []*Region {
+	path := make([]*Region, 0, LevelCounty)
+	for r := d.regions[code]; r != nil; r = d.regions[r.Parent] {
+		path = append([]*Region{r}, path...)
+	}
+	return path
+}
+
+// FullName 以空格连接的路径名称，如“安徽省 合肥市”
+func (d *Dataset) FullName(code string) string {
+	names := make([]string, 0, LevelCounty)
+	for _, r := range d.Path(code) {
+		names = append(names, r.Name)
+	}
+	return strings.Join(names, " ")
+}
+
+// Resolve 将文字匹配为区划代码，支持“安徽省合肥市”“安徽 合肥”“合肥”等写法，无法唯一匹配时返回空
+func (d *Dataset) Resolve(text string) string {
+	text = strings.Join(strings.Fields(text), "")
+	if text == "" {
+		return ""
+	}
+	if code := d.texts[text]; code != "" {
+		return code
+	}
+	return d.texts[trimSuffix(text)]
+}
+
+// Search 按名称包含关键词查询区划，省级在前
+func (d *Dataset) Search(keyword string, limit int) []*Region {
+	keyword = strings.TrimSpace(keyword)
+	result := make([]*Region, 0, limit)
+	if keyword == "" {
+		return result
+	}
+	for level := LevelProvince; level <= LevelCounty; level++ {
+		for _, r := range d.ordered {
+			if r.Level == level && strings.Contains(r.Name, keyword) {
+				result = append(result, r)
+				if len(result) >= limit {
+					return result
+				}
+			}
+		}
+	}
+	return result
+}
+
+// Ancestor 返回区划在指定级别的上级（或自身），不存在时返回空
+func (d *Dataset) Ancestor(code string, level int) string {
+	for _, r := range d.Path(code) {
+		if r.Level == level {
+			return r.Code
+		}
+	}
+	return ""
+}
+
+func validCode(code string) bool {
+	if len(code) != 6 || code[:2] == "00" {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func levelOf(code string) int {
+	switch {
+	case code[2:] == "0000":
+		return LevelProvince
+	case code[4:] == "00":
+		return LevelCity
+	default:
+		return LevelCounty
+	}
+}
+
+func trimSuffix(name string) string {
+	for _, suffix := range suffixes {
+		if trimmed := strings.TrimSuffix(name, suffix); trimmed != name && trimmed != "" {
+			return trimmed
+		}
+	}
+	return name
+}
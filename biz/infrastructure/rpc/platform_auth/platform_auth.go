@@ -0,0 +1,197 @@
+package platform_auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util/log"
+)
+
+const (
+	defaultTimeout = 5 * time.Second
+	// maxAttempts 幂等请求在网络错误或 5xx 时的最大尝试次数
+	maxAttempts  = 3
+	retryBackoff = 200 * time.Millisecond
+)
+
+// IPlatformAuth 中台账号接口，失败时返回 consts.Errno：中台拒绝请求时返回各接口对应的业务错误，中台不可用时返回 ErrPlatformUnavailable
+type IPlatformAuth interface {
+	// SignIn 使用验证码或密码登录，验证码登录的手机号未注册时中台会自动注册
+	SignIn(ctx context.Context, req *SignInReq) (*SignInResp, error)
+	SetPassword(ctx context.Context, accessToken, password string) error
+	SendVerifyCode(ctx context.Context, authType, authId string) error
+}
+
+type SignInReq struct {
+	AuthType   string  `json:"authType"`
+	AuthId     string  `json:"authId"`
+	VerifyCode *string `json:"verifyCode,omitempty"`
+	Password   *string `json:"password,omitempty"`
+	AppId      int64   `json:"appId"`
+}
+
+type SignInResp struct {
+	UserId       string `json:"userId"`
+	AccessToken  string `json:"accessToken"`
+	AccessExpire int64  `json:"accessExpire"`
+}
+
+// Error 中台返回的非 2xx 响应
+type Error struct {
+	Status int
+	Code   int64
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("platform status=%d, code=%d, msg=%s", e.Status, e.Code, e.Msg)
+}
+
+type PlatformAuth struct {
+	client *http.Client
+	config *config.Config
+}
+
+var PlatformAuthSet = wire.NewSet(
+	NewPlatformAuth,
+	wire.Bind(new(IPlatformAuth), new(*PlatformAuth)),
+)
+
+func NewPlatformAuth(config *config.Config) *PlatformAuth {
+	timeout := time.Duration(config.Platform.Timeout) * time.Millisecond
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &PlatformAuth{
+		client: &http.Client{Timeout: timeout},
+		config: config,
+	}
+}
+
+func (p *PlatformAuth) SignIn(ctx context.Context, req *SignInReq) (*SignInResp, error) {
+	req.AppId = consts.AppId
+	// 验证码只能使用一次，验证码登录不重试
+	idempotent := req.VerifyCode == nil
+	resp := new(SignInResp)
+	if err := p.call(ctx, consts.PlatformSignInPath, "", req, resp, idempotent); err != nil {
+		return nil, mapError(ctx, err, consts.ErrSignIn)
+	}
+	if resp.UserId == "" || resp.AccessToken == "" {
+		log.CtxError(ctx, "[PlatformAuth] sign in response missing userId or accessToken")
+		return nil, consts.ErrSignIn
+	}
+	return resp, nil
+}
+
+func (p *PlatformAuth) SetPassword(ctx context.Context, accessToken, password string) error {
+	body := map[string]any{"password": password, "appId": consts.AppId}
+	return mapError(ctx, p.call(ctx, consts.PlatformSetPasswordPath, accessToken, body, nil, true), consts.ErrSignUp)
+}
+
+func (p *PlatformAuth) SendVerifyCode(ctx context.Context, authType, authId string) error {
+	body := map[string]any{"authType": authType, "authId": authId, "appId": consts.AppId}
+	return mapError(ctx, p.call(ctx, consts.PlatformSendVerifyCodePath, "", body, nil, false), consts.ErrSend)
+}
+
+// url 拼接中台接口地址；State 为 local 时 BaseUrl 在启动后才被改写，因此每次调用时读取
+func (p *PlatformAuth) url(path string) string {
+	baseUrl := p.config.Platform.BaseUrl
+	if baseUrl == "" {
+		baseUrl = consts.PlatformBaseUrl
+	}
+	return strings.TrimRight(baseUrl, "/") + path
+}
+
+// call 发送请求并解析响应，idempotent 为 true 时在网络错误或 5xx 时按退避间隔重试
+func (p *PlatformAuth) call(ctx context.Context, path, authorization string, body, out any, idempotent bool) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	attempts := 1
+	if idempotent {
+		attempts = maxAttempts
+	}
+	for attempt := 1; ; attempt++ {
+		err = p.do(ctx, path, authorization, payload, out)
+		var platformErr *Error
+		retryable := err != nil && (!errors.As(err, &platformErr) || platformErr.Status >= http.StatusInternalServerError)
+		if !retryable || attempt >= attempts {
+			return err
+		}
+		log.CtxInfo(ctx, "[PlatformAuth] retry %s, attempt=%d, err=%v", path, attempt, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryBackoff * time.Duration(attempt)):
+		}
+	}
+}
+
+func (p *PlatformAuth) do(ctx context.Context, path, authorization string, payload []byte, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url(path), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", consts.ContentTypeJson)
+	req.Header.Set("Charset", consts.CharSetUTF8)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	// 测试环境向测试环境的中台发送请求
+	if p.config.State == "test" {
+		req.Header.Set("X-Xh-Env", "test")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		platformErr := &Error{Status: resp.StatusCode}
+		var errBody struct {
+			Code int64  `json:"code"`
+			Msg  string `json:"msg"`
+		}
+		if json.Unmarshal(data, &errBody) == nil {
+			platformErr.Code, platformErr.Msg = errBody.Code, errBody.Msg
+		}
+		return platformErr
+	}
+	if out == nil {
+		return nil
+	}
+	if err = json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("解析中台响应失败: %w", err)
+	}
+	return nil
+}
+
+// mapError 中台拒绝请求（4xx）时返回 rejected，网络错误、超时或 5xx 时返回 ErrPlatformUnavailable；
+// 中台未公布业务错误码，不按 code 区分拒绝原因，登录时也不会因此暴露账号是否存在
+func mapError(ctx context.Context, err error, rejected *consts.Errno) error {
+	if err == nil {
+		return nil
+	}
+	log.CtxError(ctx, "[PlatformAuth] request failed, err=%v", err)
+	var platformErr *Error
+	if !errors.As(err, &platformErr) || platformErr.Status >= http.StatusInternalServerError {
+		return consts.ErrPlatformUnavailable
+	}
+	return rejected
+}
@@ -0,0 +1,52 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/service-idl-gen-go/kitex_gen/basic"
+)
+
+// SignAccessToken 使用 Auth.SecretKey 签发与中台格式一致的 access token，sessionId 写入 DeviceId，配置了 Auth.KeyId 时写入 kid，返回 token 与过期时间戳
+func SignAccessToken(userId, sessionId string, wechat *basic.WechatUserMeta) (string, int64, error) {
+	cfg := config.GetConfig()
+	key, err := jwt.ParseECPrivateKeyFromPEM([]byte(cfg.Auth.SecretKey))
+	if err != nil {
+		return "", 0, fmt.Errorf("解析签名密钥失败: %w", err)
+	}
+	meta := &basic.UserMeta{
+		UserId:          userId,
+		AppId:           basic.APP(consts.AppId),
+		DeviceId:        sessionId,
+		SessionUserId:   userId,
+		SessionAppId:    basic.APP(consts.AppId),
+		SessionDeviceId: sessionId,
+		IsLogin:         true,
+		WechatUserMeta:  wechat,
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return "", 0, err
+	}
+	claims := jwt.MapClaims{}
+	if err = json.Unmarshal(data, &claims); err != nil {
+		return "", 0, err
+	}
+	now := time.Now()
+	expire := now.Add(time.Duration(cfg.Auth.AccessExpire) * time.Second).Unix()
+	claims["iat"] = now.Unix()
+	claims["exp"] = expire
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	if cfg.Auth.KeyId != "" {
+		token.Header["kid"] = cfg.Auth.KeyId
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		return "", 0, fmt.Errorf("签发 token 失败: %w", err)
+	}
+	return signed, expire, nil
+}
@@ -127,3 +127,42 @@ func (w *WxClient) GetPhoneNumber(code string) (string, error) {
 	}
 	return result.PhoneInfo.PhoneNumber, nil
 }
+
+// WxSession 小程序登录凭证校验结果，UnionId 仅在小程序绑定开放平台后返回
+type WxSession struct {
+	OpenId     string `json:"openid"`
+	UnionId    string `json:"unionid"`
+	SessionKey string `json:"session_key"`
+}
+
+// Code2Session 通过 wx.login 获取的 code 换取 openid 与 unionid
+func (w *WxClient) Code2Session(code string) (*WxSession, error) {
+	url := fmt.Sprintf("https://api.weixin.qq.com/sns/jscode2session?appid=%s&secret=%s&js_code=%s&grant_type=authorization_code",
+		w.appId, w.appSecret, code)
+	resp, err := w.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("登录凭证校验请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取登录凭证校验响应失败: %w", err)
+	}
+
+	var result struct {
+		WxSession
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("解析登录凭证校验响应失败: %w", err)
+	}
+	if result.ErrCode != 0 {
+		return nil, fmt.Errorf("登录凭证校验失败: %s", result.ErrMsg)
+	}
+	if result.OpenId == "" {
+		return nil, fmt.Errorf("未返回 openid")
+	}
+	return &result.WxSession, nil
+}
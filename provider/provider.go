@@ -1,15 +1,42 @@
 package provider
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/wire"
+	"github.com/xh-polaris/alumni-core_api/biz/adaptor"
 	"github.com/xh-polaris/alumni-core_api/biz/application/service"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/captcha"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/consts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/jwks"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/limiter"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/activity"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/article"
-	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/seed"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/audit"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/dictionary"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/group"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/group_announcement"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/group_member"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/job"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/job_application"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/mentorship"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/notification"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/order"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/organization"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/register"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/role"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/session"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/verification"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/payment"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/platform"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/region"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/rpc/platform_auth"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/rpc/platform_sts"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/seed"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/util/log"
 )
 
 var provider *Provider
@@ -20,17 +47,56 @@ func Init() {
 	if err != nil {
 		panic(err)
 	}
-	seed.EnsureDevData(provider.Config)
+	if provider.Config.State == config.StateLocal {
+		if _, err = platform.StartLocal(provider.Config); err != nil {
+			panic(err)
+		}
+		log.Error("[WARNING] state=local, using the in-memory fake platform at %s", provider.Config.Platform.BaseUrl)
+	}
+	if provider.Config.MockAuthEnabled() {
+		log.Error("[WARNING] mock auth is enabled, state=%s: requests with header %s: %s can act as user %s",
+			provider.Config.State, consts.DevModeHeader, consts.DevModeValue, consts.DevMockUserID)
+	}
+	if provider.Config.SeedEnabled() {
+		seed.EnsureDevData(provider.Config)
+	} else if provider.Config.Dev.Seed {
+		log.Error("[WARNING] dev seed is ignored, state=%s: demo data is only written when state is dev, test or local", provider.Config.State)
+	}
+	keySet, err := jwks.NewKeySet(provider.Config)
+	if err != nil {
+		panic(err)
+	}
+	go keySet.Watch(context.Background(), time.Duration(provider.Config.Auth.JwksReload)*time.Second)
+	adaptor.SetKeyfunc(keySet.Keyfunc)
+	adaptor.SetSessionValidator(provider.SessionService.ValidateSession)
+	go provider.AccountService.RunDeletionSweeper(context.Background(), time.Hour)
+	go provider.OrderService.RunExpirySweeper(context.Background(), time.Minute)
 }
 
 // Provider 提供controller依赖的对象
 type Provider struct {
-	Config          *config.Config
-	UserService     service.UserService
-	ActivityService service.ActivityService
-	AdminService    service.AdminService
-	ArticleService  service.ArticleService
-	StsService      service.StsService
+	Config              *config.Config
+	UserService         service.UserService
+	ActivityService     service.ActivityService
+	AdminService        service.AdminService
+	ArticleService      service.ArticleService
+	StsService          service.StsService
+	OrderService        service.OrderService
+	OrganizationService service.OrganizationService
+	DirectoryService    service.DirectoryService
+	PrivacyService      service.PrivacyService
+	VerificationService service.VerificationService
+	RoleService         service.RoleService
+	AuditService        service.AuditService
+	MergeService        service.MergeService
+	AccountService      service.AccountService
+	SessionService      service.SessionService
+	MentorshipService   service.MentorshipService
+	JobService          service.JobService
+	DictionaryService   service.DictionaryService
+	RegionService       service.RegionService
+	NotificationService service.NotificationService
+	GroupService        service.GroupService
 }
 
 func Get() *Provider {
@@ -43,9 +109,27 @@ var ApplicationSet = wire.NewSet(
 	service.AdminServiceSet,
 	service.ArticleServiceSet,
 	service.StsServiceSet,
+	service.OrderServiceSet,
+	service.OrganizationServiceSet,
+	service.DirectoryServiceSet,
+	service.PrivacyServiceSet,
+	service.VerificationServiceSet,
+	service.RoleServiceSet,
+	service.AuditServiceSet,
+	service.MergeServiceSet,
+	service.AccountServiceSet,
+	service.SessionServiceSet,
+	service.MentorshipServiceSet,
+	service.JobServiceSet,
+	service.DictionaryServiceSet,
+	service.RegionServiceSet,
+	service.NotificationServiceSet,
+	service.GroupServiceSet,
+	service.UserRecordsSet,
 )
 
 var RpcSet = wire.NewSet(
+	platform_auth.PlatformAuthSet,
 	platform_sts.PlatformStsSet,
 )
 
@@ -55,6 +139,24 @@ var InfrastructureSet = wire.NewSet(
 	article.NewMongoMapper,
 	activity.NewMongoMapper,
 	register.NewMongoMapper,
+	order.NewMongoMapper,
+	organization.NewMongoMapper,
+	verification.NewMongoMapper,
+	role.NewMongoMapper,
+	audit.NewMongoMapper,
+	session.NewMongoMapper,
+	mentorship.NewMongoMapper,
+	job.NewMongoMapper,
+	job_application.NewMongoMapper,
+	dictionary.NewMongoMapper,
+	notification.NewMongoMapper,
+	group.NewMongoMapper,
+	group_member.NewMongoMapper,
+	group_announcement.NewMongoMapper,
+	payment.PaymentSet,
+	limiter.LimiterSet,
+	captcha.CaptchaSet,
+	region.DatasetSet,
 	RpcSet,
 )
 
@@ -8,11 +8,30 @@ package provider
 
 import (
 	"github.com/xh-polaris/alumni-core_api/biz/application/service"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/captcha"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/config"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/limiter"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/activity"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/article"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/audit"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/dictionary"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/group"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/group_announcement"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/group_member"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/job"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/job_application"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/mentorship"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/notification"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/order"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/organization"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/register"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/role"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/session"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/user"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/mapper/verification"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/payment"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/region"
+	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/rpc/platform_auth"
 	"github.com/xh-polaris/alumni-core_api/biz/infrastructure/rpc/platform_sts"
 )
 
@@ -24,20 +43,68 @@ func NewProvider() (*Provider, error) {
 		return nil, err
 	}
 	mongoMapper := user.NewMongoMapper(configConfig)
+	sessionMongoMapper := session.NewMongoMapper(configConfig)
+	platformAuth := platform_auth.NewPlatformAuth(configConfig)
+	limiterLimiter := limiter.NewLimiter(configConfig)
+	reject := &captcha.Reject{}
+	dictionaryMongoMapper := dictionary.NewMongoMapper(configConfig)
+	dataset, err := region.NewDataset(configConfig)
+	if err != nil {
+		return nil, err
+	}
+	registerMongoMapper := register.NewMongoMapper(configConfig)
+	orderMongoMapper := order.NewMongoMapper(configConfig)
+	verificationMongoMapper := verification.NewMongoMapper(configConfig)
+	organizationMongoMapper := organization.NewMongoMapper(configConfig)
+	mentorshipMongoMapper := mentorship.NewMongoMapper(configConfig)
+	jobMongoMapper := job.NewMongoMapper(configConfig)
+	job_applicationMongoMapper := job_application.NewMongoMapper(configConfig)
+	group_memberMongoMapper := group_member.NewMongoMapper(configConfig)
+	group_announcementMongoMapper := group_announcement.NewMongoMapper(configConfig)
+	userRecords := &service.UserRecords{
+		RegisterMapper:     registerMongoMapper,
+		OrderMapper:        orderMongoMapper,
+		VerificationMapper: verificationMongoMapper,
+		OrganizationMapper: organizationMongoMapper,
+		MentorshipMapper:   mentorshipMongoMapper,
+		JobMapper:          jobMongoMapper,
+		ApplicationMapper:  job_applicationMongoMapper,
+		GroupMemberMapper:  group_memberMongoMapper,
+		AnnouncementMapper: group_announcementMongoMapper,
+	}
 	userService := service.UserService{
-		UserMapper: mongoMapper,
+		UserMapper:       mongoMapper,
+		SessionMapper:    sessionMongoMapper,
+		PlatformAuth:     platformAuth,
+		Limiter:          limiterLimiter,
+		Captcha:          reject,
+		DictionaryMapper: dictionaryMongoMapper,
+		Regions:          dataset,
+		Records:          userRecords,
 	}
 	activityMongoMapper := activity.NewMongoMapper(configConfig)
-	registerMongoMapper := register.NewMongoMapper(configConfig)
+	roleMongoMapper := role.NewMongoMapper(configConfig)
 	activityService := service.ActivityService{
-		ActivityMapper: activityMongoMapper,
-		RegisterMapper: registerMongoMapper,
+		ActivityMapper:     activityMongoMapper,
+		RegisterMapper:     registerMongoMapper,
+		OrderMapper:        orderMongoMapper,
+		UserMapper:         mongoMapper,
+		OrganizationMapper: organizationMongoMapper,
+		RoleMapper:         roleMongoMapper,
+		GroupMemberMapper:  group_memberMongoMapper,
 	}
 	articleMongoMapper := article.NewMongoMapper(configConfig)
+	auditMongoMapper := audit.NewMongoMapper(configConfig)
 	adminService := service.AdminService{
 		UserMapper:     mongoMapper,
 		RegisterMapper: registerMongoMapper,
 		ArticleMapper:  articleMongoMapper,
+		ActivityMapper: activityMongoMapper,
+		OrderMapper:    orderMongoMapper,
+		RoleMapper:     roleMongoMapper,
+		AuditMapper:    auditMongoMapper,
+		SessionMapper:  sessionMongoMapper,
+		Regions:        dataset,
 	}
 	articleService := service.ArticleService{
 		ArticleMapper: articleMongoMapper,
@@ -47,16 +114,135 @@ func NewProvider() (*Provider, error) {
 		Client: client,
 	}
 	stsService := service.StsService{
-		PlatformSts: platformSts,
+		PlatformSts:  platformSts,
+		PlatformAuth: platformAuth,
+		UserMapper:   mongoMapper,
+		Limiter:      limiterLimiter,
+		Captcha:      reject,
+	}
+	iPaymentProvider, err := payment.NewPaymentProvider(configConfig)
+	if err != nil {
+		return nil, err
+	}
+	orderService := service.OrderService{
+		Config:         configConfig,
+		ActivityMapper: activityMongoMapper,
+		RegisterMapper: registerMongoMapper,
+		OrderMapper:    orderMongoMapper,
+		Payment:        iPaymentProvider,
+		AuditMapper:    auditMongoMapper,
+	}
+	organizationService := service.OrganizationService{
+		OrganizationMapper: organizationMongoMapper,
+		ActivityMapper:     activityMongoMapper,
+		UserMapper:         mongoMapper,
+		AuditMapper:        auditMongoMapper,
+	}
+	directoryService := service.DirectoryService{
+		UserMapper:       mongoMapper,
+		DictionaryMapper: dictionaryMongoMapper,
+		Regions:          dataset,
+	}
+	privacyService := service.PrivacyService{
+		UserMapper: mongoMapper,
+	}
+	verificationService := service.VerificationService{
+		UserMapper:         mongoMapper,
+		VerificationMapper: verificationMongoMapper,
+		AuditMapper:        auditMongoMapper,
+	}
+	roleService := service.RoleService{
 		UserMapper:  mongoMapper,
+		RoleMapper:  roleMongoMapper,
+		AuditMapper: auditMongoMapper,
+	}
+	auditService := service.AuditService{
+		AuditMapper: auditMongoMapper,
+	}
+	mergeService := service.MergeService{
+		UserMapper:  mongoMapper,
+		AuditMapper: auditMongoMapper,
+		Records:     userRecords,
+	}
+	groupMongoMapper := group.NewMongoMapper(configConfig)
+	accountService := service.AccountService{
+		Config:             configConfig,
+		UserMapper:         mongoMapper,
+		RegisterMapper:     registerMongoMapper,
+		OrderMapper:        orderMongoMapper,
+		VerificationMapper: verificationMongoMapper,
+		OrganizationMapper: organizationMongoMapper,
+		GroupMapper:        groupMongoMapper,
+		GroupMemberMapper:  group_memberMongoMapper,
+		AnnouncementMapper: group_announcementMongoMapper,
+		MentorshipMapper:   mentorshipMongoMapper,
+		JobMapper:          jobMongoMapper,
+		ApplicationMapper:  job_applicationMongoMapper,
+	}
+	sessionService := service.SessionService{
+		UserMapper:    mongoMapper,
+		SessionMapper: sessionMongoMapper,
+	}
+	mentorshipService := service.MentorshipService{
+		UserMapper:       mongoMapper,
+		MentorshipMapper: mentorshipMongoMapper,
+	}
+	jobService := service.JobService{
+		UserMapper:           mongoMapper,
+		JobMapper:            jobMongoMapper,
+		JobApplicationMapper: job_applicationMongoMapper,
+		AuditMapper:          auditMongoMapper,
+	}
+	dictionaryService := service.DictionaryService{
+		UserMapper:       mongoMapper,
+		DictionaryMapper: dictionaryMongoMapper,
+		AuditMapper:      auditMongoMapper,
+	}
+	regionService := service.RegionService{
+		UserMapper:  mongoMapper,
+		AuditMapper: auditMongoMapper,
+		Regions:     dataset,
+	}
+	notificationMongoMapper := notification.NewMongoMapper(configConfig)
+	notificationService := service.NotificationService{
+		UserMapper:         mongoMapper,
+		NotificationMapper: notificationMongoMapper,
+		AuditMapper:        auditMongoMapper,
+	}
+	groupService := service.GroupService{
+		UserMapper:              mongoMapper,
+		GroupMapper:             groupMongoMapper,
+		GroupMemberMapper:       group_memberMongoMapper,
+		GroupAnnouncementMapper: group_announcementMongoMapper,
+		ActivityMapper:          activityMongoMapper,
+		DictionaryMapper:        dictionaryMongoMapper,
+		NotificationMapper:      notificationMongoMapper,
+		AuditMapper:             auditMongoMapper,
+		Regions:                 dataset,
 	}
 	providerProvider := &Provider{
-		Config:          configConfig,
-		UserService:     userService,
-		ActivityService: activityService,
-		AdminService:    adminService,
-		ArticleService:  articleService,
-		StsService:      stsService,
+		Config:              configConfig,
+		UserService:         userService,
+		ActivityService:     activityService,
+		AdminService:        adminService,
+		ArticleService:      articleService,
+		StsService:          stsService,
+		OrderService:        orderService,
+		OrganizationService: organizationService,
+		DirectoryService:    directoryService,
+		PrivacyService:      privacyService,
+		VerificationService: verificationService,
+		RoleService:         roleService,
+		AuditService:        auditService,
+		MergeService:        mergeService,
+		AccountService:      accountService,
+		SessionService:      sessionService,
+		MentorshipService:   mentorshipService,
+		JobService:          jobService,
+		DictionaryService:   dictionaryService,
+		RegionService:       regionService,
+		NotificationService: notificationService,
+		GroupService:        groupService,
 	}
 	return providerProvider, nil
 }
@@ -7,6 +7,8 @@ import (
 	handler "github.com/xh-polaris/alumni-core_api/biz/adaptor/controller"
 	admin "github.com/xh-polaris/alumni-core_api/biz/adaptor/controller/admin"
 	article "github.com/xh-polaris/alumni-core_api/biz/adaptor/controller/article"
+	core_api "github.com/xh-polaris/alumni-core_api/biz/adaptor/controller/core_api"
+	"github.com/xh-polaris/alumni-core_api/biz/application/service"
 )
 
 // customizeRegister registers customize routers.
@@ -15,30 +17,142 @@ func customizedRegister(r *server.Hertz) {
 	r.GET("/articles", article.ListArticles)
 	r.GET("/articles/:id", article.GetArticle)
 
+	r.POST("/activity/get_tickets", core_api.ListTickets)
+	r.POST("/activity/get_organizations", core_api.GetActivityOrganizations)
+	r.POST("/dictionary/search", core_api.SearchDictionary)
+	r.POST("/directory/search", core_api.SearchDirectory)
+	r.POST("/directory/get_setting", core_api.GetDirectorySetting)
+	r.POST("/directory/update_setting", core_api.UpdateDirectorySetting)
+	r.POST("/group/create", core_api.CreateGroup)
+	r.POST("/group/update", core_api.UpdateGroup)
+	r.POST("/group/search", core_api.SearchGroups)
+	r.POST("/group/get", core_api.GetGroup)
+	r.POST("/group/join", core_api.JoinGroup)
+	r.POST("/group/leave", core_api.LeaveGroup)
+	r.POST("/group/invite", core_api.InviteGroupMember)
+	r.POST("/group/review_member", core_api.ReviewGroupMember)
+	r.POST("/group/set_member_role", core_api.SetGroupMemberRole)
+	r.POST("/group/remove_member", core_api.RemoveGroupMember)
+	r.POST("/group/get_members", core_api.GetGroupMembers)
+	r.POST("/group/create_announcement", core_api.CreateGroupAnnouncement)
+	r.POST("/group/get_announcements", core_api.GetGroupAnnouncements)
+	r.POST("/group/delete_announcement", core_api.DeleteGroupAnnouncement)
+	r.POST("/job/create", core_api.CreateJob)
+	r.POST("/job/search", core_api.SearchJobs)
+	r.POST("/job/get", core_api.GetJob)
+	r.POST("/job/get_mine", core_api.GetMyJobs)
+	r.POST("/job/close", core_api.CloseJob)
+	r.POST("/job/apply", core_api.ApplyJob)
+	r.POST("/job/get_applications", core_api.GetJobApplications)
+	r.POST("/job/get_vocabulary", core_api.GetJobVocabulary)
+	r.POST("/mentorship/get_setting", core_api.GetMentorSetting)
+	r.POST("/mentorship/update_setting", core_api.UpdateMentorSetting)
+	r.POST("/mentorship/suggest", core_api.SuggestMentors)
+	r.POST("/mentorship/create", core_api.CreateMentorship)
+	r.POST("/mentorship/get_many", core_api.GetMentorships)
+	r.POST("/mentorship/respond", core_api.RespondMentorship)
+	r.POST("/mentorship/complete", core_api.CompleteMentorship)
+	r.POST("/notification/get_many", core_api.GetNotifications)
+	r.POST("/notification/read", core_api.ReadNotifications)
+	r.POST("/order/create", core_api.CreateOrder)
+	r.POST("/order/get", core_api.GetOrder)
+	r.POST("/order/get_many", core_api.GetOrders)
+	r.POST("/order/pay_notify", core_api.PayNotify)
+	r.POST("/organization/get_many", core_api.GetOrganizations)
+	r.POST("/organization/get", core_api.GetOrganization)
+	r.POST("/organization/get_activities", core_api.GetOrganizationActivities)
+	r.POST("/organization/create_activity", core_api.CreateOrganizationActivity)
+	r.POST("/region/get_children", core_api.GetRegions)
+	r.POST("/region/search", core_api.SearchRegions)
+	r.POST("/region/get_stats", core_api.GetRegionStats)
+	r.POST("/session/refresh", core_api.RefreshSession)
+	r.POST("/session/logout", core_api.Logout)
+
+	r.GET("/user/:id/profile", core_api.GetUserProfile)
+	r.POST("/user/export", core_api.ExportUserData)
+	r.POST("/user/delete", core_api.DeleteAccount)
+	r.POST("/user/cancel_delete", core_api.CancelAccountDeletion)
+	r.POST("/user/phone/send_code", core_api.SendPhoneChangeCode)
+	r.POST("/user/phone/change", core_api.ChangePhone)
+	r.POST("/user/wx_login", core_api.WxLogin)
+	r.POST("/user/wx_bind_phone", core_api.WxBindPhone)
+	r.POST("/user/get_privacy", core_api.GetPrivacySetting)
+	r.POST("/user/update_hometown", core_api.UpdateHometown)
+	r.POST("/user/update_privacy", core_api.UpdatePrivacySetting)
+	r.POST("/verification/submit", core_api.SubmitVerification)
+	r.POST("/verification/get_many", core_api.GetVerifications)
+
 	adminGroup := r.Group("/admin", admin.RequireAuth())
 	adminGroup.GET("/session", admin.GetSession)
 
-	adminGroup.GET("/users", admin.ListUsers)
-	adminGroup.GET("/users/:id", admin.GetUser)
-	adminGroup.PATCH("/users/:id", admin.UpdateUser)
-	adminGroup.PATCH("/users/:id/role", admin.SetUserRole)
-	adminGroup.PATCH("/users/:id/status", admin.SetUserStatus)
-	adminGroup.DELETE("/users/:id", admin.DeleteUser)
-	adminGroup.POST("/users/:id/restore", admin.RestoreUser)
-
-	adminGroup.GET("/registrations", admin.ListRegistrations)
-	adminGroup.POST("/registrations", admin.CreateRegistration)
-	adminGroup.PATCH("/registrations/:id", admin.UpdateRegistration)
-	adminGroup.DELETE("/registrations/:id", admin.DeleteRegistration)
-	adminGroup.POST("/registrations/:id/check-in", admin.CheckInRegistration)
-	adminGroup.POST("/registrations/:id/cancel-check-in", admin.CancelCheckInRegistration)
-
-	adminGroup.GET("/articles", admin.ListArticles)
-	adminGroup.GET("/articles/:id", admin.GetArticle)
-	adminGroup.POST("/articles", admin.CreateArticle)
-	adminGroup.PATCH("/articles/:id", admin.UpdateArticle)
-	adminGroup.DELETE("/articles/:id", admin.DeleteArticle)
-	adminGroup.POST("/articles/:id/restore", admin.RestoreArticle)
-	adminGroup.POST("/articles/:id/publish", admin.PublishArticle)
-	adminGroup.POST("/articles/:id/offline", admin.OfflineArticle)
+	adminGroup.GET("/users", admin.Require(service.PermUserRead), admin.ListUsers)
+	adminGroup.GET("/users/:id", admin.Require(service.PermUserRead), admin.GetUser)
+	adminGroup.PATCH("/users/:id", admin.Require(service.PermUserWrite), admin.UpdateUser)
+	adminGroup.PATCH("/users/:id/role", admin.Require(service.PermRoleManage), admin.SetUserRole)
+	adminGroup.PATCH("/users/:id/status", admin.Require(service.PermUserWrite), admin.SetUserStatus)
+	adminGroup.DELETE("/users/:id", admin.Require(service.PermUserWrite), admin.DeleteUser)
+	adminGroup.POST("/users/:id/restore", admin.Require(service.PermUserWrite), admin.RestoreUser)
+	adminGroup.POST("/users/:id/unlock", admin.Require(service.PermUserWrite), admin.UnlockUser)
+	adminGroup.GET("/users/merge/preview", admin.Require(service.PermUserWrite), admin.PreviewMergeUsers)
+	adminGroup.POST("/users/merge", admin.Require(service.PermUserWrite), admin.MergeUsers)
+	adminGroup.POST("/users/notify_incomplete", admin.Require(service.PermUserWrite), admin.NotifyIncompleteProfiles)
+
+	adminGroup.GET("/registrations", admin.Require(service.PermRegistrationRead), admin.ListRegistrations)
+	adminGroup.POST("/registrations", admin.Require(service.PermRegistrationWrite), admin.CreateRegistration)
+	adminGroup.PATCH("/registrations/:id", admin.Require(service.PermRegistrationWrite), admin.UpdateRegistration)
+	adminGroup.DELETE("/registrations/:id", admin.Require(service.PermRegistrationWrite), admin.DeleteRegistration)
+	adminGroup.POST("/registrations/:id/check-in", admin.Require(service.PermRegistrationWrite), admin.CheckInRegistration)
+	adminGroup.POST("/registrations/:id/cancel-check-in", admin.Require(service.PermRegistrationWrite), admin.CancelCheckInRegistration)
+
+	adminGroup.PUT("/activities/:id/tickets", admin.Require(service.PermActivityWrite), admin.SetActivityTickets)
+	adminGroup.PUT("/activities/:id/organizations", admin.Require(service.PermActivityWrite), admin.SetActivityOrganizations)
+	adminGroup.PUT("/activities/:id/group", admin.Require(service.PermActivityWrite), admin.SetActivityGroup)
+	adminGroup.GET("/orders", admin.Require(service.PermOrderRead), admin.ListOrders)
+	adminGroup.POST("/orders/:id/refund", admin.Require(service.PermOrderRefund), admin.RefundOrder)
+
+	adminGroup.GET("/organizations", admin.Require(service.PermOrganizationRead), admin.ListOrganizations)
+	adminGroup.GET("/organizations/:id", admin.Require(service.PermOrganizationRead), admin.GetOrganization)
+	adminGroup.POST("/organizations", admin.Require(service.PermOrganizationWrite), admin.CreateOrganization)
+	adminGroup.PATCH("/organizations/:id", admin.Require(service.PermOrganizationWrite), admin.UpdateOrganization)
+	adminGroup.DELETE("/organizations/:id", admin.Require(service.PermOrganizationWrite), admin.DeleteOrganization)
+	adminGroup.POST("/organizations/:id/restore", admin.Require(service.PermOrganizationWrite), admin.RestoreOrganization)
+	adminGroup.PUT("/organizations/:id/members", admin.Require(service.PermOrganizationWrite), admin.SetOrganizationMembers)
+
+	adminGroup.GET("/verifications", admin.Require(service.PermVerificationReview), admin.ListVerifications)
+	adminGroup.POST("/verifications/:id/approve", admin.Require(service.PermVerificationReview), admin.ApproveVerification)
+	adminGroup.POST("/verifications/:id/reject", admin.Require(service.PermVerificationReview), admin.RejectVerification)
+
+	adminGroup.GET("/groups", admin.Require(service.PermGroupManage), admin.ListGroups)
+	adminGroup.DELETE("/groups/:id", admin.Require(service.PermGroupManage), admin.DeleteGroup)
+	adminGroup.POST("/groups/:id/restore", admin.Require(service.PermGroupManage), admin.RestoreGroup)
+	adminGroup.GET("/jobs", admin.Require(service.PermJobReview), admin.ListJobs)
+	adminGroup.POST("/jobs/:id/approve", admin.Require(service.PermJobReview), admin.ApproveJob)
+	adminGroup.POST("/jobs/:id/reject", admin.Require(service.PermJobReview), admin.RejectJob)
+
+	adminGroup.GET("/dictionaries/:kind", admin.Require(service.PermDictionaryWrite), admin.ListDictionary)
+	adminGroup.POST("/dictionaries/:kind", admin.Require(service.PermDictionaryWrite), admin.CreateDictionaryEntry)
+	adminGroup.PATCH("/dictionaries/:kind/:id", admin.Require(service.PermDictionaryWrite), admin.UpdateDictionaryEntry)
+	adminGroup.DELETE("/dictionaries/:kind/:id", admin.Require(service.PermDictionaryWrite), admin.DeleteDictionaryEntry)
+	adminGroup.POST("/dictionaries/:kind/migrate", admin.Require(service.PermDictionaryWrite), admin.MigrateDictionary)
+
+	adminGroup.GET("/regions/stats", admin.Require(service.PermUserRead), admin.RegionStats)
+	adminGroup.POST("/regions/migrate", admin.Require(service.PermUserWrite), admin.MigrateHometowns)
+
+	adminGroup.GET("/articles", admin.Require(service.PermArticleRead), admin.ListArticles)
+	adminGroup.GET("/articles/:id", admin.Require(service.PermArticleRead), admin.GetArticle)
+	adminGroup.POST("/articles", admin.Require(service.PermArticleWrite), admin.CreateArticle)
+	adminGroup.PATCH("/articles/:id", admin.Require(service.PermArticleWrite), admin.UpdateArticle)
+	adminGroup.DELETE("/articles/:id", admin.Require(service.PermArticleWrite), admin.DeleteArticle)
+	adminGroup.POST("/articles/:id/restore", admin.Require(service.PermArticleWrite), admin.RestoreArticle)
+	adminGroup.POST("/articles/:id/publish", admin.Require(service.PermArticleWrite), admin.PublishArticle)
+	adminGroup.POST("/articles/:id/offline", admin.Require(service.PermArticleWrite), admin.OfflineArticle)
+
+	adminGroup.GET("/permissions", admin.Require(service.PermRoleManage), admin.ListPermissions)
+	adminGroup.GET("/roles", admin.Require(service.PermRoleManage), admin.ListRoles)
+	adminGroup.POST("/roles", admin.Require(service.PermRoleManage), admin.CreateRole)
+	adminGroup.PATCH("/roles/:key", admin.Require(service.PermRoleManage), admin.UpdateRole)
+	adminGroup.DELETE("/roles/:key", admin.Require(service.PermRoleManage), admin.DeleteRole)
+	adminGroup.PUT("/users/:id/admin_roles", admin.Require(service.PermRoleManage), admin.SetUserAdminRoles)
+
+	adminGroup.GET("/audits", admin.Require(service.PermAuditRead), admin.ListAudits)
 }